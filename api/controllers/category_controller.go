@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type CategoryController struct {
+	categoryService api.CategoryService
+}
+
+func NewCategoryController(service api.CategoryService) *CategoryController {
+	return &CategoryController{categoryService: service}
+}
+
+func (cc *CategoryController) ListCategories(ctx *fiber.Ctx) error {
+	params := make(map[string]interface{})
+	if status := ctx.Query("status"); status != "" {
+		params["status"] = status
+	}
+
+	categories, err := cc.categoryService.ListCategories(ctx.UserContext(), params)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(categories)
+}
+
+func (cc *CategoryController) GetCategory(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	category, err := cc.categoryService.GetCategory(ctx.UserContext(), id)
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(category)
+}
+
+func (cc *CategoryController) CreateCategory(ctx *fiber.Ctx) error {
+	category := new(api.Category)
+	if err := ctx.BodyParser(category); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := cc.categoryService.CreateCategory(ctx.UserContext(), category); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(category)
+}
+
+func (cc *CategoryController) UpdateCategory(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	category := new(api.Category)
+	if err := ctx.BodyParser(category); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := cc.categoryService.UpdateCategory(ctx.UserContext(), id, category); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(category)
+}
+
+func (cc *CategoryController) DeleteCategory(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	if err := cc.categoryService.DeleteCategory(ctx.UserContext(), id); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// ListCategoryProducts dipakai storefront untuk satu bagian menu: kategori, sub-kategori dan produknya
+func (cc *CategoryController) ListCategoryProducts(ctx *fiber.Ctx) error {
+	page := ctx.QueryInt("page", 1)
+	limit := ctx.QueryInt("limit", 50)
+	params := map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+	}
+
+	category, products, err := cc.categoryService.ListCategoryProducts(ctx.UserContext(), ctx.Params("slug"), params)
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(fiber.Map{
+		"category": category,
+		"data":     products,
+		"page":     page,
+		"limit":    limit,
+	})
+}
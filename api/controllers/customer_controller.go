@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type CustomerController struct {
+	customerService api.CustomerService
+	loyaltyService  api.LoyaltyService
+}
+
+func NewCustomerController(cs api.CustomerService, ls api.LoyaltyService) *CustomerController {
+	return &CustomerController{customerService: cs, loyaltyService: ls}
+}
+
+func (cc *CustomerController) CreateCustomer(ctx *fiber.Ctx) error {
+	customer := new(api.Customer)
+	if err := ctx.BodyParser(customer); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := cc.customerService.CreateCustomer(ctx.UserContext(), customer); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(customer)
+}
+
+func (cc *CustomerController) GetCustomer(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	customer, err := cc.customerService.GetCustomer(ctx.UserContext(), id)
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Customer not found",
+		})
+	}
+
+	return ctx.JSON(customer)
+}
+
+func (cc *CustomerController) UpdateCustomer(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	customer := new(api.Customer)
+	if err := ctx.BodyParser(customer); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := cc.customerService.UpdateCustomer(ctx.UserContext(), id, customer); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(customer)
+}
+
+func (cc *CustomerController) ListCustomers(ctx *fiber.Ctx) error {
+	params := make(map[string]interface{})
+	params["page"] = ctx.QueryInt("page", 1)
+	params["limit"] = ctx.QueryInt("limit", 20)
+	for _, key := range []string{"phone", "search", "store_id"} {
+		if value := ctx.Query(key); value != "" {
+			params[key] = value
+		}
+	}
+
+	customers, err := cc.customerService.ListCustomers(ctx.UserContext(), params)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(customers)
+}
+
+// GetLoyaltyBalance mengembalikan saldo per program, bisa difilter per toko
+func (cc *CustomerController) GetLoyaltyBalance(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	balances, err := cc.loyaltyService.GetBalances(ctx.UserContext(), id, ctx.Query("store_id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(balances)
+}
+
+func (cc *CustomerController) GetLoyaltyHistory(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	params := make(map[string]interface{})
+	params["page"] = ctx.QueryInt("page", 1)
+	params["limit"] = ctx.QueryInt("limit", 20)
+	if programID := ctx.QueryInt("program_id"); programID > 0 {
+		params["program_id"] = programID
+	}
+
+	history, err := cc.loyaltyService.GetHistory(ctx.UserContext(), id, params)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(history)
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type DeliveryController struct {
+	deliveryService api.DeliveryService
+}
+
+func NewDeliveryController(service api.DeliveryService) *DeliveryController {
+	return &DeliveryController{deliveryService: service}
+}
+
+func (dc *DeliveryController) ListDeliveryZones(ctx *fiber.Ctx) error {
+	zones, err := dc.deliveryService.ListDeliveryZones(ctx.UserContext(), ctx.Params("store_id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(zones)
+}
+
+func (dc *DeliveryController) CreateDeliveryZone(ctx *fiber.Ctx) error {
+	zone := new(api.DeliveryZone)
+	if err := ctx.BodyParser(zone); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	zone.StoreID = ctx.Params("store_id")
+
+	if err := dc.deliveryService.CreateDeliveryZone(ctx.UserContext(), zone); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(zone)
+}
+
+func (dc *DeliveryController) UpdateDeliveryZone(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	zone := new(api.DeliveryZone)
+	if err := ctx.BodyParser(zone); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := dc.deliveryService.UpdateDeliveryZone(ctx.UserContext(), ctx.Params("store_id"), id, zone); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(zone)
+}
+
+func (dc *DeliveryController) DeleteDeliveryZone(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	if err := dc.deliveryService.DeleteDeliveryZone(ctx.UserContext(), ctx.Params("store_id"), id); err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// CheckDelivery mengecek apakah toko bisa mengantar ke koordinat pelanggan dan berapa ongkirnya
+func (dc *DeliveryController) CheckDelivery(ctx *fiber.Ctx) error {
+	lat, errLat := strconv.ParseFloat(ctx.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(ctx.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "lat and lng are required",
+		})
+	}
+
+	quote, err := dc.deliveryService.CheckDelivery(ctx.UserContext(), ctx.Params("store_id"), lat, lng, ctx.QueryInt("subtotal", 0))
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(quote)
+}
@@ -0,0 +1,21 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+)
+
+// serviceErrorStatus memetakan error dari service ke status HTTP
+func serviceErrorStatus(err error) int {
+	var validationErr *api.ValidationError
+	if errors.As(err, &validationErr) {
+		return fiber.StatusBadRequest
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/health"
+)
+
+type HealthController struct {
+	checker *health.Checker
+}
+
+func NewHealthController(checker *health.Checker) *HealthController {
+	return &HealthController{checker: checker}
+}
+
+// Liveness hanya menandakan proses masih melayani request; dependency tidak diperiksa
+// supaya database yang mati tidak membuat container di-restart
+func (c *HealthController) Liveness(ctx *fiber.Ctx) error {
+	return ctx.JSON(fiber.Map{"status": health.StatusOK})
+}
+
+// Readiness mengembalikan 503 jika ada dependency yang gagal atau server sedang berhenti
+func (c *HealthController) Readiness(ctx *fiber.Ctx) error {
+	report := c.checker.Check(ctx.UserContext())
+	if report.Status != health.StatusOK {
+		ctx.Status(fiber.StatusServiceUnavailable)
+	}
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+	return ctx.JSON(report)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	var dbErr error
+	checker := health.New(time.Second)
+	checker.Add("database", func(ctx context.Context) (string, error) { return "", dbErr })
+
+	app := fiber.New()
+	controller := NewHealthController(checker)
+	app.Get("/healthz", controller.Liveness)
+	app.Get("/readyz", controller.Readiness)
+
+	ready := func() (int, health.Report) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+		require.NoError(t, err)
+		var report health.Report
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		return resp.StatusCode, report
+	}
+
+	status, report := ready()
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+
+	dbErr = errors.New("connection refused")
+	status, report = ready()
+	assert.Equal(t, fiber.StatusServiceUnavailable, status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+
+	// Liveness tetap ok walaupun database mati
+	resp, err := app.Test(httptest.NewRequest("GET", "/healthz", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	dbErr = nil
+	checker.SetDraining()
+	status, report = ready()
+	assert.Equal(t, fiber.StatusServiceUnavailable, status)
+	assert.Equal(t, health.StatusDraining, report.Status)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type LoyaltyController struct {
+	loyaltyService api.LoyaltyService
+}
+
+func NewLoyaltyController(service api.LoyaltyService) *LoyaltyController {
+	return &LoyaltyController{loyaltyService: service}
+}
+
+func (lc *LoyaltyController) CreateProgram(ctx *fiber.Ctx) error {
+	program := new(api.LoyaltyProgram)
+	if err := ctx.BodyParser(program); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := lc.loyaltyService.CreateProgram(ctx.UserContext(), program); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(program)
+}
+
+func (lc *LoyaltyController) GetProgram(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	program, err := lc.loyaltyService.GetProgram(ctx.UserContext(), id)
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Loyalty program not found",
+		})
+	}
+
+	return ctx.JSON(program)
+}
+
+func (lc *LoyaltyController) UpdateProgram(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	program := new(api.LoyaltyProgram)
+	if err := ctx.BodyParser(program); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := lc.loyaltyService.UpdateProgram(ctx.UserContext(), id, program); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(program)
+}
+
+func (lc *LoyaltyController) DeleteProgram(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	if err := lc.loyaltyService.DeleteProgram(ctx.UserContext(), id); err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (lc *LoyaltyController) ListPrograms(ctx *fiber.Ctx) error {
+	params := make(map[string]interface{})
+	if status := ctx.Query("status"); status != "" {
+		params["status"] = status
+	}
+	if storeID := ctx.Query("store_id"); storeID != "" {
+		params["store_id"] = storeID
+	}
+
+	programs, err := lc.loyaltyService.ListPrograms(ctx.UserContext(), params)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(programs)
+}
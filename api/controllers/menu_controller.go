@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type MenuController struct {
+	menuService api.MenuService
+}
+
+func NewMenuController(service api.MenuService) *MenuController {
+	return &MenuController{menuService: service}
+}
+
+// GetStoreMenu mengembalikan toko, produk per kategori, topping dan level pedas dalam satu response
+func (mc *MenuController) GetStoreMenu(ctx *fiber.Ctx) error {
+	menu, err := mc.menuService.GetStoreMenu(ctx.UserContext(), ctx.Params("store_id"))
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(menu)
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+type MetricsController struct {
+	handler fiber.Handler
+	token   string
+}
+
+// NewMetricsController menyajikan handler Prometheus. Jika token diisi, scraper harus
+// mengirim Authorization: Bearer <token> karena metrik berisi pendapatan per toko.
+func NewMetricsController(handler http.Handler, token string) *MetricsController {
+	return &MetricsController{handler: adaptor.HTTPHandler(handler), token: token}
+}
+
+func (c *MetricsController) Metrics(ctx *fiber.Ctx) error {
+	if c.token != "" {
+		want := []byte("Bearer " + c.token)
+		if subtle.ConstantTimeCompare([]byte(ctx.Get(fiber.HeaderAuthorization)), want) != 1 {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "invalid metrics token",
+			})
+		}
+	}
+	return c.handler(ctx)
+}
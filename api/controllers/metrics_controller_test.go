@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsToken(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("up 1\n"))
+	})
+
+	open := fiber.New()
+	open.Get("/metrics", NewMetricsController(handler, "").Metrics)
+	resp, err := open.Test(httptest.NewRequest("GET", "/metrics", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	protected := fiber.New()
+	protected.Get("/metrics", NewMetricsController(handler, "s3cret").Metrics)
+	for header, want := range map[string]int{
+		"":              fiber.StatusUnauthorized,
+		"Bearer wrong":  fiber.StatusUnauthorized,
+		"Bearer s3cret": fiber.StatusOK,
+	} {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := protected.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, want, resp.StatusCode, header)
+	}
+}
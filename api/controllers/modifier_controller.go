@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type ModifierController struct {
+	modifierService api.ModifierService
+}
+
+func NewModifierController(service api.ModifierService) *ModifierController {
+	return &ModifierController{modifierService: service}
+}
+
+func (mc *ModifierController) GetModifierGroups(ctx *fiber.Ctx) error {
+	productID, err := strconv.Atoi(ctx.Params("productId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid product ID"})
+	}
+
+	groups, err := mc.modifierService.GetModifierGroups(ctx.UserContext(), productID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return ctx.JSON(groups)
+}
+
+// SetModifierGroups mengganti seluruh grup modifier milik produk
+func (mc *ModifierController) SetModifierGroups(ctx *fiber.Ctx) error {
+	productID, err := strconv.Atoi(ctx.Params("productId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid product ID"})
+	}
+
+	var groups []api.ModifierGroup
+	if err := ctx.BodyParser(&groups); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	saved, err := mc.modifierService.SetModifierGroups(ctx.UserContext(), productID, groups)
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return ctx.JSON(saved)
+}
+
+func (mc *ModifierController) ValidateConfiguration(ctx *fiber.Ctx) error {
+	productID, err := strconv.Atoi(ctx.Params("productId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid product ID"})
+	}
+
+	var body struct {
+		Modifiers []api.ModifierSelection `json:"modifiers"`
+	}
+	if err := ctx.BodyParser(&body); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	config, err := mc.modifierService.ValidateConfiguration(ctx.UserContext(), productID, body.Modifiers)
+	if err != nil {
+		if serviceErrorStatus(err) == fiber.StatusBadRequest {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"valid": false, "error": err.Error()})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return ctx.JSON(fiber.Map{"valid": true, "configuration": config})
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type OrderController struct {
+	orderService api.OrderService
+}
+
+func NewOrderController(service api.OrderService) *OrderController {
+	return &OrderController{orderService: service}
+}
+
+func (oc *OrderController) QuoteOrder(ctx *fiber.Ctx) error {
+	req := new(api.OrderRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	req.StoreID = ctx.Params("store_id")
+
+	quote, err := oc.orderService.QuoteOrder(ctx.UserContext(), req)
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(quote)
+}
+
+func (oc *OrderController) CreateOrder(ctx *fiber.Ctx) error {
+	req := new(api.OrderRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	req.StoreID = ctx.Params("store_id")
+
+	order, err := oc.orderService.CreateOrder(ctx.UserContext(), req)
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(order)
+}
+
+func (oc *OrderController) GetOrder(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID",
+		})
+	}
+
+	order, err := oc.orderService.GetOrder(ctx.UserContext(), id)
+	if err != nil || order.StoreID != ctx.Params("store_id") {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Order not found",
+		})
+	}
+
+	return ctx.JSON(order)
+}
+
+func (oc *OrderController) ListOrders(ctx *fiber.Ctx) error {
+	params := make(map[string]interface{})
+	params["page"] = ctx.QueryInt("page", 1)
+	params["limit"] = ctx.QueryInt("limit", 20)
+	if status := ctx.Query("status"); status != "" {
+		params["status"] = status
+	}
+
+	orders, err := oc.orderService.ListOrders(ctx.UserContext(), ctx.Params("store_id"), params)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(orders)
+}
+
+func (oc *OrderController) UpdateOrderStatus(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID",
+		})
+	}
+
+	var body struct {
+		Status string `json:"status"`
+	}
+	if err := ctx.BodyParser(&body); err != nil || body.Status == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	existing, err := oc.orderService.GetOrder(ctx.UserContext(), id)
+	if err != nil || existing.StoreID != ctx.Params("store_id") {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Order not found",
+		})
+	}
+
+	order, err := oc.orderService.UpdateOrderStatus(ctx.UserContext(), id, body.Status)
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(order)
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type PhotoController struct {
+	photoService api.PhotoService
+}
+
+func NewPhotoController(service api.PhotoService) *PhotoController {
+	return &PhotoController{photoService: service}
+}
+
+// UploadProductPhoto menerima multipart form dengan field "photo"
+func (pc *PhotoController) UploadProductPhoto(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid product ID",
+		})
+	}
+
+	return pc.withUpload(ctx, func(upload api.PhotoUpload) (interface{}, error) {
+		return pc.photoService.UploadProductPhoto(ctx.UserContext(), id, upload)
+	})
+}
+
+func (pc *PhotoController) UploadProductMasterPhoto(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	return pc.withUpload(ctx, func(upload api.PhotoUpload) (interface{}, error) {
+		return pc.photoService.UploadProductMasterPhoto(ctx.UserContext(), id, upload)
+	})
+}
+
+func (pc *PhotoController) withUpload(ctx *fiber.Ctx, save func(api.PhotoUpload) (interface{}, error)) error {
+	header, err := ctx.FormFile("photo")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "photo file is required",
+		})
+	}
+	file, err := header.Open()
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid photo file",
+		})
+	}
+	defer file.Close()
+
+	result, err := save(api.PhotoUpload{Filename: header.Filename, Size: header.Size, Content: file})
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(result)
+}
@@ -21,7 +21,7 @@ func (pc *ProductController) GetProducts(c *gin.Context) {
 	storeID := c.Query("store_id")
 	params := make(map[string]interface{})
 
-	products, err := pc.productService.ListProducts(storeID, params)
+	products, err := pc.productService.ListProducts(c.Request.Context(), storeID, params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -40,8 +40,8 @@ func (pc *ProductController) CreateProduct(ctx *fiber.Ctx) error {
 
 	product.StoreID = ctx.Params("store_id")
 
-	if err := pc.productService.CreateProduct(product); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	if err := pc.productService.CreateProduct(ctx.UserContext(), product); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -57,9 +57,9 @@ func (pc *ProductController) GetProduct(ctx *fiber.Ctx) error {
 		})
 	}
 
-	product, err := pc.productService.GetProduct(id)
+	product, err := pc.productService.GetProduct(ctx.UserContext(), id)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -89,8 +89,8 @@ func (pc *ProductController) UpdateProduct(ctx *fiber.Ctx) error {
 		})
 	}
 
-	if err := pc.productService.UpdateProduct(id, product); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	if err := pc.productService.UpdateProduct(ctx.UserContext(), id, product); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -107,7 +107,7 @@ func (pc *ProductController) DeleteProduct(ctx *fiber.Ctx) error {
 		})
 	}
 
-	if err := pc.productService.DeleteProduct(id); err != nil {
+	if err := pc.productService.DeleteProduct(ctx.UserContext(), id); err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -120,7 +120,7 @@ func (pc *ProductController) ListProducts(ctx *fiber.Ctx) error {
 	storeID := ctx.Params("store_id")
 	params := make(map[string]interface{})
 
-	products, err := pc.productService.ListProducts(storeID, params)
+	products, err := pc.productService.ListProducts(ctx.UserContext(), storeID, params)
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http/httptest"
@@ -11,6 +12,7 @@ import (
 	"github.com/seleraseblak/backend/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 // Mock ProductService
@@ -18,12 +20,12 @@ type mockProductService struct {
 	mock.Mock
 }
 
-func (m *mockProductService) CreateProduct(product *api.Product) error {
+func (m *mockProductService) CreateProduct(ctx context.Context, product *api.Product) error {
 	args := m.Called(product)
 	return args.Error(0)
 }
 
-func (m *mockProductService) GetProduct(id int) (*api.Product, error) {
+func (m *mockProductService) GetProduct(ctx context.Context, id int) (*api.Product, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -31,17 +33,17 @@ func (m *mockProductService) GetProduct(id int) (*api.Product, error) {
 	return args.Get(0).(*api.Product), args.Error(1)
 }
 
-func (m *mockProductService) UpdateProduct(id int, product *api.Product) error {
+func (m *mockProductService) UpdateProduct(ctx context.Context, id int, product *api.Product) error {
 	args := m.Called(id, product)
 	return args.Error(0)
 }
 
-func (m *mockProductService) DeleteProduct(id int) error {
+func (m *mockProductService) DeleteProduct(ctx context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *mockProductService) ListProducts(storeID string, params map[string]interface{}) ([]api.Product, error) {
+func (m *mockProductService) ListProducts(ctx context.Context, storeID string, params map[string]interface{}) ([]api.Product, error) {
 	args := m.Called(storeID, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -96,6 +98,8 @@ func TestCreateProduct(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// Ekspektasi subtest sebelumnya tidak boleh menjawab panggilan subtest ini
+			mockService.ExpectedCalls = nil
 			tt.mockBehavior()
 
 			jsonBody, _ := json.Marshal(tt.body)
@@ -144,7 +148,7 @@ func TestGetProduct(t *testing.T) {
 			productID:      "1",
 			expectedStatus: fiber.StatusNotFound,
 			mockBehavior: func() {
-				mockService.On("GetProduct", 1).Return(nil, fmt.Errorf("not found"))
+				mockService.On("GetProduct", 1).Return(nil, gorm.ErrRecordNotFound)
 			},
 		},
 		{
@@ -157,6 +161,8 @@ func TestGetProduct(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// Ekspektasi subtest sebelumnya tidak boleh menjawab panggilan subtest ini
+			mockService.ExpectedCalls = nil
 			tt.mockBehavior()
 
 			req := httptest.NewRequest("GET", fmt.Sprintf("/api/products/%s", tt.productID), nil)
@@ -191,7 +197,7 @@ func TestListProducts(t *testing.T) {
 			query:          "?page=1&limit=10",
 			expectedStatus: fiber.StatusOK,
 			mockBehavior: func() {
-				mockService.On("ListProducts", "store-123", mock.AnythingOfType("map[string]interface{}")).
+				mockService.On("ListProducts", "store-123", mock.AnythingOfType("map[string]interface {}")).
 					Return([]api.Product{{
 						ID:             1,
 						ProductMasterID: "pm-123",
@@ -207,7 +213,7 @@ func TestListProducts(t *testing.T) {
 			query:          "?page=1&limit=10",
 			expectedStatus: fiber.StatusInternalServerError,
 			mockBehavior: func() {
-				mockService.On("ListProducts", "store-123", mock.AnythingOfType("map[string]interface{}")).
+				mockService.On("ListProducts", "store-123", mock.AnythingOfType("map[string]interface {}")).
 					Return(nil, fmt.Errorf("service error"))
 			},
 		},
@@ -215,6 +221,8 @@ func TestListProducts(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// Ekspektasi subtest sebelumnya tidak boleh menjawab panggilan subtest ini
+			mockService.ExpectedCalls = nil
 			tt.mockBehavior()
 
 			req := httptest.NewRequest("GET", fmt.Sprintf("/api/stores/%s/products%s", tt.storeID, tt.query), nil)
@@ -266,6 +274,8 @@ func TestUpdateProduct(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// Ekspektasi subtest sebelumnya tidak boleh menjawab panggilan subtest ini
+			mockService.ExpectedCalls = nil
 			tt.mockBehavior()
 
 			jsonBody, _ := json.Marshal(tt.requestBody)
@@ -23,8 +23,8 @@ func (c *productMasterController) CreateProductMaster(ctx *fiber.Ctx) error {
 		})
 	}
 
-	if err := c.productMasterService.CreateProductMaster(product); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	if err := c.productMasterService.CreateProductMaster(ctx.UserContext(), product); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -34,7 +34,7 @@ func (c *productMasterController) CreateProductMaster(ctx *fiber.Ctx) error {
 
 func (c *productMasterController) GetProductMaster(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
-	product, err := c.productMasterService.GetProductMaster(id)
+	product, err := c.productMasterService.GetProductMaster(ctx.UserContext(), id)
 	if err != nil {
 		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Product master not found",
@@ -53,8 +53,8 @@ func (c *productMasterController) UpdateProductMaster(ctx *fiber.Ctx) error {
 		})
 	}
 
-	if err := c.productMasterService.UpdateProductMaster(id, product); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	if err := c.productMasterService.UpdateProductMaster(ctx.UserContext(), id, product); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -64,7 +64,7 @@ func (c *productMasterController) UpdateProductMaster(ctx *fiber.Ctx) error {
 
 func (c *productMasterController) DeleteProductMaster(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
-	if err := c.productMasterService.DeleteProductMaster(id); err != nil {
+	if err := c.productMasterService.DeleteProductMaster(ctx.UserContext(), id); err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -92,7 +92,7 @@ func (c *productMasterController) ListProductMasters(ctx *fiber.Ctx) error {
 		params["category"] = category
 	}
 
-	products, err := c.productMasterService.ListProductMasters(params)
+	products, err := c.productMasterService.ListProductMasters(ctx.UserContext(), params)
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
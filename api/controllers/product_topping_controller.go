@@ -24,7 +24,7 @@ func NewProductToppingController(service api.ProductToppingService) *ProductTopp
 // @Success 200 {array} api.ProductTopping
 // @Router /product-toppings [get]
 func (c *ProductToppingController) GetProductToppings(ctx *fiber.Ctx) error {
-    productToppings, err := c.service.GetProductToppings()
+    productToppings, err := c.service.GetProductToppings(ctx.UserContext())
     if err != nil {
         return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
     }
@@ -45,7 +45,7 @@ func (c *ProductToppingController) GetProductToppingsByProduct(ctx *fiber.Ctx) e
     if err != nil {
         return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid product ID"})
     }
-    productToppings, err := c.service.GetProductToppingsByProduct(productID)
+    productToppings, err := c.service.GetProductToppingsByProduct(ctx.UserContext(), productID)
     if err != nil {
         return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
     }
@@ -66,7 +66,7 @@ func (c *ProductToppingController) GetProductToppingsByTopping(ctx *fiber.Ctx) e
     if err != nil {
         return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid topping ID"})
     }
-    productToppings, err := c.service.GetProductToppingsByTopping(toppingID)
+    productToppings, err := c.service.GetProductToppingsByTopping(ctx.UserContext(), toppingID)
     if err != nil {
         return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
     }
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type PromotionController struct {
+	promotionService api.PromotionService
+}
+
+func NewPromotionController(service api.PromotionService) *PromotionController {
+	return &PromotionController{promotionService: service}
+}
+
+func (pc *PromotionController) CreatePromotion(ctx *fiber.Ctx) error {
+	promotion := new(api.Promotion)
+	if err := ctx.BodyParser(promotion); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := pc.promotionService.CreatePromotion(ctx.UserContext(), promotion); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(promotion)
+}
+
+func (pc *PromotionController) GetPromotion(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	promotion, err := pc.promotionService.GetPromotion(ctx.UserContext(), id)
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Promotion not found",
+		})
+	}
+
+	return ctx.JSON(promotion)
+}
+
+func (pc *PromotionController) UpdatePromotion(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	promotion := new(api.Promotion)
+	if err := ctx.BodyParser(promotion); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := pc.promotionService.UpdatePromotion(ctx.UserContext(), id, promotion); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(promotion)
+}
+
+func (pc *PromotionController) DeletePromotion(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	if err := pc.promotionService.DeletePromotion(ctx.UserContext(), id); err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (pc *PromotionController) ListPromotions(ctx *fiber.Ctx) error {
+	params := make(map[string]interface{})
+	if status := ctx.Query("status"); status != "" {
+		params["status"] = status
+	}
+	if storeID := ctx.Query("store_id"); storeID != "" {
+		params["store_id"] = storeID
+	}
+
+	promotions, err := pc.promotionService.ListPromotions(ctx.UserContext(), params)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(promotions)
+}
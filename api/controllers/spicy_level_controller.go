@@ -14,7 +14,7 @@ func NewSpicyLevelController(service api.SpicyLevelService) *SpicyLevelControlle
 }
 
 func (c *SpicyLevelController) GetSpicyLevels(ctx *fiber.Ctx) error {
-	spicyLevels, err := c.spicyLevelService.GetSpicyLevels()
+	spicyLevels, err := c.spicyLevelService.GetSpicyLevels(ctx.UserContext())
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -25,7 +25,7 @@ func (c *SpicyLevelController) GetSpicyLevels(ctx *fiber.Ctx) error {
 
 func (c *SpicyLevelController) GetSpicyLevel(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
-	spicyLevel, err := c.spicyLevelService.GetSpicyLevel(id)
+	spicyLevel, err := c.spicyLevelService.GetSpicyLevel(ctx.UserContext(), id)
 	if err != nil {
 		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Spicy level not found",
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/seleraseblak/backend/api"
 )
@@ -23,7 +25,7 @@ func (c *storeController) CreateStore(ctx *fiber.Ctx) error {
 		})
 	}
 
-	if err := c.storeService.CreateStore(store); err != nil {
+	if err := c.storeService.CreateStore(ctx.UserContext(), store); err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -34,7 +36,7 @@ func (c *storeController) CreateStore(ctx *fiber.Ctx) error {
 
 func (c *storeController) GetStore(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
-	store, err := c.storeService.GetStore(id)
+	store, err := c.storeService.GetStore(ctx.UserContext(), id)
 	if err != nil {
 		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Store not found",
@@ -53,7 +55,7 @@ func (c *storeController) UpdateStore(ctx *fiber.Ctx) error {
 		})
 	}
 
-	if err := c.storeService.UpdateStore(id, store); err != nil {
+	if err := c.storeService.UpdateStore(ctx.UserContext(), id, store); err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -64,7 +66,7 @@ func (c *storeController) UpdateStore(ctx *fiber.Ctx) error {
 
 func (c *storeController) DeleteStore(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
-	if err := c.storeService.DeleteStore(id); err != nil {
+	if err := c.storeService.DeleteStore(ctx.UserContext(), id); err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -74,7 +76,12 @@ func (c *storeController) DeleteStore(ctx *fiber.Ctx) error {
 }
 
 func (c *storeController) ListStores(ctx *fiber.Ctx) error {
-	stores, err := c.storeService.ListStores(map[string]interface{}{})
+	params := make(map[string]interface{})
+	if ctx.QueryBool("open_now") {
+		params["open_now"] = true
+	}
+
+	stores, err := c.storeService.ListStores(ctx.UserContext(), params)
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -91,3 +98,38 @@ func (c *storeController) ListStores(ctx *fiber.Ctx) error {
 
 	return ctx.JSON(activeStores)
 }
+
+func (c *storeController) ListNearbyStores(ctx *fiber.Ctx) error {
+	lat, errLat := strconv.ParseFloat(ctx.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(ctx.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "lat and lng are required",
+		})
+	}
+	radius := 5.0
+	if r := ctx.Query("radius"); r != "" {
+		parsed, err := strconv.ParseFloat(r, 64)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid radius",
+			})
+		}
+		radius = parsed
+	}
+
+	params := make(map[string]interface{})
+	params["limit"] = ctx.QueryInt("limit", 20)
+	if ctx.QueryBool("open_now") {
+		params["open_now"] = true
+	}
+
+	stores, err := c.storeService.ListNearbyStores(ctx.UserContext(), lat, lng, radius, params)
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(stores)
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http/httptest"
@@ -17,12 +18,12 @@ type mockStoreService struct {
     mock.Mock
 }
 
-func (m *mockStoreService) CreateStore(store *api.Store) error {
+func (m *mockStoreService) CreateStore(ctx context.Context, store *api.Store) error {
     args := m.Called(store)
     return args.Error(0)
 }
 
-func (m *mockStoreService) GetStore(id string) (*api.Store, error) {
+func (m *mockStoreService) GetStore(ctx context.Context, id string) (*api.Store, error) {
     args := m.Called(id)
     if args.Get(0) == nil {
         return nil, args.Error(1)
@@ -30,17 +31,17 @@ func (m *mockStoreService) GetStore(id string) (*api.Store, error) {
     return args.Get(0).(*api.Store), args.Error(1)
 }
 
-func (m *mockStoreService) UpdateStore(id string, store *api.Store) error {
+func (m *mockStoreService) UpdateStore(ctx context.Context, id string, store *api.Store) error {
     args := m.Called(id, store)
     return args.Error(0)
 }
 
-func (m *mockStoreService) DeleteStore(id string) error {
+func (m *mockStoreService) DeleteStore(ctx context.Context, id string) error {
     args := m.Called(id)
     return args.Error(0)
 }
 
-func (m *mockStoreService) ListStores(params map[string]interface{}) ([]api.Store, error) {
+func (m *mockStoreService) ListStores(ctx context.Context, params map[string]interface{}) ([]api.Store, error) {
     args := m.Called(params)
     if args.Get(0) == nil {
         return nil, args.Error(1)
@@ -48,6 +49,14 @@ func (m *mockStoreService) ListStores(params map[string]interface{}) ([]api.Stor
     return args.Get(0).([]api.Store), args.Error(1)
 }
 
+func (m *mockStoreService) ListNearbyStores(ctx context.Context, lat, lng, radiusKm float64, params map[string]interface{}) ([]api.Store, error) {
+    args := m.Called(lat, lng, radiusKm, params)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).([]api.Store), args.Error(1)
+}
+
 func TestCreateStore(t *testing.T) {
     app := fiber.New()
     mockService := new(mockStoreService)
@@ -88,6 +97,8 @@ func TestCreateStore(t *testing.T) {
 
     for _, tt := range tests {
         t.Run(tt.name, func(t *testing.T) {
+            // Ekspektasi subtest sebelumnya tidak boleh menjawab panggilan subtest ini
+            mockService.ExpectedCalls = nil
             tt.mockBehavior()
 
             jsonBody, _ := json.Marshal(tt.body)
@@ -140,6 +151,8 @@ func TestGetStore(t *testing.T) {
 
     for _, tt := range tests {
         t.Run(tt.name, func(t *testing.T) {
+            // Ekspektasi subtest sebelumnya tidak boleh menjawab panggilan subtest ini
+            mockService.ExpectedCalls = nil
             tt.mockBehavior()
 
             req := httptest.NewRequest("GET", fmt.Sprintf("/api/stores/%s", tt.storeID), nil)
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type StoreScheduleController struct {
+	scheduleService api.StoreScheduleService
+}
+
+func NewStoreScheduleController(service api.StoreScheduleService) *StoreScheduleController {
+	return &StoreScheduleController{scheduleService: service}
+}
+
+// SetOpeningHours mengganti seluruh jadwal mingguan toko
+func (sc *StoreScheduleController) SetOpeningHours(ctx *fiber.Ctx) error {
+	var hours []api.StoreOpeningHour
+	if err := ctx.BodyParser(&hours); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	saved, err := sc.scheduleService.SetOpeningHours(ctx.UserContext(), ctx.Params("store_id"), hours)
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(saved)
+}
+
+func (sc *StoreScheduleController) ListClosures(ctx *fiber.Ctx) error {
+	closures, err := sc.scheduleService.ListClosures(ctx.UserContext(), ctx.Params("store_id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(closures)
+}
+
+func (sc *StoreScheduleController) AddClosure(ctx *fiber.Ctx) error {
+	closure := new(api.StoreClosure)
+	if err := ctx.BodyParser(closure); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	closure.StoreID = ctx.Params("store_id")
+
+	if err := sc.scheduleService.AddClosure(ctx.UserContext(), closure); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(closure)
+}
+
+func (sc *StoreScheduleController) DeleteClosure(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	if err := sc.scheduleService.DeleteClosure(ctx.UserContext(), ctx.Params("store_id"), id); err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -14,7 +14,7 @@ func NewToppingController(ts api.ToppingService) *ToppingController {
 }
 
 func (tc *ToppingController) GetToppings(c *fiber.Ctx) error {
-    toppings, err := tc.toppingService.GetToppings()
+    toppings, err := tc.toppingService.GetToppings(c.UserContext())
     if err != nil {
         return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
             "error": err.Error(),
@@ -31,7 +31,7 @@ func (tc *ToppingController) GetTopping(c *fiber.Ctx) error {
         })
     }
 
-    topping, err := tc.toppingService.GetTopping(id)
+    topping, err := tc.toppingService.GetTopping(c.UserContext(), id)
     if err != nil {
         return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
             "error": "Topping not found",
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+)
+
+type VoucherController struct {
+	voucherService api.VoucherService
+	orderService   api.OrderService
+}
+
+func NewVoucherController(vs api.VoucherService, os api.OrderService) *VoucherController {
+	return &VoucherController{voucherService: vs, orderService: os}
+}
+
+func (vc *VoucherController) CreateVoucher(ctx *fiber.Ctx) error {
+	voucher := new(api.Voucher)
+	if err := ctx.BodyParser(voucher); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := vc.voucherService.CreateVoucher(ctx.UserContext(), voucher); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(voucher)
+}
+
+func (vc *VoucherController) GetVoucher(ctx *fiber.Ctx) error {
+	voucher, err := vc.voucherService.GetVoucherByCode(ctx.UserContext(), ctx.Params("code"))
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Voucher not found",
+		})
+	}
+
+	return ctx.JSON(voucher)
+}
+
+func (vc *VoucherController) UpdateVoucher(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	voucher := new(api.Voucher)
+	if err := ctx.BodyParser(voucher); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := vc.voucherService.UpdateVoucher(ctx.UserContext(), id, voucher); err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(voucher)
+}
+
+func (vc *VoucherController) DeleteVoucher(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID format",
+		})
+	}
+
+	if err := vc.voucherService.DeleteVoucher(ctx.UserContext(), id); err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (vc *VoucherController) ListVouchers(ctx *fiber.Ctx) error {
+	params := make(map[string]interface{})
+	if status := ctx.Query("status"); status != "" {
+		params["status"] = status
+	}
+	if promotionID := ctx.QueryInt("promotion_id"); promotionID > 0 {
+		params["promotion_id"] = promotionID
+	}
+
+	vouchers, err := vc.voucherService.ListVouchers(ctx.UserContext(), params)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(vouchers)
+}
+
+// ValidateVoucher menghitung ulang keranjang dengan kode voucher dan
+// menjelaskan apakah voucher bisa dipakai
+func (vc *VoucherController) ValidateVoucher(ctx *fiber.Ctx) error {
+	req := new(api.OrderRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	code := strings.ToUpper(strings.TrimSpace(req.VoucherCode))
+	if code == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "voucher_code is required",
+		})
+	}
+
+	quote, err := vc.orderService.QuoteOrder(ctx.UserContext(), req)
+	if err != nil {
+		return ctx.Status(serviceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	for _, applied := range quote.Applied {
+		if applied.VoucherCode == code {
+			return ctx.JSON(fiber.Map{
+				"voucher_code": code,
+				"valid":        true,
+				"amount":       applied.Amount,
+				"reason":       applied.Reason,
+				"quote":        quote,
+			})
+		}
+	}
+
+	reason := "voucher cannot be used"
+	for _, rejected := range quote.NotApplied {
+		if rejected.VoucherCode == code {
+			reason = rejected.Reason
+		}
+	}
+	return ctx.JSON(fiber.Map{
+		"voucher_code": code,
+		"valid":        false,
+		"reason":       reason,
+		"quote":        quote,
+	})
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/logging"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed menandai response yang diambil dari request sebelumnya
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency membuat request dengan header Idempotency-Key aman dikirim ulang. Response
+// pertama (selain 5xx) disimpan selama ttl dan dikirim lagi untuk request yang sama.
+// Key yang sama dengan request berbeda, atau saat request pertama masih berjalan, dijawab
+// 409. lockTimeout adalah batas waktu request terlama, setelah itu request pertama
+// dianggap gagal.
+func Idempotency(svc api.IdempotencyService, ttl, lockTimeout time.Duration) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		key := ctx.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return ctx.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Idempotency-Key must be at most 255 characters",
+			})
+		}
+
+		now := time.Now()
+		reservation := &api.IdempotencyKey{
+			Key:         strings.Clone(key),
+			RequestHash: requestHash(ctx),
+			LockedUntil: now.Add(lockTimeout),
+			ExpiresAt:   now.Add(ttl),
+			CreatedAt:   now,
+		}
+		existing, err := svc.Reserve(ctx.UserContext(), reservation)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return replay(ctx, existing, reservation.RequestHash)
+		}
+
+		if err := ctx.Next(); err != nil {
+			if err := ctx.App().ErrorHandler(ctx, err); err != nil {
+				_ = ctx.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// Tetap disimpan walaupun batas waktu request sudah lewat
+		storeCtx := context.WithoutCancel(ctx.UserContext())
+		status := ctx.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			// Error server belum tentu terulang, jadi client boleh mencoba lagi dengan key yang sama
+			if err := svc.Release(storeCtx, reservation.Key); err != nil {
+				logging.FromContext(storeCtx).Warn("releasing idempotency key failed", "error", err)
+			}
+			return nil
+		}
+		reservation.ResponseStatus = status
+		reservation.ContentType = string(ctx.Response().Header.ContentType())
+		reservation.ResponseBody = bytes.Clone(ctx.Response().Body())
+		if err := svc.Complete(storeCtx, reservation); err != nil {
+			logging.FromContext(storeCtx).Warn("saving idempotent response failed", "error", err)
+		}
+		return nil
+	}
+}
+
+// replay menjawab request ulang dari key yang sudah tersimpan
+func replay(ctx *fiber.Ctx, existing *api.IdempotencyKey, hash string) error {
+	if existing.RequestHash != hash {
+		return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Idempotency-Key has already been used for a different request",
+		})
+	}
+	if existing.ResponseStatus == 0 {
+		ctx.Set(fiber.HeaderRetryAfter, "1")
+		return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "A request with this Idempotency-Key is still being processed",
+		})
+	}
+	ctx.Set(HeaderIdempotentReplayed, "true")
+	if existing.ContentType != "" {
+		ctx.Set(fiber.HeaderContentType, existing.ContentType)
+	}
+	return ctx.Status(existing.ResponseStatus).Send(existing.ResponseBody)
+}
+
+// requestHash membedakan request dengan key yang sama. User ikut dihitung supaya key
+// milik user lain tidak bisa dipakai untuk membaca response-nya.
+func requestHash(ctx *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(ctx.Method() + " " + ctx.Path() + "\n"))
+	if userID, ok := ctx.Locals(UserIDKey).(string); ok {
+		h.Write([]byte(userID))
+	}
+	h.Write([]byte("\n"))
+	h.Write(ctx.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
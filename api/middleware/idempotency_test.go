@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryIdempotency meniru idempotencyService: Reserve atomik di bawah mutex
+type memoryIdempotency struct {
+	mu   sync.Mutex
+	keys map[string]api.IdempotencyKey
+}
+
+func (m *memoryIdempotency) Reserve(ctx context.Context, key *api.IdempotencyKey) (*api.IdempotencyKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.keys[key.Key]; ok && time.Now().Before(existing.ExpiresAt) {
+		return &existing, nil
+	}
+	m.keys[key.Key] = *key
+	return nil, nil
+}
+
+func (m *memoryIdempotency) Complete(ctx context.Context, key *api.IdempotencyKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys[key.Key] = *key
+	return nil
+}
+
+func (m *memoryIdempotency) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.keys, key)
+	return nil
+}
+
+func (m *memoryIdempotency) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotency(t *testing.T) {
+	svc := &memoryIdempotency{keys: make(map[string]api.IdempotencyKey)}
+	var created, failures atomic.Int32
+
+	app := fiber.New()
+	app.Use(Idempotency(svc, time.Hour, time.Minute))
+	app.Post("/stores/:store_id/orders", func(ctx *fiber.Ctx) error {
+		n := created.Add(1)
+		return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{"id": n})
+	})
+	app.Post("/flaky", func(ctx *fiber.Ctx) error {
+		if failures.Add(1) == 1 {
+			return fiber.NewError(fiber.StatusServiceUnavailable, "database down")
+		}
+		return ctx.SendStatus(fiber.StatusCreated)
+	})
+
+	send := func(path, key, body string) (*http.Response, string) {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		out, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(out)
+	}
+
+	resp, body := send("/stores/s1/orders", "order-1", `{"items":[1]}`)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.JSONEq(t, `{"id":1}`, body)
+
+	// Pengiriman ulang mendapat response yang sama tanpa membuat order baru
+	resp, body = send("/stores/s1/orders", "order-1", `{"items":[1]}`)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.JSONEq(t, `{"id":1}`, body)
+	assert.Equal(t, "true", resp.Header.Get(HeaderIdempotentReplayed))
+	assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, int32(1), created.Load())
+
+	resp, body = send("/stores/s1/orders", "order-1", `{"items":[2]}`)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	assert.Contains(t, body, "different request")
+	resp, _ = send("/stores/s2/orders", "order-1", `{"items":[1]}`)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode, "same key on another store")
+
+	// Tanpa header, setiap request diproses
+	send("/stores/s1/orders", "", `{"items":[1]}`)
+	send("/stores/s1/orders", "", `{"items":[1]}`)
+	assert.Equal(t, int32(3), created.Load())
+
+	// 5xx tidak disimpan sehingga client bisa mencoba lagi dengan key yang sama
+	resp, _ = send("/flaky", "flaky-1", `{}`)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	resp, _ = send("/flaky", "flaky-1", `{}`)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(HeaderIdempotentReplayed))
+}
+
+func TestIdempotencyConcurrentRetry(t *testing.T) {
+	svc := &memoryIdempotency{keys: make(map[string]api.IdempotencyKey)}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var created atomic.Int32
+
+	app := fiber.New()
+	app.Use(Idempotency(svc, time.Hour, time.Minute))
+	app.Post("/orders", func(ctx *fiber.Ctx) error {
+		close(started)
+		<-release
+		created.Add(1)
+		return ctx.Status(fiber.StatusCreated).SendString("created")
+	})
+	request := func() *http.Response {
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader(`{}`))
+		req.Header.Set(HeaderIdempotencyKey, "order-2")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	first := make(chan *http.Response)
+	go func() { first <- request() }()
+	<-started
+
+	// Request kedua datang saat yang pertama masih berjalan
+	resp := request()
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	close(release)
+	assert.Equal(t, fiber.StatusCreated, (<-first).StatusCode)
+	assert.Equal(t, fiber.StatusCreated, request().StatusCode)
+	assert.Equal(t, int32(1), created.Load())
+}
+
+func TestIdempotencyRejectsLongKeys(t *testing.T) {
+	app := fiber.New()
+	app.Use(Idempotency(&memoryIdempotency{keys: make(map[string]api.IdempotencyKey)}, time.Hour, time.Minute))
+	app.Post("/", func(ctx *fiber.Ctx) error { return ctx.SendStatus(fiber.StatusCreated) })
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set(HeaderIdempotencyKey, strings.Repeat("k", 256))
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/metrics"
+)
+
+// Label route untuk request yang tidak cocok dengan route mana pun
+const unmatchedRoute = "unmatched"
+
+// Metrics mencatat jumlah, durasi dan status setiap request per pola route
+func Metrics(m *metrics.Metrics) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+		own := ctx.Route()
+		done := m.RequestStarted()
+		defer done()
+
+		if err := ctx.Next(); err != nil {
+			if err := ctx.App().ErrorHandler(ctx, err); err != nil {
+				_ = ctx.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		route := routePattern(ctx, own)
+		if route == "" {
+			route = unmatchedRoute
+		}
+		// Method dari route, bukan ctx.Method(): string itu milik buffer fasthttp dan akan
+		// berubah setelah request selesai, padahal label disimpan registry
+		m.ObserveRequest(ctx.Route().Method, route, ctx.Response().StatusCode(), time.Since(start))
+		return nil
+	}
+}
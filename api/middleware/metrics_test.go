@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/seleraseblak/backend/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+	app := fiber.New()
+	app.Use(Metrics(m))
+	app.Get("/stores/:id", func(ctx *fiber.Ctx) error {
+		return ctx.SendString("ok")
+	})
+	app.Get("/boom", func(ctx *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusConflict, "conflict")
+	})
+	app.Get("/metrics", adaptor.HTTPHandler(m.Handler()))
+
+	for _, path := range []string{"/stores/1", "/stores/2", "/boom", "/nope"} {
+		_, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	out := string(body)
+	assert.Contains(t, out, `http_requests_total{method="GET",route="/stores/:id",status="200"} 2`)
+	assert.Contains(t, out, `http_requests_total{method="GET",route="/boom",status="409"} 1`)
+	assert.Contains(t, out, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.False(t, strings.Contains(out, `route="/stores/1"`), "paths must not become labels")
+}
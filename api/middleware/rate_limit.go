@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/logging"
+	"github.com/seleraseblak/backend/ratelimit"
+)
+
+// Header rate limit menurut draft IETF "RateLimit header fields for HTTP"
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// RateLimit membatasi request per client untuk satu kelompok route. Client adalah user
+// yang sudah login (ctx.Locals(UserIDKey)) atau IP-nya; setiap kelompok punya bucket
+// sendiri. Jika penyimpanan bucket gagal (misalnya Redis mati), request tetap dilayani.
+func RateLimit(limiter ratelimit.Limiter, group string, rule ratelimit.Rule) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		res, err := limiter.Allow(ctx.UserContext(), group+":"+clientKey(ctx), rule)
+		if err != nil {
+			logging.FromContext(ctx.UserContext()).Warn("rate limit unavailable", "group", group, "error", err)
+			return ctx.Next()
+		}
+
+		ctx.Set(HeaderRateLimitLimit, strconv.Itoa(res.Limit))
+		ctx.Set(HeaderRateLimitRemaining, strconv.Itoa(res.Remaining))
+		ctx.Set(HeaderRateLimitReset, strconv.Itoa(seconds(res.Reset)))
+		if !res.Allowed {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds(res.RetryAfter)))
+			return ctx.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Too many requests, please try again later",
+			})
+		}
+		return ctx.Next()
+	}
+}
+
+// WithVoucherCode menjalankan limit hanya jika body JSON berisi voucher_code, supaya quote
+// dan order tidak bisa dipakai menebak kode voucher tanpa terkena batas voucher
+func WithVoucherCode(limit fiber.Handler) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var body struct {
+			VoucherCode string `json:"voucher_code"`
+		}
+		if err := json.Unmarshal(ctx.Body(), &body); err == nil && strings.TrimSpace(body.VoucherCode) != "" {
+			return limit(ctx)
+		}
+		return ctx.Next()
+	}
+}
+
+// clientKey memakai user jika ada; selain itu IP (dari X-Forwarded-For jika proxy dipercaya)
+func clientKey(ctx *fiber.Ctx) string {
+	if userID, ok := ctx.Locals(UserIDKey).(string); ok && userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + ctx.IP()
+}
+
+// seconds membulatkan ke atas supaya client yang menunggu sesuai header tidak ditolak lagi
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string, rule ratelimit.Rule) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("redis: connection refused")
+}
+
+func TestRateLimit(t *testing.T) {
+	app := fiber.New()
+	limit := RateLimit(ratelimit.NewMemory(), "voucher", ratelimit.Rule{Limit: 2, Period: time.Minute})
+	app.Post("/vouchers/validate", limit, func(ctx *fiber.Ctx) error {
+		return ctx.SendString("ok")
+	})
+	app.Post("/me/vouchers/validate", func(ctx *fiber.Ctx) error {
+		ctx.Locals(UserIDKey, ctx.Get("X-User"))
+		return ctx.Next()
+	}, limit, func(ctx *fiber.Ctx) error {
+		return ctx.SendString("ok")
+	})
+
+	request := func(path, user string) *http.Response {
+		req := httptest.NewRequest("POST", path, nil)
+		req.Header.Set("X-User", user)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	first := request("/vouchers/validate", "")
+	assert.Equal(t, fiber.StatusOK, first.StatusCode)
+	assert.Equal(t, "2", first.Header.Get(HeaderRateLimitLimit))
+	assert.Equal(t, "1", first.Header.Get(HeaderRateLimitRemaining))
+	assert.Equal(t, "30", first.Header.Get(HeaderRateLimitReset))
+
+	assert.Equal(t, fiber.StatusOK, request("/vouchers/validate", "").StatusCode)
+	limited := request("/vouchers/validate", "")
+	assert.Equal(t, fiber.StatusTooManyRequests, limited.StatusCode)
+	assert.Equal(t, "30", limited.Header.Get(fiber.HeaderRetryAfter))
+	assert.Equal(t, "0", limited.Header.Get(HeaderRateLimitRemaining))
+
+	// User yang login punya bucket sendiri, terpisah dari IP-nya
+	assert.Equal(t, fiber.StatusOK, request("/me/vouchers/validate", "user-1").StatusCode)
+	assert.Equal(t, "1", request("/me/vouchers/validate", "user-2").Header.Get(HeaderRateLimitRemaining))
+}
+
+func TestRateLimitFailsOpen(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", RateLimit(failingLimiter{}, "read", ratelimit.Rule{Limit: 1, Period: time.Minute}), func(ctx *fiber.Ctx) error {
+		return ctx.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(HeaderRateLimitLimit))
+}
+
+func TestWithVoucherCode(t *testing.T) {
+	app := fiber.New()
+	limit := WithVoucherCode(RateLimit(ratelimit.NewMemory(), "voucher", ratelimit.Rule{Limit: 1, Period: time.Minute}))
+	app.Post("/stores/:store_id/orders/quote", limit, func(ctx *fiber.Ctx) error {
+		return ctx.SendString("ok")
+	})
+
+	request := func(body string) *http.Response {
+		req := httptest.NewRequest("POST", "/stores/s1/orders/quote", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// Tanpa voucher_code tidak memakai bucket voucher
+	for i := 0; i < 3; i++ {
+		resp := request(`{"items":[]}`)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(HeaderRateLimitLimit))
+	}
+
+	assert.Equal(t, fiber.StatusOK, request(`{"voucher_code":"HEMAT1"}`).StatusCode)
+	assert.Equal(t, fiber.StatusTooManyRequests, request(`{"voucher_code":"HEMAT2"}`).StatusCode)
+}
@@ -0,0 +1,116 @@
+// Package middleware berisi middleware Fiber yang dipakai semua route.
+package middleware
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/seleraseblak/backend/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	// Key ctx.Locals. UserIDKey diisi middleware auth; selama belum ada, user_id tidak dicatat.
+	RequestIDKey = "request_id"
+	UserIDKey    = "user_id"
+
+	maxRequestIDLength = 128
+)
+
+// RequestLogger memberi setiap request ID (dari header X-Request-ID jika valid, atau UUID baru),
+// mengirimkannya balik di response, menyimpan logger dengan request_id di ctx.UserContext()
+// untuk controller dan service, lalu mencatat satu baris log per request.
+func RequestLogger() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+		own := ctx.Route()
+
+		id := ctx.Get(HeaderRequestID)
+		if validRequestID(id) {
+			// Nilai header milik buffer fasthttp dan dipakai ulang setelah request selesai
+			id = strings.Clone(id)
+		} else {
+			id = uuid.NewString()
+		}
+		ctx.Set(HeaderRequestID, id)
+		ctx.Locals(RequestIDKey, id)
+		logger := slog.Default().With(slog.String("request_id", id))
+		// Jika tracing aktif, log bisa dicocokkan dengan trace-nya
+		if span := trace.SpanContextFromContext(ctx.UserContext()); span.IsValid() {
+			logger = logger.With(slog.String("trace_id", span.TraceID().String()))
+		}
+		ctx.SetUserContext(logging.WithLogger(ctx.UserContext(), logger))
+
+		// Error diubah jadi response di sini supaya status yang dicatat sama dengan yang dikirim
+		if err := ctx.Next(); err != nil {
+			if err := ctx.App().ErrorHandler(ctx, err); err != nil {
+				_ = ctx.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := ctx.Response().StatusCode()
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Method()),
+			slog.String("route", routePattern(ctx, own)),
+			slog.String("path", ctx.Path()),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		}
+		if storeID := storeID(ctx); storeID != "" {
+			attrs = append(attrs, slog.String("store_id", storeID))
+		}
+		if userID, ok := ctx.Locals(UserIDKey).(string); ok && userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logger.LogAttrs(ctx.UserContext(), level, "request", attrs...)
+		return nil
+	}
+}
+
+// routePattern mengembalikan pola route (/stores/:store_id/orders), bukan path aslinya,
+// supaya log mudah dikelompokkan. Jika route masih route middleware ini, request tidak
+// cocok dengan route mana pun dan tidak punya pola.
+func routePattern(ctx *fiber.Ctx, own *fiber.Route) string {
+	if route := ctx.Route(); route != own {
+		return route.Path
+	}
+	return ""
+}
+
+// storeID diambil dari parameter store_id, atau id pada route /stores/:id
+func storeID(ctx *fiber.Ctx) string {
+	if id := ctx.Params("store_id"); id != "" {
+		return id
+	}
+	if strings.HasPrefix(ctx.Route().Path, "/stores/:id") {
+		return ctx.Params("id")
+	}
+	return ""
+}
+
+// validRequestID menerima ID dari client hanya jika pendek dan berisi karakter aman,
+// supaya header tidak bisa menyisipkan baris atau data besar ke log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs mengganti slog.Default selama test dan mengembalikan baris log JSON-nya
+func captureLogs(t *testing.T) func() []map[string]interface{} {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	return func() []map[string]interface{} {
+		var lines []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			lines = append(lines, entry)
+		}
+		buf.Reset()
+		return lines
+	}
+}
+
+func newTestApp() *fiber.App {
+	app := fiber.New()
+	app.Use(RequestLogger())
+	stores := app.Group("/stores")
+	stores.Get("/:id", func(ctx *fiber.Ctx) error {
+		ctx.Locals(UserIDKey, "user-1")
+		return ctx.SendString("ok")
+	})
+	stores.Post("/:store_id/orders", func(ctx *fiber.Ctx) error {
+		// Log dari service memakai logger request lewat context
+		logging.FromContext(ctx.UserContext()).Info("creating order")
+		return fiber.NewError(fiber.StatusBadRequest, "invalid order")
+	})
+	app.Get("/boom", func(ctx *fiber.Ctx) error {
+		return errors.New("database down")
+	})
+	return app
+}
+
+func TestRequestLogger(t *testing.T) {
+	logs := captureLogs(t)
+	app := newTestApp()
+
+	req := httptest.NewRequest("GET", "/stores/store-1", nil)
+	req.Header.Set(HeaderRequestID, "abc-123")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "abc-123", resp.Header.Get(HeaderRequestID))
+
+	lines := logs()
+	require.Len(t, lines, 1)
+	assert.Equal(t, "request", lines[0]["msg"])
+	assert.Equal(t, "abc-123", lines[0]["request_id"])
+	assert.Equal(t, "GET", lines[0]["method"])
+	assert.Equal(t, "/stores/:id", lines[0]["route"])
+	assert.Equal(t, "/stores/store-1", lines[0]["path"])
+	assert.Equal(t, float64(200), lines[0]["status"])
+	assert.Equal(t, "store-1", lines[0]["store_id"])
+	assert.Equal(t, "user-1", lines[0]["user_id"])
+	assert.Contains(t, lines[0], "latency_ms")
+}
+
+func TestRequestLoggerPassesLoggerToHandlers(t *testing.T) {
+	logs := captureLogs(t)
+	app := newTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/stores/store-2/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	id := resp.Header.Get(HeaderRequestID)
+	assert.Len(t, id, 36, "request ID is generated when missing")
+
+	lines := logs()
+	require.Len(t, lines, 2)
+	assert.Equal(t, "creating order", lines[0]["msg"])
+	assert.Equal(t, id, lines[0]["request_id"])
+	assert.Equal(t, id, lines[1]["request_id"])
+	assert.Equal(t, "/stores/:store_id/orders", lines[1]["route"])
+	assert.Equal(t, float64(fiber.StatusBadRequest), lines[1]["status"])
+	assert.Equal(t, "store-2", lines[1]["store_id"])
+	assert.NotContains(t, lines[1], "user_id")
+}
+
+func TestRequestLoggerStatuses(t *testing.T) {
+	logs := captureLogs(t)
+	app := newTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/boom", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	lines := logs()
+	require.Len(t, lines, 1)
+	assert.Equal(t, "ERROR", lines[0]["level"])
+	assert.Equal(t, float64(500), lines[0]["status"])
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/unknown", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	lines = logs()
+	require.Len(t, lines, 1)
+	assert.Equal(t, "", lines[0]["route"])
+	assert.Equal(t, "/unknown", lines[0]["path"])
+}
+
+func TestRequestLoggerRejectsInvalidRequestID(t *testing.T) {
+	captureLogs(t)
+	app := newTestApp()
+
+	for _, id := range []string{"has space", "line\x0bbreak", strings.Repeat("a", 129)} {
+		req := httptest.NewRequest("GET", "/stores/1", nil)
+		req.Header.Set(HeaderRequestID, id)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.NotEqual(t, id, resp.Header.Get(HeaderRequestID))
+		assert.Len(t, resp.Header.Get(HeaderRequestID), 36)
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const timeoutKey = "timeout_context"
+
+// Timeout memberi batas waktu pada ctx.UserContext(), sehingga query GORM yang memakai
+// context itu dibatalkan saat batasnya lewat. Request yang gagal karena batas waktu
+// dijawab 504. Timeout yang dipasang pada route menggantikan timeout dari group,
+// jadi route lambat (misalnya upload foto) bisa diberi batas lebih panjang.
+func Timeout(d time.Duration) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		// WithoutCancel membuang batas dari luar tapi tetap membawa logger dan span
+		timeoutCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx.UserContext()), d)
+		defer cancel()
+		ctx.SetUserContext(timeoutCtx)
+		ctx.Locals(timeoutKey, timeoutCtx)
+
+		err := ctx.Next()
+		if current, _ := ctx.Locals(timeoutKey).(context.Context); current != timeoutCtx {
+			// Sudah ditangani Timeout milik route
+			return err
+		}
+		if !errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			return err
+		}
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) && fiberErr.Code < fiber.StatusInternalServerError {
+			return err
+		}
+		if err == nil && ctx.Response().StatusCode() < fiber.StatusInternalServerError {
+			// Handler sempat selesai tepat sebelum batas waktu
+			return nil
+		}
+		ctx.Response().ResetBody()
+		return ctx.Status(fiber.StatusGatewayTimeout).JSON(fiber.Map{
+			"error": "Request timed out",
+		})
+	}
+}
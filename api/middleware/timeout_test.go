@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowQuery meniru query yang berhenti saat context dibatalkan
+func slowQuery(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	app := fiber.New()
+	api := app.Group("/", Timeout(20*time.Millisecond))
+	handler := func(ctx *fiber.Ctx) error {
+		if err := slowQuery(ctx.UserContext(), 50*time.Millisecond); err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return ctx.SendString("ok")
+	}
+	api.Get("/slow", handler)
+	api.Post("/upload", Timeout(time.Second), handler)
+	api.Get("/fast", func(ctx *fiber.Ctx) error {
+		return ctx.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/slow", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusGatewayTimeout, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"error":"Request timed out"}`, string(body))
+
+	// Timeout route menggantikan timeout group yang lebih pendek
+	resp, err = app.Test(httptest.NewRequest("POST", "/upload", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/fast", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestTimeoutKeepsClientErrors(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", Timeout(time.Millisecond), func(ctx *fiber.Ctx) error {
+		<-ctx.UserContext().Done()
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing membuat span server untuk setiap request. Header traceparent/tracestate (W3C)
+// dari client atau gateway dipakai sebagai induk, dan context berisi span disimpan di
+// ctx.UserContext() sehingga span service dan SQL menjadi anaknya.
+func Tracing() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		own := ctx.Route()
+		parent := otel.GetTextMapPropagator().Extract(ctx.UserContext(), headerCarrier{ctx})
+		// Atribut disimpan sampai span diekspor, jadi string dari buffer request disalin
+		spanCtx, span := tracing.Tracer().Start(parent, own.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(own.Method),
+				semconv.URLPath(strings.Clone(ctx.Path())),
+				semconv.UserAgentOriginal(strings.Clone(ctx.Get(fiber.HeaderUserAgent))),
+				semconv.ClientAddress(ctx.IP()),
+			))
+		defer span.End()
+		ctx.SetUserContext(spanCtx)
+
+		if err := ctx.Next(); err != nil {
+			if err := ctx.App().ErrorHandler(ctx, err); err != nil {
+				_ = ctx.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// Nama span memakai pola route supaya request sejenis terkelompok
+		if route := routePattern(ctx, own); route != "" {
+			span.SetName(own.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		status := ctx.Response().StatusCode()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+		return nil
+	}
+}
+
+// headerCarrier membaca header request Fiber untuk propagator OpenTelemetry
+type headerCarrier struct {
+	ctx *fiber.Ctx
+}
+
+// Get menyalin nilai karena tracestate ikut disimpan span
+func (c headerCarrier) Get(key string) string {
+	return strings.Clone(c.ctx.Get(key))
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c.ctx.Request().Header.Set(key, value)
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0)
+	c.ctx.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	logs := captureLogs(t)
+
+	app := fiber.New()
+	app.Use(Tracing(), RequestLogger())
+	var handlerSpan trace.SpanContext
+	app.Get("/stores/:id", func(ctx *fiber.Ctx) error {
+		handlerSpan = trace.SpanContextFromContext(ctx.UserContext())
+		return ctx.SendString("ok")
+	})
+	app.Get("/boom", func(ctx *fiber.Ctx) error {
+		return errors.New("database down")
+	})
+
+	req := httptest.NewRequest("GET", "/stores/store-1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest("GET", "/boom", nil))
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "GET /stores/:id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Equal(t, span.SpanContext(), handlerSpan, "handler receives the request span")
+	assert.Contains(t, span.Attributes(), semconv.HTTPRoute("/stores/:id"))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+
+	assert.Equal(t, "GET /boom", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.False(t, spans[1].Parent().IsValid(), "requests without traceparent start a new trace")
+
+	lines := logs()
+	require.Len(t, lines, 2)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", lines[0]["trace_id"])
+}
@@ -1,52 +1,171 @@
 package api
 
 import (
+	"context"
+	"io"
 	"time"
 )
 
 // Request/Response structures
 type Store struct {
-	ID           string `json:"id" gorm:"primaryKey;type:uuid;column:id"`
+	ID           string `json:"id" gorm:"primaryKey;type:uuid;column:id;default:gen_random_uuid()"`
 	StoreName    string `json:"store_name" gorm:"column:store_name"`
 	StoreAddress string `json:"store_address" gorm:"column:store_address"`
 	StorePhone   string `json:"store_phone" gorm:"column:store_phone"`
 	Status       string `json:"status" gorm:"column:status"`
-	DateCreated  string `json:"date_created" gorm:"column:date_created"`
-	DateUpdated  string `json:"date_updated" gorm:"column:date_updated"`
+	DateCreated  string `json:"date_created" gorm:"column:date_created;default:now()"`
+	DateUpdated  string `json:"date_updated" gorm:"column:date_updated;default:null"`
+	// Zona waktu IANA toko, mis. Asia/Jakarta (WIB), Asia/Makassar (WITA), Asia/Jayapura (WIT)
+	Timezone  string   `json:"timezone" gorm:"column:timezone"`
+	Latitude  *float64 `json:"latitude" gorm:"column:latitude"`
+	Longitude *float64 `json:"longitude" gorm:"column:longitude"`
+	// Hanya terisi pada pencarian toko terdekat
+	DistanceKm   *float64           `json:"distance_km,omitempty" gorm:"column:distance_km;->;-:migration"`
+	OpeningHours []StoreOpeningHour `json:"opening_hours,omitempty" gorm:"foreignKey:StoreID"`
+	Closures     []StoreClosure     `json:"closures,omitempty" gorm:"foreignKey:StoreID"`
+	OpenNow      bool               `json:"open_now" gorm:"-"`
+	NextOpenAt   *time.Time         `json:"next_open_at" gorm:"-"`
 }
 
 func (Store) TableName() string {
 	return "Store"
 }
 
+// StoreOpeningHour adalah satu shift di hari tertentu (0 = Minggu). Jam dalam zona waktu toko,
+// ClosesAt lebih kecil dari OpensAt berarti shift melewati tengah malam.
+type StoreOpeningHour struct {
+	ID        int    `json:"id" gorm:"primaryKey;column:id"`
+	StoreID   string `json:"store_id" gorm:"column:store_id;type:uuid"`
+	DayOfWeek int    `json:"day_of_week" gorm:"column:day_of_week"`
+	OpensAt   string `json:"opens_at" gorm:"column:opens_at"`
+	ClosesAt  string `json:"closes_at" gorm:"column:closes_at"`
+}
+
+func (StoreOpeningHour) TableName() string {
+	return "Store_Opening_Hour"
+}
+
+// StoreClosure adalah pengecualian jadwal untuk satu tanggal (YYYY-MM-DD, zona waktu toko).
+// Tanpa OpensAt/ClosesAt toko tutup seharian, jika diisi toko buka dengan jam khusus.
+type StoreClosure struct {
+	ID       int    `json:"id" gorm:"primaryKey;column:id"`
+	StoreID  string `json:"store_id" gorm:"column:store_id;type:uuid"`
+	Date     string `json:"date" gorm:"column:date"`
+	OpensAt  string `json:"opens_at,omitempty" gorm:"column:opens_at"`
+	ClosesAt string `json:"closes_at,omitempty" gorm:"column:closes_at"`
+	Reason   string `json:"reason" gorm:"column:reason"`
+}
+
+func (StoreClosure) TableName() string {
+	return "Store_Closure"
+}
+
+// Tipe zona pengantaran
+const (
+	DeliveryZoneRadius  = "radius"
+	DeliveryZonePolygon = "polygon"
+)
+
+// DeliveryZone adalah area pengantaran toko. Zona radius berlaku sampai MaxDistanceKm dari toko,
+// zona polygon berlaku untuk titik di dalam Polygon ([lat, lng] berurutan).
+// Ongkir = BaseFee + PerKmFee per km (dibulatkan ke atas), gratis jika subtotal mencapai FreeAbove.
+type DeliveryZone struct {
+	ID            int          `json:"id" gorm:"primaryKey;column:id"`
+	StoreID       string       `json:"store_id" gorm:"column:store_id;type:uuid"`
+	Name          string       `json:"name" gorm:"column:name"`
+	Type          string       `json:"type" gorm:"column:type"`
+	MaxDistanceKm float64      `json:"max_distance_km" gorm:"column:max_distance_km"`
+	Polygon       [][2]float64 `json:"polygon,omitempty" gorm:"serializer:json;column:polygon"`
+	BaseFee       int          `json:"base_fee" gorm:"column:base_fee"`
+	PerKmFee      int          `json:"per_km_fee" gorm:"column:per_km_fee"`
+	FreeAbove     int          `json:"free_above" gorm:"column:free_above"`
+	MinOrder      int          `json:"min_order" gorm:"column:min_order"`
+	Priority      int          `json:"priority" gorm:"column:priority"`
+	Status        string       `json:"status" gorm:"column:status"`
+}
+
+func (DeliveryZone) TableName() string {
+	return "Delivery_Zone"
+}
+
+// DeliveryQuote adalah hasil pengecekan pengantaran ke satu titik
+type DeliveryQuote struct {
+	Deliverable bool    `json:"deliverable"`
+	Reason      string  `json:"reason,omitempty"`
+	ZoneID      int     `json:"zone_id,omitempty"`
+	ZoneName    string  `json:"zone_name,omitempty"`
+	DistanceKm  float64 `json:"distance_km"`
+	Fee         int     `json:"fee"`
+	MinOrder    int     `json:"min_order"`
+	FreeAbove   int     `json:"free_above,omitempty"`
+}
+
+// ProductMaster.Category berisi nama kategori untuk client lama dan selalu disamakan dengan Categories
 type ProductMaster struct {
-	ID          string   `json:"id" gorm:"primaryKey;type:uuid;column:id"`
-	ProductName string   `json:"product_name" gorm:"column:product_name"`
-	Category    []string `json:"category" gorm:"serializer:json;column:category"`
-	SKU         string   `json:"sku" gorm:"column:sku"`
-	Description string   `json:"description" gorm:"column:description"`
-	Status      string   `json:"status" gorm:"column:status"`
-	UserCreated string   `json:"user_created" gorm:"column:user_created;type:uuid"`
-	UserUpdated string   `json:"user_updated" gorm:"column:user_updated;type:uuid"`
-	Price       int      `json:"price" gorm:"column:price"`
+	ID          string         `json:"id" gorm:"primaryKey;type:uuid;column:id;default:gen_random_uuid()"`
+	ProductName string         `json:"product_name" gorm:"column:product_name"`
+	Category    []string       `json:"category" gorm:"serializer:json;column:category"`
+	Categories  []Category     `json:"categories,omitempty" gorm:"many2many:Product_Master_Category;joinForeignKey:ProductMasterID;joinReferences:CategoryID"`
+	CategoryIDs []int          `json:"category_ids,omitempty" gorm:"-"`
+	SKU         string         `json:"sku" gorm:"column:sku"`
+	Description string         `json:"description" gorm:"column:description"`
+	Status      string         `json:"status" gorm:"column:status"`
+	UserCreated string         `json:"user_created" gorm:"column:user_created;type:uuid;default:null"`
+	UserUpdated string         `json:"user_updated" gorm:"column:user_updated;type:uuid;default:null"`
+	Price       int            `json:"price" gorm:"column:price"`
+	Photo       string         `json:"photo" gorm:"column:photo"`
+	Photos      *ProductPhotos `json:"photos,omitempty" gorm:"serializer:json;column:photos"`
+	// Hanya terisi saat pencarian; highlight berupa HTML dengan kata yang cocok dalam <mark>
+	SearchRank           float64 `json:"search_rank,omitempty" gorm:"column:search_rank;->;-:migration"`
+	HighlightName        string  `json:"highlight_name,omitempty" gorm:"column:highlight_name;->;-:migration"`
+	HighlightDescription string  `json:"highlight_description,omitempty" gorm:"column:highlight_description;->;-:migration"`
 }
 
 func (ProductMaster) TableName() string {
 	return "Product_Master"
 }
 
+// Category adalah kategori menu bertingkat; Children hanya terisi pada daftar pohon
+type Category struct {
+	ID           int        `json:"id" gorm:"primaryKey;column:id"`
+	ParentID     *int       `json:"parent_id" gorm:"column:parent_id"`
+	Name         string     `json:"name" gorm:"column:name"`
+	Slug         string     `json:"slug" gorm:"column:slug"`
+	Icon         string     `json:"icon" gorm:"column:icon"`
+	DisplayOrder int        `json:"display_order" gorm:"column:display_order"`
+	Status       string     `json:"status" gorm:"column:status"`
+	Children     []Category `json:"children,omitempty" gorm:"-"`
+}
+
+func (Category) TableName() string {
+	return "Category"
+}
+
+type ProductMasterCategory struct {
+	ProductMasterID string `gorm:"primaryKey;column:product_master_id;type:uuid"`
+	CategoryID      int    `gorm:"primaryKey;column:category_id"`
+}
+
+func (ProductMasterCategory) TableName() string {
+	return "Product_Master_Category"
+}
+
 type Product struct {
-	ID              int              `json:"id" gorm:"primaryKey;column:id"`
-	ProductMasterID string           `json:"product_master_id" gorm:"column:product_master_id;type:uuid"`
-	ProductMaster   ProductMaster    `json:"product_master" gorm:"foreignKey:ProductMasterID"`
-	StoreID         string           `json:"store_id" gorm:"column:store_id;type:uuid"`
-	Price           float64          `json:"price" gorm:"column:price"`
-	StockQuantity   int              `json:"stock_quantity" gorm:"column:stock_quantity"`
-	IsActive        bool             `json:"is_active" gorm:"column:is_active"`
-	Status          string           `json:"status" gorm:"column:status"`
-	Photo           string           `json:"photo" gorm:"column:photo"`
-	ProductToppings []ProductTopping `json:"-" gorm:"foreignKey:Product_id"`
-	Toppings        []Topping        `json:"toppings" gorm:"-"`
+	ID              int               `json:"id" gorm:"primaryKey;column:id"`
+	ProductMasterID string            `json:"product_master_id" gorm:"column:product_master_id;type:uuid"`
+	ProductMaster   ProductMaster     `json:"product_master" gorm:"foreignKey:ProductMasterID"`
+	StoreID         string            `json:"store_id" gorm:"column:store_id;type:uuid"`
+	Price           float64           `json:"price" gorm:"column:price"`
+	StockQuantity   int               `json:"stock_quantity" gorm:"column:stock_quantity"`
+	IsActive        bool              `json:"is_active" gorm:"column:is_active"`
+	Status          string            `json:"status" gorm:"column:status"`
+	Photo           string            `json:"photo" gorm:"column:photo"`
+	Photos          *ProductPhotos    `json:"photos,omitempty" gorm:"serializer:json;column:photos"`
+	Type            string            `json:"type" gorm:"column:type;default:single"`
+	ProductToppings []ProductTopping  `json:"-" gorm:"foreignKey:Product_id"`
+	Toppings        []Topping         `json:"toppings" gorm:"-"`
+	Components      []BundleComponent `json:"components,omitempty" gorm:"foreignKey:BundleProductID"`
+	ModifierGroups  []ModifierGroup   `json:"modifier_groups,omitempty" gorm:"foreignKey:ProductID"`
 }
 
 func (Product) TableName() string {
@@ -66,6 +185,106 @@ func (p *Product) AfterFind() error {
 	return nil
 }
 
+// Tipe produk
+const (
+	ProductTypeSingle = "single"
+	ProductTypeBundle = "bundle"
+)
+
+// BundleComponent adalah satu slot di dalam paket. Slot tetap mengisi ProductID,
+// slot pilihan (mis. "pilih minuman") mengisi ChoiceName dan ChoiceProductIDs.
+// Quantity adalah jumlah per satu paket.
+type BundleComponent struct {
+	ID               int       `json:"id" gorm:"primaryKey;column:id"`
+	BundleProductID  int       `json:"bundle_product_id" gorm:"column:bundle_product_id"`
+	ProductID        *int      `json:"product_id" gorm:"column:product_id"`
+	Product          *Product  `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	ChoiceName       string    `json:"choice_name,omitempty" gorm:"column:choice_name"`
+	ChoiceProductIDs []int     `json:"choice_product_ids,omitempty" gorm:"serializer:json;column:choice_product_ids"`
+	Choices          []Product `json:"choices,omitempty" gorm:"-"`
+	Quantity         int       `json:"quantity" gorm:"column:quantity"`
+}
+
+func (BundleComponent) TableName() string {
+	return "Bundle_Component"
+}
+
+// Jenis grup modifier
+const (
+	ModifierKindBroth      = "broth"
+	ModifierKindNoodle     = "noodle"
+	ModifierKindTopping    = "topping"
+	ModifierKindSpicyLevel = "spicy_level"
+	ModifierKindOther      = "other"
+)
+
+// ModifierGroup adalah sekumpulan pilihan pada produk dengan aturan jumlah pilihan,
+// mis. "Kuah" (tepat 1) atau "Topping" (0 sampai 5). MaxSelect 0 berarti tidak dibatasi.
+type ModifierGroup struct {
+	ID        int              `json:"id" gorm:"primaryKey;column:id"`
+	ProductID int              `json:"product_id" gorm:"column:product_id"`
+	Name      string           `json:"name" gorm:"column:name"`
+	Kind      string           `json:"kind" gorm:"column:kind"`
+	MinSelect int              `json:"min_select" gorm:"column:min_select"`
+	MaxSelect int              `json:"max_select" gorm:"column:max_select"`
+	SortOrder int              `json:"sort_order" gorm:"column:sort_order"`
+	Options   []ModifierOption `json:"options" gorm:"foreignKey:GroupID"`
+}
+
+func (ModifierGroup) TableName() string {
+	return "Modifier_Group"
+}
+
+// ModifierOption adalah satu pilihan di grup. ToppingID dan SpicyLevelID
+// menghubungkan pilihan ke data topping dan level pedas yang sudah ada.
+// Price kosong berarti harga diambil dari topping/level pedas tersebut.
+type ModifierOption struct {
+	ID           int     `json:"id" gorm:"primaryKey;column:id"`
+	GroupID      int     `json:"group_id" gorm:"column:group_id"`
+	Name         string  `json:"name" gorm:"column:name"`
+	Price        *int    `json:"price" gorm:"column:price"`
+	IsDefault    bool    `json:"is_default" gorm:"column:is_default"`
+	ToppingID    *int    `json:"topping_id,omitempty" gorm:"column:topping_id"`
+	SpicyLevelID *string `json:"spicy_level_id,omitempty" gorm:"column:spicy_level_id"`
+	SortOrder    int     `json:"sort_order" gorm:"column:sort_order"`
+	Status       string  `json:"status" gorm:"column:status"`
+}
+
+func (ModifierOption) TableName() string {
+	return "Modifier_Option"
+}
+
+// UnitPrice adalah harga pilihan; pilihan tanpa harga dihitung gratis
+func (o ModifierOption) UnitPrice() int {
+	if o.Price == nil {
+		return 0
+	}
+	return *o.Price
+}
+
+type ModifierSelection struct {
+	OptionID int `json:"option_id"`
+	Quantity int `json:"quantity"`
+}
+
+// SelectedModifier adalah pilihan yang sudah divalidasi beserta harganya
+type SelectedModifier struct {
+	GroupID   int    `json:"group_id"`
+	GroupName string `json:"group_name"`
+	Kind      string `json:"kind"`
+	OptionID  int    `json:"option_id"`
+	Name      string `json:"name"`
+	Price     int    `json:"price"`
+	Quantity  int    `json:"quantity"`
+	IsDefault bool   `json:"is_default"`
+}
+
+type ModifierConfiguration struct {
+	ProductID     int                `json:"product_id"`
+	Selections    []SelectedModifier `json:"selections"`
+	ModifierTotal int                `json:"modifier_total"`
+}
+
 // Tambahkan tabel junction
 type ProductTopping struct {
 	ID        int     `gorm:"primaryKey;column:id"`
@@ -98,6 +317,15 @@ const (
 	StatusArchived  = "archived"
 )
 
+// ValidationError menandakan input dari client tidak valid, controller membalas dengan 400
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
 // Tambahkan struct Topping
 type Topping struct {
 	ID          int       `json:"id" gorm:"primaryKey;column:id"`
@@ -120,57 +348,532 @@ type SpicyLevel struct {
 	Price int    `json:"price"`
 }
 
+// Menu adalah seluruh menu satu outlet untuk storefront dalam satu response
+type Menu struct {
+	Store    Store         `json:"store"`
+	Sections []MenuSection `json:"sections"`
+}
+
+// MenuSection adalah satu bagian menu per kategori. Category nil berisi produk tanpa kategori.
+type MenuSection struct {
+	Category *Category     `json:"category"`
+	Products []MenuProduct `json:"products"`
+}
+
+// MenuProduct adalah produk beserta level pedas yang berlaku untuknya:
+// pilihan di grup modifier jika ada, selain itu daftar level pedas umum
+type MenuProduct struct {
+	Product
+	SpicyLevels []SpicyLevel `json:"spicy_levels"`
+}
+
 // Tambahkan interface service
 type SpicyLevelService interface {
-	GetSpicyLevels() ([]SpicyLevel, error)
-	GetSpicyLevel(id string) (*SpicyLevel, error)
+	GetSpicyLevels(ctx context.Context) ([]SpicyLevel, error)
+	GetSpicyLevel(ctx context.Context, id string) (*SpicyLevel, error)
 }
 
 // Service interfaces
 type StoreService interface {
-	CreateStore(store *Store) error
-	GetStore(id string) (*Store, error)
-	UpdateStore(id string, store *Store) error
-	DeleteStore(id string) error
-	ListStores(params map[string]interface{}) ([]Store, error)
+	CreateStore(ctx context.Context, store *Store) error
+	GetStore(ctx context.Context, id string) (*Store, error)
+	UpdateStore(ctx context.Context, id string, store *Store) error
+	DeleteStore(ctx context.Context, id string) error
+	ListStores(ctx context.Context, params map[string]interface{}) ([]Store, error)
+	ListNearbyStores(ctx context.Context, lat, lng, radiusKm float64, params map[string]interface{}) ([]Store, error)
+}
+
+type StoreScheduleService interface {
+	SetOpeningHours(ctx context.Context, storeID string, hours []StoreOpeningHour) ([]StoreOpeningHour, error)
+	ListClosures(ctx context.Context, storeID string) ([]StoreClosure, error)
+	AddClosure(ctx context.Context, closure *StoreClosure) error
+	DeleteClosure(ctx context.Context, storeID string, id int) error
+}
+
+type DeliveryService interface {
+	ListDeliveryZones(ctx context.Context, storeID string) ([]DeliveryZone, error)
+	CreateDeliveryZone(ctx context.Context, zone *DeliveryZone) error
+	UpdateDeliveryZone(ctx context.Context, storeID string, id int, zone *DeliveryZone) error
+	DeleteDeliveryZone(ctx context.Context, storeID string, id int) error
+	CheckDelivery(ctx context.Context, storeID string, lat, lng float64, subtotal int) (*DeliveryQuote, error)
+}
+
+// PhotoUpload adalah file foto yang diunggah lewat multipart form
+type PhotoUpload struct {
+	Filename string
+	Size     int64
+	Content  io.Reader
+}
+
+// ProductPhotos berisi URL foto asli dan varian ukurannya. Photo tetap berisi URL foto asli.
+type ProductPhotos struct {
+	Original  PhotoVariant `json:"original"`
+	Thumbnail PhotoVariant `json:"thumbnail"`
+	Medium    PhotoVariant `json:"medium"`
+	Large     PhotoVariant `json:"large"`
+}
+
+// PhotoVariant adalah satu ukuran foto dalam format JPEG dan WebP
+type PhotoVariant struct {
+	URL    string `json:"url"`
+	WebP   string `json:"webp,omitempty"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type PhotoService interface {
+	UploadProductPhoto(ctx context.Context, productID int, upload PhotoUpload) (*Product, error)
+	UploadProductMasterPhoto(ctx context.Context, id string, upload PhotoUpload) (*ProductMaster, error)
 }
 
 type ProductService interface {
-	CreateProduct(product *Product) error
-	GetProduct(id int) (*Product, error)
-	UpdateProduct(id int, product *Product) error
-	DeleteProduct(id int) error
-	ListProducts(storeID string, params map[string]interface{}) ([]Product, error)
+	CreateProduct(ctx context.Context, product *Product) error
+	GetProduct(ctx context.Context, id int) (*Product, error)
+	UpdateProduct(ctx context.Context, id int, product *Product) error
+	DeleteProduct(ctx context.Context, id int) error
+	ListProducts(ctx context.Context, storeID string, params map[string]interface{}) ([]Product, error)
 }
 
 type ProductMasterService interface {
-	CreateProductMaster(product *ProductMaster) error
-	GetProductMaster(id string) (*ProductMaster, error)
-	UpdateProductMaster(id string, product *ProductMaster) error
-	DeleteProductMaster(id string) error
-	ListProductMasters(params map[string]interface{}) ([]ProductMaster, error)
+	CreateProductMaster(ctx context.Context, product *ProductMaster) error
+	GetProductMaster(ctx context.Context, id string) (*ProductMaster, error)
+	UpdateProductMaster(ctx context.Context, id string, product *ProductMaster) error
+	DeleteProductMaster(ctx context.Context, id string) error
+	ListProductMasters(ctx context.Context, params map[string]interface{}) ([]ProductMaster, error)
+}
+
+type MenuService interface {
+	GetStoreMenu(ctx context.Context, storeID string) (*Menu, error)
+}
+
+type CategoryService interface {
+	ListCategories(ctx context.Context, params map[string]interface{}) ([]Category, error)
+	GetCategory(ctx context.Context, id int) (*Category, error)
+	CreateCategory(ctx context.Context, category *Category) error
+	UpdateCategory(ctx context.Context, id int, category *Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	// ListCategoryProducts mengembalikan kategori beserta produk di kategori itu dan sub-kategorinya
+	ListCategoryProducts(ctx context.Context, slug string, params map[string]interface{}) (*Category, []ProductMaster, error)
+}
+
+// CatalogExport adalah katalog pusat (kategori, topping, product master) dalam satu dokumen
+// untuk dipindahkan antar lingkungan
+type CatalogExport struct {
+	Version        int             `json:"version"`
+	ExportedAt     time.Time       `json:"exported_at"`
+	Categories     []Category      `json:"categories"`
+	Toppings       []Topping       `json:"toppings"`
+	ProductMasters []ProductMaster `json:"product_masters"`
+}
+
+type CatalogImportResult struct {
+	Categories     int `json:"categories"`
+	Toppings       int `json:"toppings"`
+	ProductMasters int `json:"product_masters"`
+}
+
+type CatalogService interface {
+	ExportCatalog(ctx context.Context) (*CatalogExport, error)
+	ImportCatalog(ctx context.Context, catalog *CatalogExport) (*CatalogImportResult, error)
 }
 
 type UserStoreService interface {
-	AssignUserToStore(userStore *UserStore) error
-	RemoveUserFromStore(userID, storeID string) error
-	GetUserStores(userID string) ([]UserStore, error)
-	GetStoreUsers(storeID string) ([]UserStore, error)
+	AssignUserToStore(ctx context.Context, userStore *UserStore) error
+	RemoveUserFromStore(ctx context.Context, userID, storeID string) error
+	GetUserStores(ctx context.Context, userID string) ([]UserStore, error)
+	GetStoreUsers(ctx context.Context, storeID string) ([]UserStore, error)
 }
 
 type ToppingService interface {
-	GetToppings() ([]Topping, error)
-	GetTopping(id int) (*Topping, error)
-	CreateTopping(topping *Topping) error
-	UpdateTopping(id int, topping *Topping) error
-	DeleteTopping(id int) error
+	GetToppings(ctx context.Context) ([]Topping, error)
+	GetTopping(ctx context.Context, id int) (*Topping, error)
+	CreateTopping(ctx context.Context, topping *Topping) error
+	UpdateTopping(ctx context.Context, id int, topping *Topping) error
+	DeleteTopping(ctx context.Context, id int) error
+}
+
+type ModifierService interface {
+	GetModifierGroups(ctx context.Context, productID int) ([]ModifierGroup, error)
+	SetModifierGroups(ctx context.Context, productID int, groups []ModifierGroup) ([]ModifierGroup, error)
+	ValidateConfiguration(ctx context.Context, productID int, selections []ModifierSelection) (*ModifierConfiguration, error)
 }
 
 // Tambahkan interface service
 type ProductToppingService interface {
-	GetProductToppings() ([]ProductTopping, error)
-	GetProductToppingsByProduct(productID int) ([]ProductTopping, error)
-	GetProductToppingsByTopping(toppingID int) ([]ProductTopping, error)
-	CreateProductTopping(productTopping *ProductTopping) error
-	DeleteProductTopping(productID, toppingID int) error
+	GetProductToppings(ctx context.Context) ([]ProductTopping, error)
+	GetProductToppingsByProduct(ctx context.Context, productID int) ([]ProductTopping, error)
+	GetProductToppingsByTopping(ctx context.Context, toppingID int) ([]ProductTopping, error)
+	CreateProductTopping(ctx context.Context, productTopping *ProductTopping) error
+	DeleteProductTopping(ctx context.Context, productID, toppingID int) error
+}
+
+// Tipe order
+const (
+	OrderTypeDineIn   = "dine_in"
+	OrderTypePickup   = "pickup"
+	OrderTypeDelivery = "delivery"
+)
+
+// Status order
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusCompleted = "completed"
+	OrderStatusCancelled = "cancelled"
+)
+
+// Tipe promosi
+const (
+	PromotionTypePercentage  = "percentage"
+	PromotionTypeFixedAmount = "fixed_amount"
+	PromotionTypeFreeTopping = "free_topping"
+	PromotionTypeBundle      = "bundle"
+)
+
+type Order struct {
+	ID              int    `json:"id" gorm:"primaryKey;column:id"`
+	StoreID         string `json:"store_id" gorm:"column:store_id;type:uuid"`
+	UserID          string `json:"user_id" gorm:"column:user_id;type:uuid;default:null"`
+	CustomerID      *int   `json:"customer_id" gorm:"column:customer_id"`
+	OrderType       string `json:"order_type" gorm:"column:order_type"`
+	Status          string `json:"status" gorm:"column:status"`
+	VoucherCode     string `json:"voucher_code" gorm:"column:voucher_code"`
+	Subtotal        int    `json:"subtotal" gorm:"column:subtotal"`
+	Discount        int    `json:"discount" gorm:"column:discount"`
+	LoyaltyDiscount int    `json:"loyalty_discount" gorm:"column:loyalty_discount"`
+	DeliveryFee     int    `json:"delivery_fee" gorm:"column:delivery_fee"`
+	Total           int    `json:"total" gorm:"column:total"`
+	// Hanya diisi untuk order delivery
+	DeliveryZoneID    *int               `json:"delivery_zone_id,omitempty" gorm:"column:delivery_zone_id"`
+	DeliveryAddress   string             `json:"delivery_address,omitempty" gorm:"column:delivery_address"`
+	DeliveryLatitude  *float64           `json:"delivery_latitude,omitempty" gorm:"column:delivery_latitude"`
+	DeliveryLongitude *float64           `json:"delivery_longitude,omitempty" gorm:"column:delivery_longitude"`
+	DeliveryDistance  float64            `json:"delivery_distance_km,omitempty" gorm:"column:delivery_distance_km"`
+	Items             []OrderItem        `json:"items" gorm:"foreignKey:OrderID"`
+	Promotions        []AppliedPromotion `json:"promotions" gorm:"serializer:json;column:promotions"`
+	Loyalty           []AppliedLoyalty   `json:"loyalty" gorm:"serializer:json;column:loyalty"`
+	DateCreated       time.Time          `json:"date_created" gorm:"column:date_created;autoCreateTime"`
+	DateUpdated       time.Time          `json:"date_updated" gorm:"column:date_updated;autoUpdateTime"`
+}
+
+func (Order) TableName() string {
+	return "Order"
+}
+
+type OrderItem struct {
+	ID           int                  `json:"id" gorm:"primaryKey;column:id"`
+	OrderID      int                  `json:"order_id" gorm:"column:order_id"`
+	ProductID    int                  `json:"product_id" gorm:"column:product_id"`
+	ProductName  string               `json:"product_name" gorm:"column:product_name"`
+	Quantity     int                  `json:"quantity" gorm:"column:quantity"`
+	UnitPrice    int                  `json:"unit_price" gorm:"column:unit_price"`
+	SpicyLevelID string               `json:"spicy_level_id" gorm:"column:spicy_level_id"`
+	SpicyPrice   int                  `json:"spicy_price" gorm:"column:spicy_price"`
+	Toppings     []OrderItemTopping   `json:"toppings" gorm:"serializer:json;column:toppings"`
+	Components   []OrderItemComponent `json:"components,omitempty" gorm:"serializer:json;column:components"`
+	Modifiers    []SelectedModifier   `json:"modifiers,omitempty" gorm:"serializer:json;column:modifiers"`
+	LineTotal    int                  `json:"line_total" gorm:"column:line_total"`
+}
+
+// OrderItemComponent adalah isi satu unit paket yang dipesan
+type OrderItemComponent struct {
+	ProductID int    `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+}
+
+func (OrderItem) TableName() string {
+	return "Order_Item"
+}
+
+type OrderItemTopping struct {
+	ToppingID int    `json:"topping_id"`
+	Name      string `json:"name"`
+	Price     int    `json:"price"`
+}
+
+// OrderRequest adalah payload untuk quote maupun pembuatan order
+type OrderRequest struct {
+	StoreID       string                 `json:"store_id"`
+	UserID        string                 `json:"user_id"`
+	CustomerID    int                    `json:"customer_id"`
+	OrderType     string                 `json:"order_type"`
+	VoucherCode   string                 `json:"voucher_code"`
+	Items         []OrderRequestItem     `json:"items"`
+	RedeemLoyalty []LoyaltyRedeemRequest `json:"redeem_loyalty"`
+	// Wajib untuk order delivery
+	DeliveryAddress   string   `json:"delivery_address"`
+	DeliveryLatitude  *float64 `json:"delivery_latitude"`
+	DeliveryLongitude *float64 `json:"delivery_longitude"`
+}
+
+// LoyaltyRedeemRequest meminta penukaran poin/stamp dari satu program saat checkout
+type LoyaltyRedeemRequest struct {
+	ProgramID int `json:"program_id"`
+	Points    int `json:"points"`
+}
+
+type OrderRequestItem struct {
+	ProductID    int    `json:"product_id"`
+	Quantity     int    `json:"quantity"`
+	SpicyLevelID string `json:"spicy_level_id"`
+	ToppingIDs   []int  `json:"topping_ids"`
+	// Pilihan untuk slot pilihan di produk paket
+	BundleSelections []BundleSelection `json:"bundle_selections"`
+	// Pilihan modifier untuk produk yang punya grup modifier
+	Modifiers []ModifierSelection `json:"modifiers"`
+}
+
+type BundleSelection struct {
+	ComponentID int `json:"component_id"`
+	ProductID   int `json:"product_id"`
+	Quantity    int `json:"quantity"`
+}
+
+// OrderQuote adalah hasil perhitungan harga sebelum order disimpan
+type OrderQuote struct {
+	Items           []OrderItem         `json:"items"`
+	Subtotal        int                 `json:"subtotal"`
+	Discount        int                 `json:"discount"`
+	LoyaltyDiscount int                 `json:"loyalty_discount"`
+	DeliveryFee     int                 `json:"delivery_fee"`
+	Total           int                 `json:"total"`
+	Delivery        *DeliveryQuote      `json:"delivery,omitempty"`
+	Applied         []AppliedPromotion  `json:"applied_promotions"`
+	NotApplied      []RejectedPromotion `json:"not_applied_promotions"`
+	Loyalty         []AppliedLoyalty    `json:"loyalty"`
+}
+
+type Promotion struct {
+	ID                int        `json:"id" gorm:"primaryKey;column:id"`
+	Name              string     `json:"name" gorm:"column:name"`
+	Description       string     `json:"description" gorm:"column:description"`
+	Type              string     `json:"type" gorm:"column:type"`
+	Value             int        `json:"value" gorm:"column:value"`
+	MaxDiscount       int        `json:"max_discount" gorm:"column:max_discount"`
+	MinSubtotal       int        `json:"min_subtotal" gorm:"column:min_subtotal"`
+	ToppingID         int        `json:"topping_id" gorm:"column:topping_id"`
+	ProductIDs        []int      `json:"product_ids" gorm:"serializer:json;column:product_ids"`
+	BuyQuantity       int        `json:"buy_quantity" gorm:"column:buy_quantity"`
+	GetQuantity       int        `json:"get_quantity" gorm:"column:get_quantity"`
+	StoreIDs          []string   `json:"store_ids" gorm:"serializer:json;column:store_ids"`
+	StartsAt          *time.Time `json:"starts_at" gorm:"column:starts_at"`
+	EndsAt            *time.Time `json:"ends_at" gorm:"column:ends_at"`
+	DaysOfWeek        []int      `json:"days_of_week" gorm:"serializer:json;column:days_of_week"`
+	DailyStart        string     `json:"daily_start" gorm:"column:daily_start"`
+	DailyEnd          string     `json:"daily_end" gorm:"column:daily_end"`
+	UsageLimit        int        `json:"usage_limit" gorm:"column:usage_limit"`
+	UsageLimitPerUser int        `json:"usage_limit_per_user" gorm:"column:usage_limit_per_user"`
+	RequiresVoucher   bool       `json:"requires_voucher" gorm:"column:requires_voucher"`
+	Exclusive         bool       `json:"exclusive" gorm:"column:exclusive"`
+	Priority          int        `json:"priority" gorm:"column:priority"`
+	Status            string     `json:"status" gorm:"column:status"`
+}
+
+func (Promotion) TableName() string {
+	return "Promotion"
+}
+
+type Voucher struct {
+	ID                int        `json:"id" gorm:"primaryKey;column:id"`
+	Code              string     `json:"code" gorm:"column:code;uniqueIndex"`
+	PromotionID       int        `json:"promotion_id" gorm:"column:promotion_id"`
+	Promotion         Promotion  `json:"promotion" gorm:"foreignKey:PromotionID"`
+	UsageLimit        int        `json:"usage_limit" gorm:"column:usage_limit"`
+	UsageLimitPerUser int        `json:"usage_limit_per_user" gorm:"column:usage_limit_per_user"`
+	StartsAt          *time.Time `json:"starts_at" gorm:"column:starts_at"`
+	EndsAt            *time.Time `json:"ends_at" gorm:"column:ends_at"`
+	Status            string     `json:"status" gorm:"column:status"`
+}
+
+func (Voucher) TableName() string {
+	return "Voucher"
+}
+
+// PromotionRedemption mencatat pemakaian promosi per order, dipakai untuk batas pemakaian
+type PromotionRedemption struct {
+	ID          int       `json:"id" gorm:"primaryKey;column:id"`
+	PromotionID int       `json:"promotion_id" gorm:"column:promotion_id"`
+	VoucherID   *int      `json:"voucher_id" gorm:"column:voucher_id"`
+	OrderID     int       `json:"order_id" gorm:"column:order_id"`
+	UserID      string    `json:"user_id" gorm:"column:user_id;type:uuid;default:null"`
+	Amount      int       `json:"amount" gorm:"column:amount"`
+	DateCreated time.Time `json:"date_created" gorm:"column:date_created;autoCreateTime"`
+}
+
+func (PromotionRedemption) TableName() string {
+	return "Promotion_Redemption"
+}
+
+type AppliedPromotion struct {
+	PromotionID int    `json:"promotion_id"`
+	Name        string `json:"name"`
+	VoucherCode string `json:"voucher_code,omitempty"`
+	Amount      int    `json:"amount"`
+	Reason      string `json:"reason"`
+}
+
+type RejectedPromotion struct {
+	PromotionID int    `json:"promotion_id"`
+	Name        string `json:"name"`
+	VoucherCode string `json:"voucher_code,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+type OrderService interface {
+	QuoteOrder(ctx context.Context, req *OrderRequest) (*OrderQuote, error)
+	CreateOrder(ctx context.Context, req *OrderRequest) (*Order, error)
+	GetOrder(ctx context.Context, id int) (*Order, error)
+	UpdateOrderStatus(ctx context.Context, id int, status string) (*Order, error)
+	ListOrders(ctx context.Context, storeID string, params map[string]interface{}) ([]Order, error)
+}
+
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, promotion *Promotion) error
+	GetPromotion(ctx context.Context, id int) (*Promotion, error)
+	UpdatePromotion(ctx context.Context, id int, promotion *Promotion) error
+	DeletePromotion(ctx context.Context, id int) error
+	ListPromotions(ctx context.Context, params map[string]interface{}) ([]Promotion, error)
+}
+
+type VoucherService interface {
+	CreateVoucher(ctx context.Context, voucher *Voucher) error
+	GetVoucherByCode(ctx context.Context, code string) (*Voucher, error)
+	UpdateVoucher(ctx context.Context, id int, voucher *Voucher) error
+	DeleteVoucher(ctx context.Context, id int) error
+	ListVouchers(ctx context.Context, params map[string]interface{}) ([]Voucher, error)
+}
+
+// Tipe program loyalty
+const (
+	LoyaltyTypePoints = "points"
+	LoyaltyTypeStamps = "stamps"
+)
+
+// Tipe transaksi loyalty
+const (
+	LoyaltyTxEarn   = "earn"
+	LoyaltyTxRedeem = "redeem"
+	LoyaltyTxExpire = "expire"
+	LoyaltyTxRevert = "revert"
+	LoyaltyTxRefund = "refund"
+)
+
+// Customer adalah pelanggan yang mengumpulkan poin/stamp. UserID terisi jika
+// pelanggan juga punya akun (directus user).
+type Customer struct {
+	ID          int       `json:"id" gorm:"primaryKey;column:id"`
+	UserID      *string   `json:"user_id" gorm:"column:user_id;type:uuid"`
+	Name        string    `json:"name" gorm:"column:name"`
+	Phone       string    `json:"phone" gorm:"column:phone;uniqueIndex"`
+	Email       string    `json:"email" gorm:"column:email"`
+	StoreID     *string   `json:"store_id" gorm:"column:store_id;type:uuid"`
+	DateCreated time.Time `json:"date_created" gorm:"column:date_created;autoCreateTime"`
+}
+
+func (Customer) TableName() string {
+	return "Customer"
+}
+
+// LoyaltyProgram berlaku untuk satu toko, atau semua toko jika StoreID kosong
+type LoyaltyProgram struct {
+	ID              int     `json:"id" gorm:"primaryKey;column:id"`
+	Name            string  `json:"name" gorm:"column:name"`
+	Type            string  `json:"type" gorm:"column:type"`
+	StoreID         *string `json:"store_id" gorm:"column:store_id;type:uuid"`
+	AmountPerPoint  int     `json:"amount_per_point" gorm:"column:amount_per_point"`
+	PointValue      int     `json:"point_value" gorm:"column:point_value"`
+	MinRedeemPoints int     `json:"min_redeem_points" gorm:"column:min_redeem_points"`
+	StampProductIDs []int   `json:"stamp_product_ids" gorm:"serializer:json;column:stamp_product_ids"`
+	StampsRequired  int     `json:"stamps_required" gorm:"column:stamps_required"`
+	ExpiryDays      int     `json:"expiry_days" gorm:"column:expiry_days"`
+	Status          string  `json:"status" gorm:"column:status"`
+}
+
+func (LoyaltyProgram) TableName() string {
+	return "Loyalty_Program"
+}
+
+// LoyaltyTransaction adalah buku besar poin/stamp. Remaining dipakai pada transaksi
+// earn untuk menghitung saldo yang belum ditukar atau kadaluarsa (FIFO).
+type LoyaltyTransaction struct {
+	ID          int        `json:"id" gorm:"primaryKey;column:id"`
+	CustomerID  int        `json:"customer_id" gorm:"column:customer_id"`
+	ProgramID   int        `json:"program_id" gorm:"column:program_id"`
+	OrderID     *int       `json:"order_id" gorm:"column:order_id"`
+	Type        string     `json:"type" gorm:"column:type"`
+	Points      int        `json:"points" gorm:"column:points"`
+	Remaining   int        `json:"-" gorm:"column:remaining"`
+	ExpiresAt   *time.Time `json:"expires_at" gorm:"column:expires_at"`
+	DateCreated time.Time  `json:"date_created" gorm:"column:date_created;autoCreateTime"`
+}
+
+func (LoyaltyTransaction) TableName() string {
+	return "Loyalty_Transaction"
+}
+
+type LoyaltyBalance struct {
+	ProgramID      int        `json:"program_id"`
+	ProgramName    string     `json:"program_name"`
+	Type           string     `json:"type"`
+	Points         int        `json:"points"`
+	NextExpiry     *time.Time `json:"next_expiry"`
+	ExpiringPoints int        `json:"expiring_points"`
+	RewardsReady   int        `json:"rewards_ready"`
+}
+
+type AppliedLoyalty struct {
+	ProgramID int    `json:"program_id"`
+	Name      string `json:"name"`
+	Points    int    `json:"points"`
+	Amount    int    `json:"amount"`
+}
+
+type CustomerService interface {
+	CreateCustomer(ctx context.Context, customer *Customer) error
+	GetCustomer(ctx context.Context, id int) (*Customer, error)
+	UpdateCustomer(ctx context.Context, id int, customer *Customer) error
+	ListCustomers(ctx context.Context, params map[string]interface{}) ([]Customer, error)
+}
+
+type LoyaltyService interface {
+	CreateProgram(ctx context.Context, program *LoyaltyProgram) error
+	GetProgram(ctx context.Context, id int) (*LoyaltyProgram, error)
+	UpdateProgram(ctx context.Context, id int, program *LoyaltyProgram) error
+	DeleteProgram(ctx context.Context, id int) error
+	ListPrograms(ctx context.Context, params map[string]interface{}) ([]LoyaltyProgram, error)
+	GetBalances(ctx context.Context, customerID int, storeID string) ([]LoyaltyBalance, error)
+	GetHistory(ctx context.Context, customerID int, params map[string]interface{}) ([]LoyaltyTransaction, error)
+}
+
+// IdempotencyKey menyimpan request POST yang dikirim dengan header Idempotency-Key beserta
+// response-nya, sehingga request yang dikirim ulang mendapat response yang sama tanpa
+// membuat data ganda
+type IdempotencyKey struct {
+	Key string `gorm:"primaryKey;column:key"`
+	// RequestHash adalah SHA-256 dari method, path, user dan body request
+	RequestHash string `gorm:"column:request_hash"`
+	// ResponseStatus 0 berarti request pertama masih diproses
+	ResponseStatus int    `gorm:"column:response_status"`
+	ContentType    string `gorm:"column:content_type"`
+	ResponseBody   []byte `gorm:"column:response_body"`
+	// LockedUntil membatasi lama request pertama dianggap masih berjalan; setelah itu
+	// (misalnya instance mati di tengah request) key boleh dipakai ulang
+	LockedUntil time.Time `gorm:"column:locked_until"`
+	ExpiresAt   time.Time `gorm:"column:expires_at"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "Idempotency_Key"
+}
+
+type IdempotencyService interface {
+	// Reserve menyimpan key baru secara atomik. Jika key masih berlaku, key yang sudah
+	// tersimpan dikembalikan dan tidak ada yang diubah.
+	Reserve(ctx context.Context, key *IdempotencyKey) (existing *IdempotencyKey, err error)
+	Complete(ctx context.Context, key *IdempotencyKey) error
+	// Release menghapus key yang belum selesai supaya request bisa dicoba lagi
+	Release(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
 }
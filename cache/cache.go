@@ -0,0 +1,15 @@
+// Package cache menyimpan hasil baca katalog di memori (LRU dengan TTL) atau Redis.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache menyimpan nilai byte per key. TTL 0 berarti tidak kedaluwarsa
+// (entri tetap bisa tergusur pada cache memori yang penuh).
+type Cache interface {
+	// Get mengembalikan ok=false jika key tidak ada atau sudah kedaluwarsa
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
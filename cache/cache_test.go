@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryGetSet(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(10)
+
+	_, ok, err := m.Get(ctx, "toppings")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, m.Set(ctx, "toppings", []byte("ceker"), 0))
+	value, ok, err := m.Get(ctx, "toppings")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "ceker", string(value))
+
+	require.NoError(t, m.Set(ctx, "toppings", []byte("bakso"), 0))
+	value, _, _ = m.Get(ctx, "toppings")
+	assert.Equal(t, "bakso", string(value))
+	assert.Equal(t, 1, m.Len())
+}
+
+func TestMemoryTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMemory(10)
+	m.now = func() time.Time { return now }
+
+	require.NoError(t, m.Set(ctx, "menu", []byte("x"), time.Minute))
+	now = now.Add(59 * time.Second)
+	_, ok, _ := m.Get(ctx, "menu")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok, _ = m.Get(ctx, "menu")
+	assert.False(t, ok)
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestMemoryEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(3)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, m.Set(ctx, fmt.Sprint(i), []byte{byte(i)}, 0))
+	}
+
+	// "0" dipakai lagi sehingga "1" yang paling lama tidak dipakai
+	_, ok, _ := m.Get(ctx, "0")
+	require.True(t, ok)
+	require.NoError(t, m.Set(ctx, "3", []byte{3}, 0))
+
+	assert.Equal(t, 3, m.Len())
+	for key, want := range map[string]bool{"0": true, "1": false, "2": true, "3": true} {
+		_, ok, _ := m.Get(ctx, key)
+		assert.Equal(t, want, ok, key)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	r := NewRedis(client, "seleraseblak:")
+
+	_, ok, err := r.Get(ctx, "toppings")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, r.Set(ctx, "toppings", []byte("ceker"), time.Minute))
+	value, ok, err := r.Get(ctx, "toppings")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "ceker", string(value))
+	assert.True(t, mr.Exists("seleraseblak:toppings"))
+
+	mr.FastForward(time.Minute)
+	_, ok, err = r.Get(ctx, "toppings")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, r.Set(ctx, "gen:catalog", []byte("1"), 0))
+	mr.FastForward(24 * time.Hour)
+	_, ok, _ = r.Get(ctx, "gen:catalog")
+	assert.True(t, ok)
+	assert.NoError(t, r.Ping(ctx))
+
+	mr.Close()
+	_, _, err = r.Get(ctx, "toppings")
+	assert.Error(t, err)
+	assert.Error(t, r.Ping(ctx))
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/cache"
+	"github.com/seleraseblak/backend/config"
+	"github.com/seleraseblak/backend/health"
+	"github.com/seleraseblak/backend/logging"
+	"github.com/seleraseblak/backend/metrics"
+	"github.com/seleraseblak/backend/migrations"
+	"github.com/seleraseblak/backend/ratelimit"
+	"github.com/seleraseblak/backend/services"
+	"github.com/seleraseblak/backend/storage"
+	"github.com/seleraseblak/backend/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gorm.io/gorm"
+)
+
+// app berisi koneksi dan semua service; dibuat sekali oleh setiap perintah supaya
+// server dan perintah admin memakai service (termasuk cache dan validasinya) yang sama
+type app struct {
+	cfg          *config.Config
+	db           *gorm.DB
+	photoStorage storage.Storage
+	health       *health.Checker
+	lifecycle    *lifecycle
+	// nil jika METRICS_ENABLED=false
+	metrics *metrics.Metrics
+	// nil jika TRACING_ENABLED=false
+	tracer *sdktrace.TracerProvider
+	// nil jika RATE_LIMIT_ENABLED=false
+	limiter ratelimit.Limiter
+
+	storeService          api.StoreService
+	productService        api.ProductService
+	productMasterService  api.ProductMasterService
+	toppingService        api.ToppingService
+	spicyLevelService     api.SpicyLevelService
+	productToppingService api.ProductToppingService
+	orderService          api.OrderService
+	promotionService      api.PromotionService
+	voucherService        api.VoucherService
+	customerService       api.CustomerService
+	loyaltyService        api.LoyaltyService
+	modifierService       api.ModifierService
+	storeScheduleService  api.StoreScheduleService
+	deliveryService       api.DeliveryService
+	photoService          api.PhotoService
+	categoryService       api.CategoryService
+	menuService           api.MenuService
+	userStoreService      api.UserStoreService
+	catalogService        api.CatalogService
+	idempotencyService    api.IdempotencyService
+}
+
+func newApp(cfg *config.Config) (*app, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	photoStorage, err := config.InitStorage(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("initializing storage: %w", err)
+	}
+
+	// Cache baca katalog
+	catalogCache, cacheTTL, err := config.InitCache(cfg.Cache)
+	if err != nil {
+		return nil, fmt.Errorf("initializing cache: %w", err)
+	}
+
+	limiter, err := config.InitRateLimiter(cfg.RateLimit, cfg.Cache.Redis)
+	if err != nil {
+		return nil, fmt.Errorf("initializing rate limiter: %w", err)
+	}
+
+	tracer, err := config.InitTracing(cfg.Tracing, cfg.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("initializing tracing: %w", err)
+	}
+
+	a := &app{cfg: cfg, db: db, photoStorage: photoStorage, lifecycle: newLifecycle(), tracer: tracer, limiter: limiter}
+	a.health = newHealthChecker(db, catalogCache)
+	if tracer != nil {
+		// Didaftarkan pertama supaya ditutup terakhir dan span dari penutupan lain ikut terkirim
+		a.lifecycle.OnClose("tracing", func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return tracer.Shutdown(ctx)
+		})
+		if err := db.Use(tracing.GormPlugin()); err != nil {
+			return nil, fmt.Errorf("initializing tracing: %w", err)
+		}
+	}
+	// Ditutup terbalik: cache lalu database, setelah semua worker berhenti
+	a.lifecycle.OnClose("database", func() error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	})
+	if closer, ok := catalogCache.(io.Closer); ok {
+		a.lifecycle.OnClose("cache", closer.Close)
+	}
+	if closer, ok := limiter.(io.Closer); ok {
+		a.lifecycle.OnClose("rate limiter", closer.Close)
+	}
+	if cfg.Metrics.Enabled {
+		if a.metrics, err = newMetrics(db); err != nil {
+			return nil, fmt.Errorf("initializing metrics: %w", err)
+		}
+	}
+	if err := services.SetupCategoryJoinTable(db); err != nil {
+		return nil, fmt.Errorf("configuring category join table: %w", err)
+	}
+	a.storeService = services.NewStoreService(db)
+	a.productService = services.NewProductService(db)
+	a.productMasterService = services.NewProductMasterService(db)
+	a.toppingService = services.NewToppingService(db)
+	a.spicyLevelService = services.NewSpicyLevelService()
+	a.productToppingService = services.NewProductToppingService(db)
+	a.orderService = services.NewOrderService(db, a.spicyLevelService)
+	a.promotionService = services.NewPromotionService(db)
+	a.voucherService = services.NewVoucherService(db)
+	a.customerService = services.NewCustomerService(db)
+	a.loyaltyService = services.NewLoyaltyService(db)
+	a.modifierService = services.NewModifierService(db, a.spicyLevelService)
+	a.storeScheduleService = services.NewStoreScheduleService(db)
+	a.deliveryService = services.NewDeliveryService(db)
+	a.photoService = services.NewPhotoService(db, photoStorage)
+	a.categoryService = services.NewCategoryService(db)
+	a.menuService = services.NewMenuService(db, a.spicyLevelService)
+	a.userStoreService = services.NewUserStoreService(db)
+	a.catalogService = services.NewCatalogService(db)
+	a.idempotencyService = services.NewIdempotencyService(db)
+
+	// Decorator cache dipasang setelah semua service dibuat supaya service lain
+	// (pesanan, menu) tetap membaca langsung dari database
+	if catalogCache != nil {
+		cc := services.NewCatalogCache(catalogCache, cacheTTL)
+		a.productService = cc.ProductService(a.productService)
+		a.productMasterService = cc.ProductMasterService(a.productMasterService)
+		a.toppingService = cc.ToppingService(a.toppingService)
+		a.spicyLevelService = cc.SpicyLevelService(a.spicyLevelService)
+		a.productToppingService = cc.ProductToppingService(a.productToppingService)
+		a.orderService = cc.OrderService(a.orderService)
+		a.modifierService = cc.ModifierService(a.modifierService)
+		a.photoService = cc.PhotoService(a.photoService)
+		a.categoryService = cc.CategoryService(a.categoryService)
+		a.catalogService = cc.CatalogService(a.catalogService)
+	}
+	if a.metrics != nil {
+		a.orderService = a.metrics.OrderService(a.orderService)
+	}
+	if tracer != nil {
+		a.traceServices()
+	}
+	return a, nil
+}
+
+// close menutup resource app untuk perintah admin yang selesai sendiri, dipanggil dengan
+// defer tepat setelah newApp supaya span terkirim dan koneksi ditutup juga saat perintah gagal
+func (a *app) close() {
+	if err := a.lifecycle.Shutdown(time.Duration(a.cfg.Server.ShutdownTimeout)); err != nil {
+		slog.Error("shutdown failed", "error", err)
+	}
+}
+
+// traceServices membungkus semua service paling luar supaya cache hit juga punya span
+func (a *app) traceServices() {
+	a.storeService = tracing.StoreService(a.storeService)
+	a.productService = tracing.ProductService(a.productService)
+	a.productMasterService = tracing.ProductMasterService(a.productMasterService)
+	a.toppingService = tracing.ToppingService(a.toppingService)
+	a.spicyLevelService = tracing.SpicyLevelService(a.spicyLevelService)
+	a.productToppingService = tracing.ProductToppingService(a.productToppingService)
+	a.orderService = tracing.OrderService(a.orderService)
+	a.promotionService = tracing.PromotionService(a.promotionService)
+	a.voucherService = tracing.VoucherService(a.voucherService)
+	a.customerService = tracing.CustomerService(a.customerService)
+	a.loyaltyService = tracing.LoyaltyService(a.loyaltyService)
+	a.modifierService = tracing.ModifierService(a.modifierService)
+	a.storeScheduleService = tracing.StoreScheduleService(a.storeScheduleService)
+	a.deliveryService = tracing.DeliveryService(a.deliveryService)
+	a.photoService = tracing.PhotoService(a.photoService)
+	a.categoryService = tracing.CategoryService(a.categoryService)
+	a.menuService = tracing.MenuService(a.menuService)
+	a.userStoreService = tracing.UserStoreService(a.userStoreService)
+	a.catalogService = tracing.CatalogService(a.catalogService)
+}
+
+// newMetrics memasang pengukur query dan statistik pool koneksi primary
+func newMetrics(db *gorm.DB) (*metrics.Metrics, error) {
+	m := metrics.New()
+	if err := db.Use(m.GormPlugin()); err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.RegisterDBStats("primary", sqlDB); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// openDB membuka database dengan logger query yang membawa request_id dari context.
+// Isi cache katalog selalu dibaca dari primary supaya replica yang tertinggal tidak ikut tersimpan.
+func openDB(cfg *config.Config) (*gorm.DB, error) {
+	db, err := config.InitDB(cfg.Database, logging.NewGormLogger(time.Duration(cfg.Log.SlowQuery)))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(services.PrimaryReadPlugin()); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// newHealthChecker mendaftarkan dependency yang harus sehat sebelum instance menerima traffic
+func newHealthChecker(db *gorm.DB, catalogCache cache.Cache) *health.Checker {
+	checker := health.New(2 * time.Second)
+	checker.Add("database", func(ctx context.Context) (string, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return "", err
+		}
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return "", err
+		}
+		stats := sqlDB.Stats()
+		return fmt.Sprintf("open %d, in use %d", stats.OpenConnections, stats.InUse), nil
+	})
+	checker.Add("migrations", func(ctx context.Context) (string, error) {
+		pending, err := migrations.Pending(db.WithContext(ctx))
+		if err != nil {
+			return "", err
+		}
+		if len(pending) > 0 {
+			names := make([]string, 0, len(pending))
+			for _, m := range pending {
+				names = append(names, fmt.Sprintf("%04d_%s", m.Version, m.Name))
+			}
+			return "", fmt.Errorf("pending migrations: %s", strings.Join(names, ", "))
+		}
+		all, err := migrations.Load()
+		if err != nil || len(all) == 0 {
+			return "", err
+		}
+		return fmt.Sprintf("version %d", all[len(all)-1].Version), nil
+	})
+	if pinger, ok := catalogCache.(interface{ Ping(context.Context) error }); ok {
+		checker.Add("cache", func(ctx context.Context) (string, error) {
+			return "", pinger.Ping(ctx)
+		})
+	}
+	return checker
+}
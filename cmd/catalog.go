@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/config"
+	"github.com/spf13/cobra"
+)
+
+func newCatalogCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Export or import the central catalog (categories, toppings, product masters)",
+	}
+
+	var output string
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "Write the catalog as JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := newApp(cfg)
+			if err != nil {
+				return err
+			}
+			defer a.close()
+			catalog, err := a.catalogService.ExportCatalog(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			if output != "" && output != "-" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(catalog); err != nil {
+				return err
+			}
+			if output != "" && output != "-" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "exported %d categories, %d toppings, %d product masters to %s\n",
+					len(catalog.Categories), len(catalog.Toppings), len(catalog.ProductMasters), output)
+			}
+			return nil
+		},
+	}
+	export.Flags().StringVarP(&output, "output", "o", "", "file to write (default stdout)")
+
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create or overwrite catalog entries from a JSON export (- for stdin)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var r io.Reader = cmd.InOrStdin()
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			var catalog api.CatalogExport
+			if err := json.NewDecoder(r).Decode(&catalog); err != nil {
+				return fmt.Errorf("reading catalog: %w", err)
+			}
+
+			a, err := newApp(cfg)
+			if err != nil {
+				return err
+			}
+			defer a.close()
+			result, err := a.catalogService.ImportCatalog(cmd.Context(), &catalog)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "imported %d categories, %d toppings, %d product masters\n",
+				result.Categories, result.Toppings, result.ProductMasters)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(export, importCmd)
+	return cmd
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// lifecycle memegang worker latar belakang dan resource yang harus ditutup saat aplikasi berhenti
+type lifecycle struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+	closers []namedCloser
+}
+
+type namedCloser struct {
+	name  string
+	close func() error
+}
+
+func newLifecycle() *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{ctx: ctx, cancel: cancel}
+}
+
+// Go menjalankan worker atau scheduler. ctx dibatalkan saat shutdown dan worker harus
+// kembali secepatnya setelah itu.
+func (l *lifecycle) Go(name string, run func(ctx context.Context)) {
+	l.workers.Add(1)
+	go func() {
+		defer l.workers.Done()
+		run(l.ctx)
+		if l.ctx.Err() == nil {
+			slog.Warn("worker stopped", "worker", name)
+		}
+	}()
+}
+
+// OnClose mendaftarkan resource; ditutup berurutan terbalik dari pendaftarannya
+func (l *lifecycle) OnClose(name string, close func() error) {
+	l.closers = append(l.closers, namedCloser{name: name, close: close})
+}
+
+// Shutdown menghentikan worker, menunggu paling lama timeout, lalu menutup semua resource.
+// Resource tetap ditutup walaupun ada worker yang tidak berhenti tepat waktu.
+func (l *lifecycle) Shutdown(timeout time.Duration) error {
+	l.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		l.workers.Wait()
+		close(done)
+	}()
+
+	var errs []error
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		errs = append(errs, fmt.Errorf("background workers did not stop within %s", timeout))
+	}
+
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		c := l.closers[i]
+		if err := c.close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing %s: %w", c.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
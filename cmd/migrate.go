@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/seleraseblak/backend/config"
+	"github.com/seleraseblak/backend/migrations"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply or roll back database migrations",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "up",
+		Short: "Apply all pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDB(cfg)
+			if err != nil {
+				return err
+			}
+			done, err := migrations.Up(db)
+			for _, m := range done {
+				fmt.Fprintf(cmd.OutOrStdout(), "applied %04d_%s\n", m.Version, m.Name)
+			}
+			if err == nil && len(done) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no pending migrations")
+			}
+			return err
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "down [n]",
+		Short: "Roll back the last n migrations (default 1)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			steps := 1
+			if len(args) > 0 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n < 1 {
+					return fmt.Errorf("invalid number of steps %q", args[0])
+				}
+				steps = n
+			}
+			db, err := openDB(cfg)
+			if err != nil {
+				return err
+			}
+			done, err := migrations.Down(db, steps)
+			for _, m := range done {
+				fmt.Fprintf(cmd.OutOrStdout(), "rolled back %04d_%s\n", m.Version, m.Name)
+			}
+			return err
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "List migrations and when they were applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDB(cfg)
+			if err != nil {
+				return err
+			}
+			list, err := migrations.List(db)
+			if err != nil {
+				return err
+			}
+			for _, s := range list {
+				applied := "pending"
+				if s.AppliedAt != nil {
+					applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%04d_%-30s %s\n", s.Version, s.Name, applied)
+			}
+			return nil
+		},
+	})
+	return cmd
+}
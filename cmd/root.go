@@ -0,0 +1,98 @@
+// Package cmd berisi perintah CLI: server API dan perintah admin untuk operasional.
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/seleraseblak/backend/config"
+	"github.com/seleraseblak/backend/logging"
+	"github.com/spf13/cobra"
+)
+
+func newRootCmd() *cobra.Command {
+	// cfg diisi sebelum perintah berjalan; setiap perintah memegang pointer yang sama
+	cfg := &config.Config{}
+	var envFile, configFile, profile string
+	root := &cobra.Command{
+		Use:           "seleraseblak",
+		Short:         "Selera Seblak backend API and admin tools",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !needsConfig(cmd) {
+				return nil
+			}
+			if err := loadEnv(envFile, cmd.Flags().Changed("env-file")); err != nil {
+				return err
+			}
+			if configFile == "" {
+				configFile = os.Getenv("CONFIG_FILE")
+			}
+			loaded, err := config.Load(config.Options{File: configFile, Profile: profile, Flags: cmd.Flags()})
+			if err != nil {
+				return err
+			}
+			*cfg = *loaded
+
+			// Log ke stderr supaya stdout tetap bersih untuk output perintah (misalnya catalog export)
+			logger, err := logging.New(os.Stderr, cfg.Log.Format, cfg.Log.Level)
+			if err != nil {
+				return err
+			}
+			slog.SetDefault(logger)
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVar(&envFile, "env-file", ".env", "env file to load before reading the environment")
+	root.PersistentFlags().StringVar(&configFile, "config", "", "YAML or TOML config file (default from CONFIG_FILE)")
+	root.PersistentFlags().StringVar(&profile, "profile", "", "configuration profile: development, staging, production (default from APP_ENV)")
+
+	root.AddCommand(
+		newServeCmd(cfg),
+		newMigrateCmd(cfg),
+		newSeedCmd(cfg),
+		newUserCmd(cfg),
+		newStoreCmd(cfg),
+		newCatalogCmd(cfg),
+	)
+	return root
+}
+
+// needsConfig bernilai false untuk bantuan dan skrip completion, yang tetap harus jalan
+// walaupun konfigurasi belum lengkap
+func needsConfig(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return false
+		}
+	}
+	return true
+}
+
+// loadEnv membaca file env jika ada. Variabel yang sudah di-set di environment tidak ditimpa,
+// dan file yang tidak ada hanya menjadi error jika dipilih lewat --env-file.
+func loadEnv(path string, explicit bool) error {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return nil
+		}
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := godotenv.Load(path); err != nil {
+		return fmt.Errorf("loading config %s: %w", path, err)
+	}
+	return nil
+}
+
+// Execute menjalankan CLI dengan argumen dari os.Args
+func Execute() error {
+	err := newRootCmd().Execute()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/seleraseblak/backend/config"
+	"github.com/seleraseblak/backend/migrations"
+	"github.com/seleraseblak/backend/services"
+	"github.com/spf13/cobra"
+)
+
+func newSeedCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "seed",
+		Short: "Apply migrations and insert demo data for local development",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := newApp(cfg)
+			if err != nil {
+				return err
+			}
+			defer a.close()
+			if _, err := migrations.Up(a.db); err != nil {
+				return fmt.Errorf("running migrations: %w", err)
+			}
+			if err := services.SeedDemoData(a.db); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "demo data inserted")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/api/controllers"
+	"github.com/seleraseblak/backend/api/middleware"
+	"github.com/seleraseblak/backend/config"
+	"github.com/seleraseblak/backend/migrations"
+	"github.com/seleraseblak/backend/services"
+	"github.com/seleraseblak/backend/storage"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the HTTP API",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := newApp(cfg)
+			if err != nil {
+				return err
+			}
+			if cfg.Database.AutoMigrate {
+				if _, err := migrations.Up(a.db); err != nil {
+					a.close()
+					return fmt.Errorf("running migrations: %w", err)
+				}
+			}
+			a.lifecycle.Go("idempotency cleanup", func(ctx context.Context) {
+				cleanIdempotencyKeys(ctx, a.idempotencyService)
+			})
+			return serve(a, newServer(a))
+		},
+	}
+	// Nilainya dibaca config.Load; di sini hanya didaftarkan
+	cmd.Flags().Int("port", 8080, "port to listen on, overrides PORT and the config file")
+	return cmd
+}
+
+// serve menjalankan server sampai SIGTERM/SIGINT, lalu berhenti dengan urutan:
+// readiness gagal, tunggu ShutdownDelay, berhenti menerima koneksi dan menunggu request
+// yang berjalan (paling lama ShutdownTimeout), hentikan worker, tutup cache dan database.
+func serve(a *app, server *fiber.App) error {
+	cfg := a.cfg.Server
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	listenErr := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "port", cfg.Port, "profile", a.cfg.Profile)
+		listenErr <- server.Listen(fmt.Sprintf(":%d", cfg.Port))
+	}()
+
+	select {
+	case err := <-listenErr:
+		// Gagal listen (misalnya port dipakai); resource tetap ditutup
+		if closeErr := a.lifecycle.Shutdown(time.Duration(cfg.ShutdownTimeout)); closeErr != nil {
+			slog.Error("shutdown failed", "error", closeErr)
+		}
+		return err
+	case <-ctx.Done():
+	}
+	// Sinyal kedua langsung menghentikan proses
+	stop()
+
+	slog.Info("shutting down", "drain_delay", time.Duration(cfg.ShutdownDelay).String())
+	a.health.SetDraining()
+	time.Sleep(time.Duration(cfg.ShutdownDelay))
+
+	var errs []error
+	if err := server.ShutdownWithTimeout(time.Duration(cfg.ShutdownTimeout)); err != nil {
+		errs = append(errs, fmt.Errorf("in-flight requests did not finish within %s: %w", time.Duration(cfg.ShutdownTimeout), err))
+	}
+	if err := <-listenErr; err != nil {
+		errs = append(errs, err)
+	}
+	if err := a.lifecycle.Shutdown(time.Duration(cfg.ShutdownTimeout)); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	slog.Info("shutdown complete")
+	return nil
+}
+
+// cleanIdempotencyKeys menghapus Idempotency-Key yang kedaluwarsa setiap jam
+func cleanIdempotencyKeys(ctx context.Context, svc api.IdempotencyService) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := svc.DeleteExpired(ctx)
+			if err != nil {
+				slog.Warn("deleting expired idempotency keys failed", "error", err)
+				continue
+			}
+			slog.Debug("deleted expired idempotency keys", "count", deleted)
+		}
+	}
+}
+
+// newServer membuat aplikasi Fiber dengan semua route API
+func newServer(a *app) *fiber.App {
+	// Initialize controllers
+	storeController := controllers.NewStoreController(a.storeService)
+	productController := controllers.NewProductController(a.productService)
+	productMasterController := controllers.NewProductMasterController(a.productMasterService)
+	toppingController := controllers.NewToppingController(a.toppingService)
+	spicyLevelController := controllers.NewSpicyLevelController(a.spicyLevelService)
+	productToppingController := controllers.NewProductToppingController(a.productToppingService)
+	orderController := controllers.NewOrderController(a.orderService)
+	promotionController := controllers.NewPromotionController(a.promotionService)
+	voucherController := controllers.NewVoucherController(a.voucherService, a.orderService)
+	customerController := controllers.NewCustomerController(a.customerService, a.loyaltyService)
+	loyaltyController := controllers.NewLoyaltyController(a.loyaltyService)
+	modifierController := controllers.NewModifierController(a.modifierService)
+	storeScheduleController := controllers.NewStoreScheduleController(a.storeScheduleService)
+	deliveryController := controllers.NewDeliveryController(a.deliveryService)
+	photoController := controllers.NewPhotoController(a.photoService)
+	categoryController := controllers.NewCategoryController(a.categoryService)
+	menuController := controllers.NewMenuController(a.menuService)
+	healthController := controllers.NewHealthController(a.health)
+
+	// Create Fiber app
+	fiberCfg := fiber.Config{
+		// Sedikit di atas batas foto supaya field form lain tetap muat
+		BodyLimit: services.MaxPhotoSize + 1<<20,
+	}
+	if proxies := a.cfg.Server.TrustedProxies; len(proxies) > 0 {
+		// ctx.IP() membaca IP client dari X-Forwarded-For hanya jika request datang dari proxy ini
+		fiberCfg.ProxyHeader = fiber.HeaderXForwardedFor
+		fiberCfg.EnableTrustedProxyCheck = true
+		fiberCfg.TrustedProxies = proxies
+		fiberCfg.EnableIPValidation = true
+	}
+	app := fiber.New(fiberCfg)
+
+	// Span request dipasang paling luar supaya log request bisa menyertakan trace_id
+	if a.tracer != nil {
+		app.Use(middleware.Tracing())
+	}
+	// Request ID dan log per request; dipasang sebelum route supaya semua response tercatat
+	app.Use(middleware.RequestLogger())
+	if a.metrics != nil {
+		app.Use(middleware.Metrics(a.metrics))
+	}
+
+	// CORS middleware dengan konfigurasi yang lebih lengkap
+	app.Use(cors.New(cors.Config{
+		AllowOrigins:     strings.Join(a.cfg.Server.CORSOrigins, ","), // URL frontend yang diizinkan
+		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS,HEAD,PATCH",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Requested-With, X-Request-ID, Idempotency-Key, traceparent, tracestate",
+		AllowCredentials: true,
+		ExposeHeaders:    "Content-Length, Access-Control-Allow-Origin, X-Request-ID, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After, Idempotent-Replayed",
+		MaxAge:           86400, // 24 jam dalam detik
+	}))
+
+	// Health check untuk Docker dan load balancer
+	app.Get("/healthz", healthController.Liveness)
+	app.Get("/readyz", healthController.Readiness)
+	if a.metrics != nil {
+		metricsController := controllers.NewMetricsController(a.metrics.Handler(), a.cfg.Metrics.Token)
+		app.Get("/metrics", metricsController.Metrics)
+	}
+
+	// API routes
+	// Foto di storage lokal disajikan langsung oleh server
+	if local, ok := a.photoStorage.(*storage.Local); ok && strings.HasPrefix(local.BaseURL, "/") {
+		app.Static(local.BaseURL, local.Dir, fiber.Static{MaxAge: 86400})
+	}
+
+	// Batas waktu dipasang setelah health check dan /metrics, yang punya batas sendiri
+	api := app.Group("/", middleware.Timeout(time.Duration(a.cfg.Server.RequestTimeout)))
+	uploadTimeout := middleware.Timeout(time.Duration(a.cfg.Server.UploadTimeout))
+
+	// Rate limit per client: GET katalog dan perubahan data punya bucket terpisah, dan
+	// route voucher serta quote/order dengan voucher_code mendapat batas tambahan yang lebih ketat
+	voucherLimit := func(ctx *fiber.Ctx) error { return ctx.Next() }
+	if a.limiter != nil {
+		rl := a.cfg.RateLimit
+		readLimit := middleware.RateLimit(a.limiter, "read", rl.Read)
+		writeLimit := middleware.RateLimit(a.limiter, "write", rl.Write)
+		api.Use(func(ctx *fiber.Ctx) error {
+			if ctx.Method() == fiber.MethodGet || ctx.Method() == fiber.MethodHead {
+				return readLimit(ctx)
+			}
+			return writeLimit(ctx)
+		})
+		voucherLimit = middleware.RateLimit(a.limiter, "voucher", rl.Voucher)
+	}
+
+	// Idempotency-Key berlaku untuk semua POST. Request pertama dianggap masih berjalan
+	// selama batas waktu request terpanjang.
+	lockTimeout := max(time.Duration(a.cfg.Server.RequestTimeout), time.Duration(a.cfg.Server.UploadTimeout))
+	idempotency := middleware.Idempotency(a.idempotencyService, time.Duration(a.cfg.Idempotency.TTL), lockTimeout)
+	api.Use(func(ctx *fiber.Ctx) error {
+		if ctx.Method() == fiber.MethodPost {
+			return idempotency(ctx)
+		}
+		return ctx.Next()
+	})
+
+	// Store routes
+	stores := api.Group("/stores")
+	stores.Post("/", storeController.CreateStore)
+	stores.Get("/nearby", storeController.ListNearbyStores)
+	stores.Get("/:id", storeController.GetStore)
+	stores.Put("/:id", storeController.UpdateStore)
+	stores.Delete("/:id", storeController.DeleteStore)
+	stores.Get("/", storeController.ListStores)
+
+	// Store schedule routes
+	stores.Put("/:store_id/opening-hours", storeScheduleController.SetOpeningHours)
+	stores.Get("/:store_id/closures", storeScheduleController.ListClosures)
+	stores.Post("/:store_id/closures", storeScheduleController.AddClosure)
+	stores.Delete("/:store_id/closures/:id", storeScheduleController.DeleteClosure)
+
+	// Delivery routes
+	stores.Get("/:store_id/delivery/check", deliveryController.CheckDelivery)
+	stores.Get("/:store_id/delivery-zones", deliveryController.ListDeliveryZones)
+	stores.Post("/:store_id/delivery-zones", deliveryController.CreateDeliveryZone)
+	stores.Put("/:store_id/delivery-zones/:id", deliveryController.UpdateDeliveryZone)
+	stores.Delete("/:store_id/delivery-zones/:id", deliveryController.DeleteDeliveryZone)
+
+	// Product routes
+	stores.Post("/:store_id/products", productController.CreateProduct)
+	stores.Get("/:store_id/products/:id", productController.GetProduct)
+	stores.Put("/:store_id/products/:id", productController.UpdateProduct)
+	stores.Delete("/:store_id/products/:id", productController.DeleteProduct)
+	stores.Get("/:store_id/products", productController.ListProducts)
+	stores.Post("/:store_id/products/:id/photo", uploadTimeout, photoController.UploadProductPhoto)
+
+	// Menu storefront
+	stores.Get("/:store_id/menu", menuController.GetStoreMenu)
+
+	// Order routes
+	orderVoucherLimit := middleware.WithVoucherCode(voucherLimit)
+	stores.Post("/:store_id/orders/quote", orderVoucherLimit, orderController.QuoteOrder)
+	stores.Post("/:store_id/orders", orderVoucherLimit, orderController.CreateOrder)
+	stores.Get("/:store_id/orders/:id", orderController.GetOrder)
+	stores.Put("/:store_id/orders/:id/status", orderController.UpdateOrderStatus)
+	stores.Get("/:store_id/orders", orderController.ListOrders)
+
+	// Product Master routes
+	productMasters := api.Group("/product-masters")
+	productMasters.Post("/", productMasterController.CreateProductMaster)
+	productMasters.Get("/:id", productMasterController.GetProductMaster)
+	productMasters.Put("/:id", productMasterController.UpdateProductMaster)
+	productMasters.Delete("/:id", productMasterController.DeleteProductMaster)
+	productMasters.Get("/", productMasterController.ListProductMasters)
+	productMasters.Post("/:id/photo", uploadTimeout, photoController.UploadProductMasterPhoto)
+
+	// Category routes
+	categories := api.Group("/categories")
+	categories.Get("/", categoryController.ListCategories)
+	categories.Post("/", categoryController.CreateCategory)
+	categories.Get("/:slug/products", categoryController.ListCategoryProducts)
+	categories.Get("/:id", categoryController.GetCategory)
+	categories.Put("/:id", categoryController.UpdateCategory)
+	categories.Delete("/:id", categoryController.DeleteCategory)
+
+	// Topping routes
+	api.Get("/toppings", toppingController.GetToppings)
+	api.Get("/toppings/:id", toppingController.GetTopping)
+
+	// Spicy Level routes
+	api.Get("/spicy-levels", spicyLevelController.GetSpicyLevels)
+	api.Get("/spicy-levels/:id", spicyLevelController.GetSpicyLevel)
+
+	// Product Topping routes
+	api.Get("/product-toppings", productToppingController.GetProductToppings)
+	api.Get("/products/:productId/toppings", productToppingController.GetProductToppingsByProduct)
+	api.Get("/toppings/:toppingId/products", productToppingController.GetProductToppingsByTopping)
+
+	// Modifier routes
+	api.Get("/products/:productId/modifiers", modifierController.GetModifierGroups)
+	api.Put("/products/:productId/modifiers", modifierController.SetModifierGroups)
+	api.Post("/products/:productId/modifiers/validate", modifierController.ValidateConfiguration)
+
+	// Promotion routes
+	promotions := api.Group("/promotions")
+	promotions.Post("/", promotionController.CreatePromotion)
+	promotions.Get("/:id", promotionController.GetPromotion)
+	promotions.Put("/:id", promotionController.UpdatePromotion)
+	promotions.Delete("/:id", promotionController.DeletePromotion)
+	promotions.Get("/", promotionController.ListPromotions)
+
+	// Voucher routes
+	vouchers := api.Group("/vouchers")
+	vouchers.Post("/validate", voucherLimit, voucherController.ValidateVoucher)
+	vouchers.Post("/", voucherController.CreateVoucher)
+	vouchers.Get("/:code", voucherLimit, voucherController.GetVoucher)
+	vouchers.Put("/:id", voucherController.UpdateVoucher)
+	vouchers.Delete("/:id", voucherController.DeleteVoucher)
+	vouchers.Get("/", voucherController.ListVouchers)
+
+	// Customer & loyalty routes
+	customers := api.Group("/customers")
+	customers.Post("/", customerController.CreateCustomer)
+	customers.Get("/:id", customerController.GetCustomer)
+	customers.Put("/:id", customerController.UpdateCustomer)
+	customers.Get("/", customerController.ListCustomers)
+	customers.Get("/:id/loyalty", customerController.GetLoyaltyBalance)
+	customers.Get("/:id/loyalty/history", customerController.GetLoyaltyHistory)
+
+	loyaltyPrograms := api.Group("/loyalty-programs")
+	loyaltyPrograms.Post("/", loyaltyController.CreateProgram)
+	loyaltyPrograms.Get("/:id", loyaltyController.GetProgram)
+	loyaltyPrograms.Put("/:id", loyaltyController.UpdateProgram)
+	loyaltyPrograms.Delete("/:id", loyaltyController.DeleteProgram)
+	loyaltyPrograms.Get("/", loyaltyController.ListPrograms)
+
+	return app
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/seleraseblak/backend/api/controllers"
+	"github.com/seleraseblak/backend/config"
+	"github.com/seleraseblak/backend/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{
+		Port:            freePort(t),
+		ShutdownDelay:   config.Duration(200 * time.Millisecond),
+		ShutdownTimeout: config.Duration(5 * time.Second),
+	}}
+	a := &app{cfg: cfg, health: health.New(time.Second), lifecycle: newLifecycle()}
+
+	var closed []string
+	a.lifecycle.OnClose("database", func() error { closed = append(closed, "database"); return nil })
+	a.lifecycle.OnClose("cache", func() error { closed = append(closed, "cache"); return nil })
+	workerStopped := make(chan struct{})
+	a.lifecycle.Go("test", func(ctx context.Context) {
+		<-ctx.Done()
+		close(workerStopped)
+	})
+
+	server := fiber.New()
+	hc := controllers.NewHealthController(a.health)
+	server.Get("/readyz", hc.Readiness)
+	started := make(chan struct{})
+	server.Get("/slow", func(ctx *fiber.Ctx) error {
+		close(started)
+		time.Sleep(500 * time.Millisecond)
+		return ctx.SendString("done")
+	})
+
+	served := make(chan error, 1)
+	go func() { served <- serve(a, server) }()
+
+	base := fmt.Sprintf("http://127.0.0.1:%d", cfg.Server.Port)
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(base + "/readyz")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	slow := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(base + "/slow")
+		if err != nil {
+			slow <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		slow <- string(body)
+	}()
+	<-started
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+	// Selama jeda readiness sudah gagal tapi koneksi baru masih diterima
+	require.Eventually(t, func() bool { return a.health.Draining() }, time.Second, 5*time.Millisecond)
+	resp, err := http.Get(base + "/readyz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	assert.Equal(t, "done", <-slow)
+	require.NoError(t, <-served)
+	<-workerStopped
+	assert.Equal(t, []string{"cache", "database"}, closed)
+}
+
+func TestLifecycleShutdownTimeout(t *testing.T) {
+	l := newLifecycle()
+	release := make(chan struct{})
+	defer close(release)
+	l.Go("stuck", func(ctx context.Context) { <-release })
+	closed := false
+	l.OnClose("database", func() error { closed = true; return nil })
+
+	err := l.Shutdown(20 * time.Millisecond)
+	assert.ErrorContains(t, err, "background workers did not stop within 20ms")
+	assert.True(t, closed, "resources are closed even when a worker is stuck")
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/config"
+	"github.com/spf13/cobra"
+)
+
+func newStoreCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: "Manage stores",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "publish <store-id>",
+		Short: "Publish a store so it appears in listings and accepts orders",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := newApp(cfg)
+			if err != nil {
+				return err
+			}
+			defer a.close()
+			store, err := a.storeService.GetStore(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("store %s: %w", args[0], err)
+			}
+			if err := a.storeService.UpdateStore(cmd.Context(), store.ID, &api.Store{Status: api.StatusPublished}); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "store %s (%s) published\n", store.ID, store.StoreName)
+			return nil
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/config"
+	"github.com/spf13/cobra"
+)
+
+func newUserCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage store users",
+	}
+
+	var userID, storeID, role string
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Give a user a role in a store",
+		Long: "Give a user a role in a store. Accounts and logins live in Directus; pass the\n" +
+			"Directus user ID with --user-id, or leave it out to generate a new ID.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := newApp(cfg)
+			if err != nil {
+				return err
+			}
+			defer a.close()
+			if userID == "" {
+				userID = uuid.NewString()
+			}
+			userStore := &api.UserStore{UserID: userID, StoreID: storeID, RoleInStore: role}
+			if err := a.userStoreService.AssignUserToStore(cmd.Context(), userStore); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "user %s is %s of store %s\n", userStore.UserID, userStore.RoleInStore, userStore.StoreID)
+			return nil
+		},
+	}
+	create.Flags().StringVar(&userID, "user-id", "", "Directus user ID (UUID)")
+	create.Flags().StringVar(&storeID, "store", "", "store ID")
+	create.Flags().StringVar(&role, "role", "", "role in the store, e.g. owner, manager or cashier")
+	create.MarkFlagRequired("store")
+	create.MarkFlagRequired("role")
+
+	cmd.AddCommand(create)
+	return cmd
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/seleraseblak/backend/cache"
+)
+
+// InitCache memilih cache katalog dari cache.driver (memory, redis atau none).
+// Mengembalikan nil jika cache dimatikan.
+func InitCache(cfg CacheConfig) (cache.Cache, time.Duration, error) {
+	ttl := time.Duration(cfg.TTL)
+	switch cfg.Driver {
+	case "memory":
+		return cache.NewMemory(cfg.MaxEntries), ttl, nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			return nil, 0, fmt.Errorf("connecting to redis: %w", err)
+		}
+		return cache.NewRedis(client, "seleraseblak:"), ttl, nil
+	case "none":
+		return nil, 0, nil
+	default:
+		return nil, 0, fmt.Errorf("unknown cache driver %q", cfg.Driver)
+	}
+}
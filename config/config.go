@@ -0,0 +1,429 @@
+// Package config memuat konfigurasi aplikasi dan membuat koneksi (database, storage, cache) darinya.
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/seleraseblak/backend/ratelimit"
+	"github.com/spf13/pflag"
+)
+
+// Profil bawaan. Profil lain boleh dipakai selama didefinisikan di file konfigurasi.
+const (
+	ProfileDevelopment = "development"
+	ProfileStaging     = "staging"
+	ProfileProduction  = "production"
+)
+
+// Config adalah seluruh konfigurasi aplikasi. Urutan prioritas (yang belakang menang):
+// default profil, file konfigurasi, bagian profiles.<profil> di file, environment, flag CLI.
+type Config struct {
+	Profile     string            `yaml:"-" toml:"-"`
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	Database    DatabaseConfig    `yaml:"database" toml:"database"`
+	Storage     StorageConfig     `yaml:"storage" toml:"storage"`
+	Cache       CacheConfig       `yaml:"cache" toml:"cache"`
+	Log         LogConfig         `yaml:"log" toml:"log"`
+	Metrics     MetricsConfig     `yaml:"metrics" toml:"metrics"`
+	Tracing     TracingConfig     `yaml:"tracing" toml:"tracing"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit" toml:"rate_limit"`
+	Idempotency IdempotencyConfig `yaml:"idempotency" toml:"idempotency"`
+}
+
+type ServerConfig struct {
+	Port        int      `yaml:"port" toml:"port"`
+	CORSOrigins []string `yaml:"cors_origins" toml:"cors_origins"`
+	// ShutdownDelay adalah jeda antara readiness gagal dan berhenti menerima koneksi, supaya
+	// load balancer sempat mengeluarkan instance. ShutdownTimeout membatasi tunggu request berjalan.
+	ShutdownDelay   Duration `yaml:"shutdown_delay" toml:"shutdown_delay"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// RequestTimeout membatasi satu request API termasuk query-nya; UploadTimeout dipakai
+	// route upload foto yang perlu mengirim file ke storage
+	RequestTimeout Duration `yaml:"request_timeout" toml:"request_timeout"`
+	UploadTimeout  Duration `yaml:"upload_timeout" toml:"upload_timeout"`
+	// TrustedProxies adalah IP/CIDR load balancer yang header X-Forwarded-For-nya dipercaya.
+	// Tanpa ini semua client di belakang proxy dianggap satu IP oleh rate limit.
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+}
+
+type DatabaseConfig struct {
+	Host        string `yaml:"host" toml:"host"`
+	Port        int    `yaml:"port" toml:"port"`
+	User        string `yaml:"user" toml:"user"`
+	Password    string `yaml:"password" toml:"password"`
+	Name        string `yaml:"name" toml:"name"`
+	SSLMode     string `yaml:"sslmode" toml:"sslmode"`
+	SSLRootCert string `yaml:"sslrootcert" toml:"sslrootcert"`
+	SSLCert     string `yaml:"sslcert" toml:"sslcert"`
+	SSLKey      string `yaml:"sslkey" toml:"sslkey"`
+	AutoMigrate bool   `yaml:"auto_migrate" toml:"auto_migrate"`
+
+	MaxOpenConns    int      `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int      `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	ConnMaxIdleTime Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+	// ConnectTimeout membatasi satu percobaan koneksi, ConnectRetryTimeout total waktu
+	// menunggu database saat start (0 = tidak mencoba ulang)
+	ConnectTimeout      Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+	ConnectRetryTimeout Duration `yaml:"connect_retry_timeout" toml:"connect_retry_timeout"`
+
+	// ReadReplicas berisi host[:port] replika; user, password, database dan TLS sama dengan primary
+	ReadReplicas []string `yaml:"read_replicas" toml:"read_replicas"`
+}
+
+type StorageConfig struct {
+	Driver    string   `yaml:"driver" toml:"driver"`
+	LocalDir  string   `yaml:"local_dir" toml:"local_dir"`
+	PublicURL string   `yaml:"public_url" toml:"public_url"`
+	S3        S3Config `yaml:"s3" toml:"s3"`
+}
+
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint" toml:"endpoint"`
+	Region          string `yaml:"region" toml:"region"`
+	Bucket          string `yaml:"bucket" toml:"bucket"`
+	AccessKeyID     string `yaml:"access_key_id" toml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key" toml:"secret_access_key"`
+	PathStyle       bool   `yaml:"path_style" toml:"path_style"`
+}
+
+type CacheConfig struct {
+	Driver     string      `yaml:"driver" toml:"driver"`
+	TTL        Duration    `yaml:"ttl" toml:"ttl"`
+	MaxEntries int         `yaml:"max_entries" toml:"max_entries"`
+	Redis      RedisConfig `yaml:"redis" toml:"redis"`
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr" toml:"addr"`
+	Password string `yaml:"password" toml:"password"`
+	DB       int    `yaml:"db" toml:"db"`
+}
+
+type LogConfig struct {
+	// Format json untuk produksi (dibaca log collector) atau text untuk dibaca di terminal
+	Format string `yaml:"format" toml:"format"`
+	Level  string `yaml:"level" toml:"level"`
+	// SlowQuery adalah batas durasi query yang dicatat sebagai warning; 0 mematikannya
+	SlowQuery Duration `yaml:"slow_query" toml:"slow_query"`
+}
+
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Token kosong berarti /metrics terbuka; batasi di proxy jika tidak memakai token
+	Token string `yaml:"token" toml:"token"`
+}
+
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Endpoint OTLP/HTTP collector, misalnya http://localhost:4318
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	// SampleRatio adalah bagian trace baru yang disimpan (0 sampai 1). Request dengan
+	// traceparent mengikuti keputusan sampling induknya.
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+	ServiceName string  `yaml:"service_name" toml:"service_name"`
+}
+
+// RateLimitConfig mengatur token bucket per client (user jika sudah login, selain itu IP)
+// untuk setiap kelompok route. Rule ditulis seperti "60/1m".
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Driver memory (per instance) atau redis (bersama, memakai cache.redis)
+	Driver string `yaml:"driver" toml:"driver"`
+	// Read untuk GET katalog publik, Write untuk request yang mengubah data
+	Read  ratelimit.Rule `yaml:"read" toml:"read"`
+	Write ratelimit.Rule `yaml:"write" toml:"write"`
+	// Voucher lebih ketat supaya kode voucher tidak bisa ditebak satu per satu
+	Voucher ratelimit.Rule `yaml:"voucher" toml:"voucher"`
+}
+
+type IdempotencyConfig struct {
+	// TTL adalah lama response disimpan untuk request ulang dengan Idempotency-Key yang sama
+	TTL Duration `yaml:"ttl" toml:"ttl"`
+}
+
+// Duration adalah time.Duration yang ditulis sebagai teks ("5m", "30s") di file konfigurasi
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Options menentukan sumber konfigurasi untuk Load
+type Options struct {
+	// File YAML (.yaml/.yml) atau TOML (.toml). Kosong berarti tanpa file.
+	File string
+	// Profile kosong berarti APP_ENV, lalu development.
+	Profile string
+	// Flags yang di-set user menimpa semua sumber lain
+	Flags *pflag.FlagSet
+}
+
+var corsOrigins = []string{"https://seleraseblak-website.pages.dev", "https://seleraseblak.com"}
+
+// defaults mengembalikan nilai awal untuk profil. Development memakai database lokal
+// tanpa TLS dan mengizinkan frontend di localhost.
+func defaults(profile string) Config {
+	cfg := Config{
+		Profile: profile,
+		Server: ServerConfig{
+			Port:            8080,
+			CORSOrigins:     append([]string(nil), corsOrigins...),
+			ShutdownDelay:   Duration(5 * time.Second),
+			ShutdownTimeout: Duration(30 * time.Second),
+			RequestTimeout:  Duration(15 * time.Second),
+			UploadTimeout:   Duration(time.Minute),
+		},
+		Database: DatabaseConfig{
+			Port:                5432,
+			SSLMode:             "require",
+			AutoMigrate:         true,
+			MaxOpenConns:        20,
+			MaxIdleConns:        5,
+			ConnMaxLifetime:     Duration(30 * time.Minute),
+			ConnMaxIdleTime:     Duration(5 * time.Minute),
+			ConnectTimeout:      Duration(5 * time.Second),
+			ConnectRetryTimeout: Duration(time.Minute),
+		},
+		Storage: StorageConfig{
+			Driver:   "local",
+			LocalDir: "./uploads",
+		},
+		Cache: CacheConfig{
+			Driver: "memory",
+			TTL:    Duration(5 * time.Minute),
+		},
+		Log: LogConfig{
+			Format:    "json",
+			Level:     "info",
+			SlowQuery: Duration(200 * time.Millisecond),
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: Duration(24 * time.Hour),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Driver:  "memory",
+			Read:    ratelimit.Rule{Limit: 300, Period: time.Minute},
+			Write:   ratelimit.Rule{Limit: 60, Period: time.Minute},
+			Voucher: ratelimit.Rule{Limit: 10, Period: time.Minute},
+		},
+		Tracing: TracingConfig{
+			Endpoint:    "http://localhost:4318",
+			SampleRatio: 1,
+			ServiceName: "seleraseblak-backend",
+		},
+	}
+	if profile == ProfileDevelopment {
+		cfg.Server.CORSOrigins = append([]string{"http://localhost:3000", "http://localhost:4321"}, corsOrigins...)
+		cfg.Database.SSLMode = "disable"
+		cfg.Server.ShutdownDelay = 0
+		cfg.Log.Format = "text"
+	}
+	return cfg
+}
+
+// Load membaca konfigurasi dari semua sumber lalu memvalidasinya
+func Load(opts Options) (*Config, error) {
+	profile := opts.Profile
+	if profile == "" {
+		profile = os.Getenv("APP_ENV")
+	}
+	if profile == "" {
+		profile = ProfileDevelopment
+	}
+
+	cfg := defaults(profile)
+	knownProfile := profile == ProfileDevelopment || profile == ProfileStaging || profile == ProfileProduction
+	if opts.File != "" {
+		found, err := applyFile(&cfg, opts.File, profile)
+		if err != nil {
+			return nil, err
+		}
+		knownProfile = knownProfile || found
+	}
+	if !knownProfile {
+		return nil, fmt.Errorf("unknown profile %q: use development, staging, production or define it under profiles in the config file", profile)
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return nil, err
+	}
+	if err := applyFlags(&cfg, opts.Flags); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Validate memeriksa seluruh konfigurasi dan melaporkan semua masalah sekaligus.
+// Nama variabel environment disebut supaya mudah diperbaiki.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("server.port (PORT) must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	// Cookie/Authorization dikirim lintas origin, jadi origin harus disebut satu per satu
+	if len(c.Server.CORSOrigins) == 0 || contains(c.Server.CORSOrigins, "*") {
+		add("server.cors_origins (CORS_ORIGINS) must list the allowed origins; \"*\" is not allowed with credentials")
+	}
+	if c.Server.ShutdownDelay < 0 || c.Server.ShutdownTimeout <= 0 {
+		add("server.shutdown_delay (SHUTDOWN_DELAY) must not be negative and server.shutdown_timeout (SHUTDOWN_TIMEOUT) must be positive")
+	}
+	if c.Server.RequestTimeout <= 0 || c.Server.UploadTimeout <= 0 {
+		add("server.request_timeout (REQUEST_TIMEOUT) and server.upload_timeout (UPLOAD_TIMEOUT) must be positive")
+	}
+
+	if c.Database.Host == "" {
+		add("database.host (DB_HOST) is required")
+	}
+	if c.Database.User == "" {
+		add("database.user (DB_USER) is required")
+	}
+	if c.Database.Name == "" {
+		add("database.name (DB_NAME) is required")
+	}
+	if c.Database.Port < 1 || c.Database.Port > 65535 {
+		add("database.port (DB_PORT) must be between 1 and 65535, got %d", c.Database.Port)
+	}
+	if !contains(sslModes, c.Database.SSLMode) {
+		add("database.sslmode (DB_SSLMODE) must be one of %s, got %q", strings.Join(sslModes, ", "), c.Database.SSLMode)
+	}
+	if (c.Database.SSLCert == "") != (c.Database.SSLKey == "") {
+		add("database.sslcert (DB_SSLCERT) and database.sslkey (DB_SSLKEY) must be set together")
+	}
+	if c.Database.MaxOpenConns < 1 {
+		add("database.max_open_conns (DB_MAX_OPEN_CONNS) must be at least 1")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		add("database.max_idle_conns (DB_MAX_IDLE_CONNS) must be between 0 and max_open_conns")
+	}
+	if c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 || c.Database.ConnectRetryTimeout < 0 {
+		add("database connection durations (DB_CONN_MAX_LIFETIME, DB_CONN_MAX_IDLE_TIME, DB_CONNECT_RETRY_TIMEOUT) must not be negative")
+	}
+	if c.Database.ConnectTimeout < Duration(time.Second) {
+		add("database.connect_timeout (DB_CONNECT_TIMEOUT) must be at least 1s")
+	}
+	for _, replica := range c.Database.ReadReplicas {
+		if _, _, err := splitHostPort(replica, c.Database.Port); err != nil {
+			add("database.read_replicas (DB_READ_REPLICAS): %v", err)
+		}
+	}
+
+	switch c.Storage.Driver {
+	case "local":
+		if c.Storage.LocalDir == "" {
+			add("storage.local_dir (STORAGE_LOCAL_DIR) is required for the local driver")
+		}
+	case "s3":
+		s3 := c.Storage.S3
+		if s3.Endpoint == "" || s3.Bucket == "" || s3.AccessKeyID == "" || s3.SecretAccessKey == "" {
+			add("storage.s3 endpoint, bucket, access_key_id and secret_access_key (S3_*) are required for the s3 driver")
+		}
+	default:
+		add("storage.driver (STORAGE_DRIVER) must be local or s3, got %q", c.Storage.Driver)
+	}
+
+	switch c.Cache.Driver {
+	case "memory", "redis":
+		if c.Cache.TTL <= 0 {
+			add("cache.ttl (CACHE_TTL) must be positive")
+		}
+		if c.Cache.MaxEntries < 0 {
+			add("cache.max_entries (CACHE_MAX_ENTRIES) must not be negative")
+		}
+		if c.Cache.Driver == "redis" && c.Cache.Redis.Addr == "" {
+			add("cache.redis.addr (REDIS_ADDR) is required for the redis driver")
+		}
+	case "none":
+	default:
+		add("cache.driver (CACHE_DRIVER) must be memory, redis or none, got %q", c.Cache.Driver)
+	}
+
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		add("log.format (LOG_FORMAT) must be json or text, got %q", c.Log.Format)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		add("log.level (LOG_LEVEL) must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	if c.Log.SlowQuery < 0 {
+		add("log.slow_query (LOG_SLOW_QUERY) must not be negative")
+	}
+
+	if c.RateLimit.Enabled {
+		switch c.RateLimit.Driver {
+		case "memory":
+		case "redis":
+			if c.Cache.Redis.Addr == "" {
+				add("cache.redis.addr (REDIS_ADDR) is required for the redis rate limit driver")
+			}
+		default:
+			add("rate_limit.driver (RATE_LIMIT_DRIVER) must be memory or redis, got %q", c.RateLimit.Driver)
+		}
+		rules := []struct {
+			name string
+			rule ratelimit.Rule
+		}{{"read", c.RateLimit.Read}, {"write", c.RateLimit.Write}, {"voucher", c.RateLimit.Voucher}}
+		for _, r := range rules {
+			if r.rule.Limit <= 0 || r.rule.Period <= 0 {
+				add("rate_limit.%s (RATE_LIMIT_%s) must look like 60/1m", r.name, strings.ToUpper(r.name))
+			}
+		}
+	}
+
+	if c.Idempotency.TTL < Duration(time.Minute) {
+		add("idempotency.ttl (IDEMPOTENCY_TTL) must be at least 1m")
+	}
+
+	if c.Tracing.Enabled {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("tracing.endpoint (TRACING_ENDPOINT) must be an http(s) URL such as http://localhost:4318, got %q", c.Tracing.Endpoint)
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			add("tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+		}
+		if c.Tracing.ServiceName == "" {
+			add("tracing.service_name (TRACING_SERVICE_NAME) is required")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration (profile %s):\n  - %s", c.Profile, strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
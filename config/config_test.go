@@ -0,0 +1,244 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/seleraseblak/backend/ratelimit"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setRequiredEnv mengisi variabel minimum supaya Validate lolos
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_USER", "seblak")
+	t.Setenv("DB_NAME", "seblak")
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	cfg, err := Load(Options{})
+	require.NoError(t, err)
+	assert.Equal(t, ProfileDevelopment, cfg.Profile)
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, Duration(15*time.Second), cfg.Server.RequestTimeout)
+	assert.Equal(t, Duration(24*time.Hour), cfg.Idempotency.TTL)
+	assert.Contains(t, cfg.Server.CORSOrigins, "http://localhost:3000")
+	assert.Equal(t, "disable", cfg.Database.SSLMode)
+	assert.True(t, cfg.Database.AutoMigrate)
+	assert.Equal(t, "memory", cfg.Cache.Driver)
+	assert.Equal(t, Duration(5*time.Minute), cfg.Cache.TTL)
+	assert.Equal(t, "text", cfg.Log.Format)
+	assert.Equal(t, Duration(200*time.Millisecond), cfg.Log.SlowQuery)
+	assert.False(t, cfg.Tracing.Enabled)
+	assert.Equal(t, 1.0, cfg.Tracing.SampleRatio)
+
+	t.Setenv("APP_ENV", ProfileProduction)
+	cfg, err = Load(Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "require", cfg.Database.SSLMode)
+	assert.NotContains(t, cfg.Server.CORSOrigins, "http://localhost:3000")
+	assert.Equal(t, "json", cfg.Log.Format)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	setRequiredEnv(t)
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+database:
+  port: 6432
+  name: from_file
+cache:
+  ttl: 1m
+profiles:
+  production:
+    database:
+      name: from_profile
+    cache:
+      driver: none
+`)
+
+	cfg, err := Load(Options{File: path})
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.Server.Port)
+	assert.Equal(t, 6432, cfg.Database.Port)
+	// Environment menang atas file
+	assert.Equal(t, "seblak", cfg.Database.Name)
+	assert.Equal(t, Duration(time.Minute), cfg.Cache.TTL)
+	assert.Equal(t, "memory", cfg.Cache.Driver)
+
+	os.Unsetenv("DB_NAME")
+	cfg, err = Load(Options{File: path, Profile: ProfileProduction})
+	require.NoError(t, err)
+	assert.Equal(t, "from_profile", cfg.Database.Name)
+	assert.Equal(t, "none", cfg.Cache.Driver)
+	// Nilai yang tidak disebut profil tetap dari bagian utama file
+	assert.Equal(t, 6432, cfg.Database.Port)
+
+	t.Setenv("PORT", "9100")
+	flags := pflag.NewFlagSet("serve", pflag.ContinueOnError)
+	flags.Int("port", 8080, "")
+	cfg, err = Load(Options{File: path, Flags: flags})
+	require.NoError(t, err)
+	assert.Equal(t, 9100, cfg.Server.Port, "flag not set by user must not override")
+
+	require.NoError(t, flags.Parse([]string{"--port", "9200"}))
+	cfg, err = Load(Options{File: path, Flags: flags})
+	require.NoError(t, err)
+	assert.Equal(t, 9200, cfg.Server.Port)
+}
+
+func TestLoadTOML(t *testing.T) {
+	setRequiredEnv(t)
+	path := writeFile(t, "config.toml", `
+[server]
+cors_origins = ["https://admin.seleraseblak.com"]
+
+[cache]
+driver = "redis"
+ttl = "30s"
+
+[cache.redis]
+addr = "redis:6379"
+
+[profiles.staging.storage]
+driver = "s3"
+`)
+
+	cfg, err := Load(Options{File: path})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://admin.seleraseblak.com"}, cfg.Server.CORSOrigins)
+	assert.Equal(t, "redis", cfg.Cache.Driver)
+	assert.Equal(t, "redis:6379", cfg.Cache.Redis.Addr)
+	assert.Equal(t, Duration(30*time.Second), cfg.Cache.TTL)
+
+	// Profil staging meminta s3 tanpa kredensial
+	_, err = Load(Options{File: path, Profile: ProfileStaging})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "S3_*")
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	setRequiredEnv(t)
+	path := writeFile(t, "config.yaml", "database:\n  hots: db\n")
+
+	_, err := Load(Options{File: path})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hots")
+
+	_, err = Load(Options{File: writeFile(t, "config.json", "{}")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported format")
+}
+
+func TestLoadUnknownProfile(t *testing.T) {
+	setRequiredEnv(t)
+
+	_, err := Load(Options{Profile: "qa"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown profile "qa"`)
+
+	path := writeFile(t, "config.yaml", "profiles:\n  qa:\n    server:\n      port: 9300\n")
+	cfg, err := Load(Options{File: path, Profile: "qa"})
+	require.NoError(t, err)
+	assert.Equal(t, 9300, cfg.Server.Port)
+}
+
+func TestLoadSecretFromFile(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("DB_PASSWORD_FILE", writeFile(t, "db_password", "s3cr3t 'quoted'\n"))
+
+	cfg, err := Load(Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t 'quoted'", cfg.Database.Password)
+	assert.Contains(t, cfg.Database.DSN(), `password='s3cr3t \'quoted\''`)
+
+	t.Setenv("DB_PASSWORD", "other")
+	_, err = Load(Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "set only one of DB_PASSWORD and DB_PASSWORD_FILE")
+}
+
+func TestLoadReportsAllProblems(t *testing.T) {
+	t.Setenv("PORT", "abc")
+	_, err := Load(Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `PORT must be a number, got "abc"`)
+
+	os.Unsetenv("PORT")
+	t.Setenv("CACHE_DRIVER", "memcached")
+	t.Setenv("DB_SSLMODE", "on")
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("IDEMPOTENCY_TTL", "10s")
+	_, err = Load(Options{})
+	require.Error(t, err)
+	for _, want := range []string{"DB_HOST", "DB_USER", "DB_NAME", "DB_SSLMODE", "CACHE_DRIVER", "LOG_LEVEL", "IDEMPOTENCY_TTL"} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadTracing(t *testing.T) {
+	setRequiredEnv(t)
+	// Endpoint dan rasio hanya divalidasi saat tracing aktif
+	t.Setenv("TRACING_ENDPOINT", "localhost:4318")
+	t.Setenv("TRACING_SAMPLE_RATIO", "2")
+	_, err := Load(Options{})
+	require.NoError(t, err)
+
+	t.Setenv("TRACING_ENABLED", "true")
+	_, err = Load(Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TRACING_ENDPOINT")
+	assert.Contains(t, err.Error(), "TRACING_SAMPLE_RATIO")
+
+	t.Setenv("TRACING_ENDPOINT", "http://otel-collector:4318")
+	t.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+	cfg, err := Load(Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, cfg.Tracing.SampleRatio)
+	assert.Equal(t, "http://otel-collector:4318", cfg.Tracing.Endpoint)
+}
+
+func TestLoadRateLimit(t *testing.T) {
+	setRequiredEnv(t)
+	cfg, err := Load(Options{})
+	require.NoError(t, err)
+	assert.True(t, cfg.RateLimit.Enabled)
+	assert.Equal(t, ratelimit.Rule{Limit: 10, Period: time.Minute}, cfg.RateLimit.Voucher)
+
+	path := writeFile(t, "config.yaml", "rate_limit:\n  write: 30/1m\n")
+	cfg, err = Load(Options{File: path})
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.Rule{Limit: 30, Period: time.Minute}, cfg.RateLimit.Write)
+
+	t.Setenv("RATE_LIMIT_VOUCHER", "5/10m")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 172.16.0.1")
+	cfg, err = Load(Options{})
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.Rule{Limit: 5, Period: 10 * time.Minute}, cfg.RateLimit.Voucher)
+	assert.Equal(t, []string{"10.0.0.0/8", "172.16.0.1"}, cfg.Server.TrustedProxies)
+
+	t.Setenv("RATE_LIMIT_READ", "lots")
+	_, err = Load(Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RATE_LIMIT_READ")
+
+	os.Unsetenv("RATE_LIMIT_READ")
+	t.Setenv("RATE_LIMIT_DRIVER", "redis")
+	_, err = Load(Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "REDIS_ADDR")
+}
@@ -1,28 +1,152 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
-func InitDB() (*gorm.DB, error) {
-	host := os.Getenv("DB_HOST")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	port := os.Getenv("DB_PORT")
+// Jeda antar percobaan koneksi saat start, dilipatgandakan sampai maxRetryDelay
+const (
+	initialRetryDelay = 500 * time.Millisecond
+	maxRetryDelay     = 10 * time.Second
+)
+
+// InitDB membuka koneksi ke primary, menunggu sampai database siap (misalnya di docker-compose
+// saat Postgres baru start) lalu mengatur pool. Jika ada read replica, query baca di luar
+// transaksi diarahkan ke replica dan semua tulis serta transaksi tetap ke primary.
+// queryLog dipakai setelah koneksi siap.
+func InitDB(cfg DatabaseConfig, queryLog logger.Interface) (*gorm.DB, error) {
+	var db *gorm.DB
+	err := retry(time.Duration(cfg.ConnectRetryTimeout), time.Sleep, func(attempt int) error {
+		var err error
+		// Logger gorm dimatikan selama menunggu supaya setiap percobaan hanya dicatat sekali
+		db, err = gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if err != nil && !permanentDBError(err) {
+			slog.Warn("database not ready", "attempt", attempt, "error", err)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("connecting to database %s:%d: %w", cfg.Host, cfg.Port, err)
+	}
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		host, user, password, dbname, port)
+	db.Logger = queryLog
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime))
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime))
 
-	fmt.Println("Connected to database")
+	if len(cfg.ReadReplicas) > 0 {
+		replicas := make([]gorm.Dialector, 0, len(cfg.ReadReplicas))
+		for _, addr := range cfg.ReadReplicas {
+			replica, err := cfg.replica(addr)
+			if err != nil {
+				return nil, err
+			}
+			replicas = append(replicas, postgres.Open(replica.DSN()))
+		}
+		resolver := dbresolver.Register(dbresolver.Config{
+			Replicas: replicas,
+			Policy:   dbresolver.RandomPolicy{},
+		}).
+			SetMaxOpenConns(cfg.MaxOpenConns).
+			SetMaxIdleConns(cfg.MaxIdleConns).
+			SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime)).
+			SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime))
+		if err := db.Use(resolver); err != nil {
+			return nil, fmt.Errorf("configuring read replicas: %w", err)
+		}
+	}
+
+	slog.Info("connected to database", "host", cfg.Host, "name", cfg.Name, "replicas", len(cfg.ReadReplicas))
 	return db, nil
 }
+
+// retry menjalankan fn sampai berhasil, error-nya permanen atau timeout habis.
+// Jeda dimulai dari initialRetryDelay dan dilipatgandakan.
+func retry(timeout time.Duration, sleep func(time.Duration), fn func(attempt int) error) error {
+	delay := initialRetryDelay
+	var waited time.Duration
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		if err == nil || permanentDBError(err) || waited+delay > timeout {
+			return err
+		}
+		sleep(delay)
+		waited += delay
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// permanentDBError bernilai true untuk error dari server yang tidak hilang dengan menunggu:
+// login ditolak (kelas 28) atau database tidak ada
+func permanentDBError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "28") || pgErr.Code == "3D000"
+	}
+	return false
+}
+
+// DSN menyusun connection string libpq. Nilai di-quote supaya password dengan spasi atau
+// tanda kutip tetap terbaca.
+func (c DatabaseConfig) DSN() string {
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	dsn := fmt.Sprintf("host='%s' port=%d user='%s' password='%s' dbname='%s' sslmode=%s connect_timeout=%d",
+		quote.Replace(c.Host), c.Port, quote.Replace(c.User), quote.Replace(c.Password), quote.Replace(c.Name), c.SSLMode,
+		int(time.Duration(c.ConnectTimeout)/time.Second))
+	for _, opt := range [][2]string{{"sslrootcert", c.SSLRootCert}, {"sslcert", c.SSLCert}, {"sslkey", c.SSLKey}} {
+		if opt[1] != "" {
+			dsn += fmt.Sprintf(" %s='%s'", opt[0], quote.Replace(opt[1]))
+		}
+	}
+	return dsn
+}
+
+// replica mengembalikan konfigurasi primary dengan host dan port replica
+func (c DatabaseConfig) replica(addr string) (DatabaseConfig, error) {
+	host, port, err := splitHostPort(addr, c.Port)
+	if err != nil {
+		return c, err
+	}
+	c.Host, c.Port = host, port
+	return c, nil
+}
+
+// splitHostPort memecah "host" atau "host:port"; tanpa port dipakai defaultPort
+func splitHostPort(addr string, defaultPort int) (string, int, error) {
+	if !strings.Contains(addr, ":") {
+		if addr == "" {
+			return "", 0, fmt.Errorf("empty replica address")
+		}
+		return addr, defaultPort, nil
+	}
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid replica address %q: %v", addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 || host == "" {
+		return "", 0, fmt.Errorf("invalid replica address %q", addr)
+	}
+	return host, port, nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBacksOffUntilTimeout(t *testing.T) {
+	var slept []time.Duration
+	sleep := func(d time.Duration) { slept = append(slept, d) }
+	refused := errors.New("connection refused")
+
+	attempts := 0
+	err := retry(20*time.Second, sleep, func(int) error {
+		attempts++
+		return refused
+	})
+	assert.ErrorIs(t, err, refused)
+	// 0.5 + 1 + 2 + 4 + 8 = 15.5s, jeda berikutnya (10s, batas atas) melewati 20s
+	assert.Equal(t, []time.Duration{
+		500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+	}, slept)
+	assert.Equal(t, 6, attempts)
+
+	slept = nil
+	err = retry(time.Minute, sleep, func(attempt int) error {
+		if attempt < 3 {
+			return refused
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, slept, 2)
+
+	slept = nil
+	err = retry(0, sleep, func(int) error { return refused })
+	assert.ErrorIs(t, err, refused)
+	assert.Empty(t, slept, "timeout 0 must not retry")
+}
+
+func TestRetryStopsOnPermanentError(t *testing.T) {
+	authFailed := fmt.Errorf("failed to connect: %w", &pgconn.PgError{Code: "28P01"})
+	attempts := 0
+	err := retry(time.Minute, func(time.Duration) {}, func(int) error {
+		attempts++
+		return authFailed
+	})
+	assert.ErrorIs(t, err, authFailed)
+	assert.Equal(t, 1, attempts)
+
+	assert.True(t, permanentDBError(&pgconn.PgError{Code: "3D000"}))
+	// Server yang masih start (57P03) ditunggu
+	assert.False(t, permanentDBError(&pgconn.PgError{Code: "57P03"}))
+}
+
+func TestDSN(t *testing.T) {
+	cfg := DatabaseConfig{
+		Host: "db", Port: 5432, User: "seblak", Password: `p@ss 'w\rd`, Name: "seblak",
+		SSLMode: "verify-full", SSLRootCert: "/certs/ca.pem", ConnectTimeout: Duration(5 * time.Second),
+	}
+	assert.Equal(t,
+		`host='db' port=5432 user='seblak' password='p@ss \'w\\rd' dbname='seblak' sslmode=verify-full connect_timeout=5 sslrootcert='/certs/ca.pem'`,
+		cfg.DSN())
+
+	replica, err := cfg.replica("replica-1:6432")
+	require.NoError(t, err)
+	assert.Equal(t, "replica-1", replica.Host)
+	assert.Equal(t, 6432, replica.Port)
+	assert.Equal(t, cfg.SSLRootCert, replica.SSLRootCert)
+
+	replica, err = cfg.replica("replica-2")
+	require.NoError(t, err)
+	assert.Equal(t, 5432, replica.Port)
+
+	_, err = cfg.replica("replica-3:abc")
+	assert.Error(t, err)
+}
+
+func TestValidatePool(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("DB_MAX_OPEN_CONNS", "4")
+	t.Setenv("DB_MAX_IDLE_CONNS", "8")
+	t.Setenv("DB_SSLCERT", "/certs/client.pem")
+	t.Setenv("DB_READ_REPLICAS", "replica-1, replica-2:bad")
+
+	_, err := Load(Options{})
+	require.Error(t, err)
+	for _, want := range []string{"DB_MAX_IDLE_CONNS", "DB_SSLKEY", `"replica-2:bad"`} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
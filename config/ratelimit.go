@@ -0,0 +1,33 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/seleraseblak/backend/ratelimit"
+)
+
+// InitRateLimiter memilih penyimpanan bucket dari rate_limit.driver. Driver redis memakai
+// server dari cache.redis. Mengembalikan nil jika rate limit dimatikan.
+func InitRateLimiter(cfg RateLimitConfig, redisCfg RedisConfig) (ratelimit.Limiter, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	switch cfg.Driver {
+	case "memory":
+		return ratelimit.NewMemory(), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     redisCfg.Addr,
+			Password: redisCfg.Password,
+			DB:       redisCfg.DB,
+		})
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			return nil, fmt.Errorf("connecting to redis: %w", err)
+		}
+		return ratelimit.NewRedis(client, "seleraseblak:ratelimit:"), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/seleraseblak/backend/ratelimit"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// applyFile menimpa cfg dengan isi file, lalu dengan bagian profiles.<profile> jika ada.
+// Mengembalikan true jika profil didefinisikan di file.
+func applyFile(cfg *Config, path, profile string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var unmarshal func([]byte, interface{}) error
+	var decode func([]byte, interface{}) error
+	var marshal func(interface{}) ([]byte, error)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		unmarshal, marshal = yaml.Unmarshal, yaml.Marshal
+		decode = func(b []byte, v interface{}) error {
+			dec := yaml.NewDecoder(bytes.NewReader(b))
+			dec.KnownFields(true)
+			return dec.Decode(v)
+		}
+	case ".toml":
+		unmarshal, marshal = toml.Unmarshal, toml.Marshal
+		decode = func(b []byte, v interface{}) error {
+			return toml.NewDecoder(bytes.NewReader(b)).DisallowUnknownFields().Decode(v)
+		}
+	default:
+		return false, fmt.Errorf("config file %s: unsupported format, use .yaml, .yml or .toml", path)
+	}
+
+	var raw map[string]interface{}
+	if err := unmarshal(data, &raw); err != nil {
+		return false, fmt.Errorf("config file %s: %w", path, err)
+	}
+	profiles, _ := raw["profiles"].(map[string]interface{})
+	delete(raw, "profiles")
+
+	// Setiap bagian ditulis ulang lalu di-decode ke cfg, supaya field yang tidak disebut tetap
+	// memakai nilai sebelumnya dan field yang tidak dikenal ditolak
+	sections := []map[string]interface{}{raw}
+	override, found := profiles[profile]
+	if found {
+		section, ok := override.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("config file %s: profiles.%s must be a table", path, profile)
+		}
+		sections = append(sections, section)
+	}
+	for _, section := range sections {
+		if len(section) == 0 {
+			continue
+		}
+		b, err := marshal(section)
+		if err != nil {
+			return false, fmt.Errorf("config file %s: %w", path, err)
+		}
+		if err := decode(b, cfg); err != nil {
+			return false, fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+	return found, nil
+}
+
+// envReader mengumpulkan error parsing supaya semua variabel yang salah dilaporkan sekaligus
+type envReader struct {
+	errs []string
+}
+
+func (e *envReader) string(name string, dst *string) {
+	if v, ok := os.LookupEnv(name); ok {
+		*dst = v
+	}
+}
+
+// secret membaca NAME atau isi file di NAME_FILE (misalnya Docker/Kubernetes secret)
+func (e *envReader) secret(name string, dst *string) {
+	v, ok := os.LookupEnv(name)
+	file, fromFile := os.LookupEnv(name + "_FILE")
+	switch {
+	case ok && fromFile:
+		e.errs = append(e.errs, fmt.Sprintf("set only one of %s and %s_FILE", name, name))
+	case fromFile:
+		data, err := os.ReadFile(file)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s_FILE: %v", name, err))
+			return
+		}
+		*dst = strings.TrimRight(string(data), "\r\n")
+	case ok:
+		*dst = v
+	}
+}
+
+func (e *envReader) int(name string, dst *int) {
+	if v, ok := os.LookupEnv(name); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s must be a number, got %q", name, v))
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envReader) float(name string, dst *float64) {
+	if v, ok := os.LookupEnv(name); ok {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s must be a number, got %q", name, v))
+			return
+		}
+		*dst = f
+	}
+}
+
+func (e *envReader) bool(name string, dst *bool) {
+	if v, ok := os.LookupEnv(name); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s must be true or false, got %q", name, v))
+			return
+		}
+		*dst = b
+	}
+}
+
+func (e *envReader) duration(name string, dst *Duration) {
+	if v, ok := os.LookupEnv(name); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s must be a duration such as 5m, got %q", name, v))
+			return
+		}
+		*dst = Duration(d)
+	}
+}
+
+func (e *envReader) rule(name string, dst *ratelimit.Rule) {
+	if v, ok := os.LookupEnv(name); ok {
+		rule, err := ratelimit.ParseRule(v)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		*dst = rule
+	}
+}
+
+// list membaca daftar yang dipisah koma
+func (e *envReader) list(name string, dst *[]string) {
+	if v, ok := os.LookupEnv(name); ok {
+		var items []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*dst = items
+	}
+}
+
+func applyEnv(cfg *Config) error {
+	e := &envReader{}
+	e.int("PORT", &cfg.Server.Port)
+	e.list("CORS_ORIGINS", &cfg.Server.CORSOrigins)
+	e.duration("SHUTDOWN_DELAY", &cfg.Server.ShutdownDelay)
+	e.duration("SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+	e.duration("REQUEST_TIMEOUT", &cfg.Server.RequestTimeout)
+	e.duration("UPLOAD_TIMEOUT", &cfg.Server.UploadTimeout)
+	e.list("TRUSTED_PROXIES", &cfg.Server.TrustedProxies)
+
+	e.string("DB_HOST", &cfg.Database.Host)
+	e.int("DB_PORT", &cfg.Database.Port)
+	e.string("DB_USER", &cfg.Database.User)
+	e.secret("DB_PASSWORD", &cfg.Database.Password)
+	e.string("DB_NAME", &cfg.Database.Name)
+	e.string("DB_SSLMODE", &cfg.Database.SSLMode)
+	e.string("DB_SSLROOTCERT", &cfg.Database.SSLRootCert)
+	e.string("DB_SSLCERT", &cfg.Database.SSLCert)
+	e.string("DB_SSLKEY", &cfg.Database.SSLKey)
+	e.bool("DB_AUTO_MIGRATE", &cfg.Database.AutoMigrate)
+	e.int("DB_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns)
+	e.int("DB_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns)
+	e.duration("DB_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime)
+	e.duration("DB_CONN_MAX_IDLE_TIME", &cfg.Database.ConnMaxIdleTime)
+	e.duration("DB_CONNECT_TIMEOUT", &cfg.Database.ConnectTimeout)
+	e.duration("DB_CONNECT_RETRY_TIMEOUT", &cfg.Database.ConnectRetryTimeout)
+	e.list("DB_READ_REPLICAS", &cfg.Database.ReadReplicas)
+
+	e.string("STORAGE_DRIVER", &cfg.Storage.Driver)
+	e.string("STORAGE_LOCAL_DIR", &cfg.Storage.LocalDir)
+	e.string("STORAGE_PUBLIC_URL", &cfg.Storage.PublicURL)
+	e.string("S3_ENDPOINT", &cfg.Storage.S3.Endpoint)
+	e.string("S3_REGION", &cfg.Storage.S3.Region)
+	e.string("S3_BUCKET", &cfg.Storage.S3.Bucket)
+	e.secret("S3_ACCESS_KEY_ID", &cfg.Storage.S3.AccessKeyID)
+	e.secret("S3_SECRET_ACCESS_KEY", &cfg.Storage.S3.SecretAccessKey)
+	e.bool("S3_PATH_STYLE", &cfg.Storage.S3.PathStyle)
+
+	e.string("CACHE_DRIVER", &cfg.Cache.Driver)
+	e.duration("CACHE_TTL", &cfg.Cache.TTL)
+	e.int("CACHE_MAX_ENTRIES", &cfg.Cache.MaxEntries)
+	e.string("REDIS_ADDR", &cfg.Cache.Redis.Addr)
+	e.secret("REDIS_PASSWORD", &cfg.Cache.Redis.Password)
+	e.int("REDIS_DB", &cfg.Cache.Redis.DB)
+
+	e.string("LOG_FORMAT", &cfg.Log.Format)
+	e.string("LOG_LEVEL", &cfg.Log.Level)
+	e.duration("LOG_SLOW_QUERY", &cfg.Log.SlowQuery)
+
+	e.bool("METRICS_ENABLED", &cfg.Metrics.Enabled)
+	e.secret("METRICS_TOKEN", &cfg.Metrics.Token)
+
+	e.bool("RATE_LIMIT_ENABLED", &cfg.RateLimit.Enabled)
+	e.string("RATE_LIMIT_DRIVER", &cfg.RateLimit.Driver)
+	e.rule("RATE_LIMIT_READ", &cfg.RateLimit.Read)
+	e.rule("RATE_LIMIT_WRITE", &cfg.RateLimit.Write)
+	e.rule("RATE_LIMIT_VOUCHER", &cfg.RateLimit.Voucher)
+
+	e.duration("IDEMPOTENCY_TTL", &cfg.Idempotency.TTL)
+
+	e.bool("TRACING_ENABLED", &cfg.Tracing.Enabled)
+	e.string("TRACING_ENDPOINT", &cfg.Tracing.Endpoint)
+	e.float("TRACING_SAMPLE_RATIO", &cfg.Tracing.SampleRatio)
+	e.string("TRACING_SERVICE_NAME", &cfg.Tracing.ServiceName)
+
+	if len(e.errs) > 0 {
+		return fmt.Errorf("invalid environment:\n  - %s", strings.Join(e.errs, "\n  - "))
+	}
+	return nil
+}
+
+// applyFlags menerapkan flag CLI yang di-set user. Flag yang tidak dikenal perintah ini diabaikan.
+func applyFlags(cfg *Config, flags *pflag.FlagSet) error {
+	if flags == nil {
+		return nil
+	}
+	if f := flags.Lookup("port"); f != nil && f.Changed {
+		port, err := strconv.Atoi(f.Value.String())
+		if err != nil {
+			return fmt.Errorf("invalid --port: %w", err)
+		}
+		cfg.Server.Port = port
+	}
+	return nil
+}
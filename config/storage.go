@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/seleraseblak/backend/storage"
+)
+
+// InitStorage memilih backend foto dari storage.driver (local atau s3)
+func InitStorage(cfg StorageConfig) (storage.Storage, error) {
+	switch cfg.Driver {
+	case "local":
+		baseURL := cfg.PublicURL
+		if baseURL == "" {
+			baseURL = "/uploads"
+		}
+		return storage.NewLocal(cfg.LocalDir, baseURL)
+	case "s3":
+		return storage.NewS3(storage.S3Config{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			PathStyle:       cfg.S3.PathStyle,
+			PublicURL:       cfg.PublicURL,
+		}, nil)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
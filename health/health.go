@@ -0,0 +1,99 @@
+// Package health menjalankan pengecekan dependency (database, migrasi, cache) untuk endpoint readiness.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+// CheckFunc memeriksa satu dependency. Detail opsional ikut ditampilkan di laporan.
+type CheckFunc func(ctx context.Context) (detail string, err error)
+
+// Result adalah hasil satu pengecekan
+type Result struct {
+	Status    string `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// Report adalah hasil semua pengecekan; Status ok hanya jika semuanya ok dan server tidak sedang berhenti
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+type namedCheck struct {
+	name string
+	fn   CheckFunc
+}
+
+type Checker struct {
+	timeout  time.Duration
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+// New membuat Checker; setiap pengecekan dibatasi timeout
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add mendaftarkan pengecekan. Dipanggil saat start, sebelum Check dipakai.
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.checks = append(c.checks, namedCheck{name: name, fn: fn})
+}
+
+// SetDraining membuat readiness gagal supaya load balancer berhenti mengirim request
+// selama server berhenti
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Check menjalankan semua pengecekan secara paralel
+func (c *Checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(c.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func(check namedCheck) {
+			defer wg.Done()
+			start := time.Now()
+			detail, err := check.fn(ctx)
+			result := Result{Status: StatusOK, Detail: detail, LatencyMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.name] = result
+			if err != nil {
+				report.Status = StatusFail
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	if c.Draining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	c := New(time.Second)
+	c.Add("database", func(ctx context.Context) (string, error) { return "open 1", nil })
+	c.Add("cache", func(ctx context.Context) (string, error) { return "", errors.New("connection refused") })
+
+	report := c.Check(context.Background())
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, Result{Status: StatusOK, Detail: "open 1", LatencyMS: report.Checks["database"].LatencyMS}, report.Checks["database"])
+	assert.Equal(t, StatusFail, report.Checks["cache"].Status)
+	assert.Equal(t, "connection refused", report.Checks["cache"].Error)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	c := New(10 * time.Millisecond)
+	c.Add("database", func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	report := c.Check(context.Background())
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+}
+
+func TestDraining(t *testing.T) {
+	c := New(time.Second)
+	c.Add("database", func(ctx context.Context) (string, error) { return "", nil })
+	assert.Equal(t, StatusOK, c.Check(context.Background()).Status)
+
+	c.SetDraining()
+	report := c.Check(context.Background())
+	assert.Equal(t, StatusDraining, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["database"].Status)
+}
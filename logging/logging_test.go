@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	buf.Reset()
+	return lines
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(&buf, "json", "warn")
+	require.NoError(t, err)
+	log.Info("hidden")
+	log.Warn("shown", "key", "value")
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "shown", lines[0]["msg"])
+	assert.Equal(t, "value", lines[0]["key"])
+
+	log, err = New(&buf, "text", "debug")
+	require.NoError(t, err)
+	log.Debug("plain")
+	assert.Contains(t, buf.String(), "msg=plain")
+
+	_, err = New(&buf, "xml", "info")
+	assert.Error(t, err)
+	_, err = New(&buf, "json", "verbose")
+	assert.Error(t, err)
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	log := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+	ctx := WithLogger(context.Background(), log)
+	assert.Same(t, log, FromContext(ctx))
+}
+
+func TestGormLoggerTrace(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(&buf, "json", "info")
+	require.NoError(t, err)
+	ctx := WithLogger(context.Background(), log.With("request_id", "req-1"))
+	gormLog := NewGormLogger(100 * time.Millisecond)
+	query := func() (string, int64) { return "SELECT * FROM stores", 3 }
+
+	// Query cepat hanya dicatat di level debug
+	gormLog.Trace(ctx, time.Now(), query, nil)
+	assert.Empty(t, decodeLines(t, &buf))
+
+	gormLog.Trace(ctx, time.Now().Add(-200*time.Millisecond), query, nil)
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "slow query", lines[0]["msg"])
+	assert.Equal(t, "WARN", lines[0]["level"])
+	assert.Equal(t, "req-1", lines[0]["request_id"])
+	assert.Equal(t, "SELECT * FROM stores", lines[0]["sql"])
+	assert.Equal(t, float64(3), lines[0]["rows"])
+	assert.GreaterOrEqual(t, lines[0]["duration_ms"], float64(200))
+
+	gormLog.Trace(ctx, time.Now(), query, errors.New("relation does not exist"))
+	lines = decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "query failed", lines[0]["msg"])
+	assert.Equal(t, "relation does not exist", lines[0]["error"])
+
+	// Not found dipakai service untuk 404, bukan error
+	gormLog.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)
+	assert.Empty(t, decodeLines(t, &buf))
+
+	gormLog.LogMode(logger.Silent).Trace(ctx, time.Now().Add(-time.Second), query, errors.New("ignored"))
+	assert.Empty(t, decodeLines(t, &buf))
+}
+
+func TestGormLoggerDebugLogsAllQueries(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(&buf, "json", "debug")
+	require.NoError(t, err)
+	ctx := WithLogger(context.Background(), log)
+
+	NewGormLogger(0).Trace(ctx, time.Now().Add(-time.Second), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "query", lines[0]["msg"])
+	assert.Equal(t, "DEBUG", lines[0]["level"])
+}
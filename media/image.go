@@ -0,0 +1,101 @@
+// Package media membuat varian ukuran foto produk (JPEG dan WebP) tanpa cgo
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+
+	"github.com/seleraseblak/backend/media/webp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Size adalah ukuran varian; sisi terpanjang gambar dikecilkan ke MaxSide
+type Size struct {
+	Name    string
+	MaxSide int
+}
+
+// Sizes adalah varian yang dibuat untuk setiap foto, dari kecil ke besar
+var Sizes = []Size{
+	{Name: "thumbnail", MaxSide: 200},
+	{Name: "medium", MaxSide: 600},
+	{Name: "large", MaxSide: 1200},
+}
+
+const (
+	JPEGQuality = 82
+	WebPQuality = 75
+)
+
+// Variant adalah satu ukuran foto yang sudah dienkode
+type Variant struct {
+	Size
+	Width, Height int
+	JPEG          []byte
+	WebP          []byte
+}
+
+// Decode membaca JPEG, PNG atau WebP, meratakan transparansi ke latar putih
+// dan memutar gambar sesuai orientasi EXIF dari kamera HP
+func Decode(data []byte) (*image.RGBA, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	b := src.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(img, img.Bounds(), src, b.Min, draw.Over)
+	return orient(img, Orientation(data)), nil
+}
+
+// Variants membuat semua ukuran di Sizes. Gambar tidak pernah diperbesar, dan setiap
+// ukuran diturunkan dari ukuran sebelumnya yang lebih besar supaya cepat.
+func Variants(img image.Image) ([]Variant, error) {
+	variants := make([]Variant, len(Sizes))
+	src := img
+	for i := len(Sizes) - 1; i >= 0; i-- {
+		resized := Resize(src, Sizes[i].MaxSide)
+		v := Variant{Size: Sizes[i], Width: resized.Bounds().Dx(), Height: resized.Bounds().Dy()}
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+			return nil, fmt.Errorf("media: encode %s jpeg: %w", v.Name, err)
+		}
+		v.JPEG = buf.Bytes()
+
+		buf = bytes.Buffer{}
+		if err := webp.Encode(&buf, resized, &webp.Options{Quality: WebPQuality}); err != nil {
+			return nil, fmt.Errorf("media: encode %s webp: %w", v.Name, err)
+		}
+		v.WebP = buf.Bytes()
+
+		variants[i] = v
+		src = resized
+	}
+	return variants, nil
+}
+
+// Resize mengecilkan gambar sampai sisi terpanjangnya maxSide dengan rasio tetap
+func Resize(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+	if w >= h {
+		h = max(1, (h*maxSide+w/2)/w)
+		w = maxSide
+	} else {
+		w = max(1, (w*maxSide+h/2)/h)
+		h = maxSide
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
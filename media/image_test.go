@@ -0,0 +1,135 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xwebp "golang.org/x/image/webp"
+)
+
+// jpegWithOrientation menyisipkan segmen APP1 EXIF berisi tag orientasi setelah SOI
+func jpegWithOrientation(t *testing.T, img image.Image, orientation uint16) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}))
+	data := buf.Bytes()
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	return append(out, data[2:]...)
+}
+
+// markedImage berwarna biru dengan pojok kiri atas merah untuk mengecek orientasi
+func markedImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{B: 255, A: 255}
+			if x < w/4 && y < h/4 {
+				c = color.RGBA{R: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func isRed(c color.Color) bool {
+	r, _, b, _ := c.RGBA()
+	return r > 0xC000 && b < 0x4000
+}
+
+func TestOrientation(t *testing.T) {
+	img := markedImage(80, 40)
+	for o := uint16(1); o <= 8; o++ {
+		assert.Equal(t, int(o), Orientation(jpegWithOrientation(t, img, o)))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	assert.Equal(t, 1, Orientation(buf.Bytes()))
+	assert.Equal(t, 1, Orientation([]byte{0xFF, 0xD8, 0xFF}))
+}
+
+func TestDecodeAppliesOrientation(t *testing.T) {
+	img := markedImage(80, 40)
+
+	// Orientasi 6: gambar disimpan miring, tampil setelah diputar 90 derajat searah jarum jam,
+	// sehingga pojok merah pindah ke kanan atas
+	decoded, err := Decode(jpegWithOrientation(t, img, 6))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 40, 80), decoded.Bounds())
+	assert.True(t, isRed(decoded.At(35, 5)))
+	assert.False(t, isRed(decoded.At(5, 5)))
+
+	decoded, err = Decode(jpegWithOrientation(t, img, 3))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 80, 40), decoded.Bounds())
+	assert.True(t, isRed(decoded.At(75, 35)))
+
+	decoded, err = Decode(jpegWithOrientation(t, img, 8))
+	require.NoError(t, err)
+	assert.True(t, isRed(decoded.At(5, 75)))
+}
+
+func TestDecodeFlattensTransparency(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	decoded, err := Decode(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{255, 255, 255, 255}, decoded.RGBAAt(1, 1))
+}
+
+func TestResize(t *testing.T) {
+	assert.Equal(t, image.Rect(0, 0, 600, 450), Resize(markedImage(1600, 1200), 600).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 150, 600), Resize(markedImage(300, 1200), 600).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 1200, 1), Resize(markedImage(3000, 2), 1200).Bounds())
+
+	// Tidak diperbesar
+	small := markedImage(100, 50)
+	assert.Same(t, image.Image(small), Resize(small, 600))
+}
+
+func TestVariants(t *testing.T) {
+	variants, err := Variants(markedImage(1500, 1000))
+	require.NoError(t, err)
+	require.Len(t, variants, len(Sizes))
+
+	want := map[string][2]int{"thumbnail": {200, 133}, "medium": {600, 400}, "large": {1200, 800}}
+	for _, v := range variants {
+		assert.Equal(t, want[v.Name], [2]int{v.Width, v.Height}, v.Name)
+
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(v.JPEG))
+		require.NoError(t, err)
+		assert.Equal(t, v.Width, cfg.Width)
+
+		cfg, err = xwebp.DecodeConfig(bytes.NewReader(v.WebP))
+		require.NoError(t, err)
+		assert.Equal(t, [2]int{v.Width, v.Height}, [2]int{cfg.Width, cfg.Height})
+
+		decoded, err := xwebp.Decode(bytes.NewReader(v.WebP))
+		require.NoError(t, err)
+		assert.True(t, isRed(decoded.At(2, 2)), v.Name)
+	}
+}
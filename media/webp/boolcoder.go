@@ -0,0 +1,74 @@
+package webp
+
+// boolEncoder adalah boolean entropy encoder dari RFC 6386 bagian 7.3
+type boolEncoder struct {
+	buf      []byte
+	rng      uint32
+	bottom   uint32
+	bitCount int
+}
+
+func newBoolEncoder() *boolEncoder {
+	return &boolEncoder{rng: 255, bitCount: 24}
+}
+
+func (e *boolEncoder) putBit(prob uint8, bit bool) {
+	split := 1 + (((e.rng - 1) * uint32(prob)) >> 8)
+	if bit {
+		e.bottom += split
+		e.rng -= split
+	} else {
+		e.rng = split
+	}
+	for e.rng < 128 {
+		e.rng <<= 1
+		if e.bottom&(1<<31) != 0 {
+			e.carry()
+		}
+		e.bottom <<= 1
+		e.bitCount--
+		if e.bitCount == 0 {
+			e.buf = append(e.buf, byte(e.bottom>>24))
+			e.bottom &= (1 << 24) - 1
+			e.bitCount = 8
+		}
+	}
+}
+
+// putLiteral menulis n bit nilai v, MSB dulu, dengan probabilitas 1/2
+func (e *boolEncoder) putLiteral(n int, v uint32) {
+	for i := n - 1; i >= 0; i-- {
+		e.putBit(uniformProb, v>>uint(i)&1 == 1)
+	}
+}
+
+// carry meneruskan carry ke byte yang sudah ditulis
+func (e *boolEncoder) carry() {
+	i := len(e.buf) - 1
+	for i >= 0 && e.buf[i] == 255 {
+		e.buf[i] = 0
+		i--
+	}
+	if i >= 0 {
+		e.buf[i]++
+	}
+}
+
+func (e *boolEncoder) finish() []byte {
+	c := e.bitCount
+	v := e.bottom
+	if v&(1<<uint(32-c)) != 0 {
+		e.carry()
+	}
+	v <<= uint(c & 7)
+	for c >>= 3; c > 0; c-- {
+		v <<= 8
+	}
+	for i := 0; i < 4; i++ {
+		e.buf = append(e.buf, byte(v>>24))
+		v <<= 8
+	}
+	return e.buf
+}
+
+const uniformProb = 128
@@ -0,0 +1,788 @@
+// Package webp berisi encoder WebP lossy (VP8 key frame) murni Go.
+//
+// Encoder ini sengaja sederhana: hanya prediksi 16x16 dan 8x8 (DC, V, H, TM),
+// satu partisi token, probabilitas token default dan tanpa loop filter. Hasilnya
+// tidak sekecil libwebp, tapi jauh lebih kecil dari JPEG ukuran penuh dan bisa
+// dibaca semua browser yang mendukung WebP.
+package webp
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+)
+
+// Options mengatur kualitas encoding, 0 (terkecil) sampai 100 (terbaik)
+type Options struct {
+	Quality float32
+}
+
+const (
+	DefaultQuality = 75
+	maxDimension   = 16383
+)
+
+// Encode menulis m sebagai WebP lossy. Transparansi diratakan ke latar putih.
+func Encode(w io.Writer, m image.Image, o *Options) error {
+	b := m.Bounds()
+	if b.Dx() <= 0 || b.Dy() <= 0 || b.Dx() > maxDimension || b.Dy() > maxDimension {
+		return errors.New("webp: invalid image dimensions")
+	}
+	quality := float32(DefaultQuality)
+	if o != nil {
+		quality = o.Quality
+	}
+
+	e := newEncoder(m, quality)
+	frame := e.encodeFrame()
+
+	size := len(frame)
+	pad := size & 1
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+size+pad))
+	copy(header[8:], "WEBPVP8 ")
+	binary.LittleEndian.PutUint32(header[16:], uint32(size))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(frame); err != nil {
+		return err
+	}
+	if pad == 1 {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+type plane struct {
+	pix    []uint8
+	stride int
+}
+
+func (p *plane) at(x, y int) uint8 { return p.pix[y*p.stride+x] }
+
+type encoder struct {
+	width, height int
+	mbw, mbh      int
+	qIndex        int
+
+	src, rec [3]plane // Y, U, V
+
+	// Faktor kuantisasi [DC, AC]
+	qY1, qY2, qUV [2]int32
+
+	// Konteks non-zero dari blok di atas dan di kiri
+	upY     [][4]uint8
+	upU     [][2]uint8
+	upV     [][2]uint8
+	upY2    []uint8
+	leftY   [4]uint8
+	leftU   [2]uint8
+	leftV   [2]uint8
+	leftY2  uint8
+	modes   *boolEncoder
+	tokens  *boolEncoder
+	modeY   []uint8
+	modeUV  []uint8
+	levels  [25][16]int32 // 16 Y, 4 U, 4 V, 1 Y2
+	ac      [24]bool
+	scratch [16]int32
+}
+
+const (
+	predDC = iota
+	predTM
+	predVE
+	predHE
+)
+
+func newEncoder(m image.Image, quality float32) *encoder {
+	if quality < 0 {
+		quality = 0
+	}
+	if quality > 100 {
+		quality = 100
+	}
+
+	b := m.Bounds()
+	e := &encoder{
+		width:  b.Dx(),
+		height: b.Dy(),
+		mbw:    (b.Dx() + 15) / 16,
+		mbh:    (b.Dy() + 15) / 16,
+		qIndex: int(math.Round(float64(100-quality) * 127 / 100)),
+	}
+	e.qY1 = [2]int32{int32(dequantTableDC[e.qIndex]), int32(dequantTableAC[e.qIndex])}
+	e.qY2 = [2]int32{int32(dequantTableDC[e.qIndex]) * 2, int32(dequantTableAC[e.qIndex]) * 155 / 100}
+	if e.qY2[1] < 8 {
+		e.qY2[1] = 8
+	}
+	uvDC := e.qIndex
+	if uvDC > 117 {
+		uvDC = 117
+	}
+	e.qUV = [2]int32{int32(dequantTableDC[uvDC]), int32(dequantTableAC[e.qIndex])}
+
+	e.upY = make([][4]uint8, e.mbw)
+	e.upU = make([][2]uint8, e.mbw)
+	e.upV = make([][2]uint8, e.mbw)
+	e.upY2 = make([]uint8, e.mbw)
+	e.convert(m)
+	return e
+}
+
+// convert mengubah gambar ke YUV 4:2:0 BT.601 (range 16-235) seperti libwebp,
+// dengan tepi diulang sampai kelipatan 16
+func (e *encoder) convert(m image.Image) {
+	b := m.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(rgba, rgba.Bounds(), m, b.Min, draw.Over)
+
+	w, h := e.mbw*16, e.mbh*16
+	for i := range e.src {
+		cw, ch := w, h
+		if i > 0 {
+			cw, ch = w/2, h/2
+		}
+		e.src[i] = plane{pix: make([]uint8, cw*ch), stride: cw}
+		e.rec[i] = plane{pix: make([]uint8, cw*ch), stride: cw}
+	}
+
+	rgb := func(x, y int) (int32, int32, int32) {
+		if x >= e.width {
+			x = e.width - 1
+		}
+		if y >= e.height {
+			y = e.height - 1
+		}
+		i := rgba.PixOffset(x, y)
+		return int32(rgba.Pix[i]), int32(rgba.Pix[i+1]), int32(rgba.Pix[i+2])
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, bl := rgb(x, y)
+			e.src[0].pix[y*w+x] = uint8((16839*r + 33059*g + 6420*bl + (16 << 16) + (1 << 15)) >> 16)
+		}
+	}
+	for y := 0; y < h/2; y++ {
+		for x := 0; x < w/2; x++ {
+			var r, g, bl int32
+			for dy := 0; dy < 2; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					pr, pg, pb := rgb(2*x+dx, 2*y+dy)
+					r, g, bl = r+pr, g+pg, bl+pb
+				}
+			}
+			// Jumlah 4 piksel, sehingga shift bertambah 2
+			u := (-9719*r - 19081*g + 28800*bl + (128 << 18) + (1 << 17)) >> 18
+			v := (28800*r - 24116*g - 4684*bl + (128 << 18) + (1 << 17)) >> 18
+			e.src[1].pix[y*(w/2)+x] = clip8(u)
+			e.src[2].pix[y*(w/2)+x] = clip8(v)
+		}
+	}
+}
+
+func (e *encoder) encodeFrame() []byte {
+	e.modes = newBoolEncoder()
+	e.tokens = newBoolEncoder()
+	e.writeHeader()
+
+	for mby := 0; mby < e.mbh; mby++ {
+		e.leftY, e.leftU, e.leftV, e.leftY2 = [4]uint8{}, [2]uint8{}, [2]uint8{}, 0
+		for mbx := 0; mbx < e.mbw; mbx++ {
+			e.encodeMacroblock(mbx, mby)
+		}
+	}
+
+	first := e.modes.finish()
+	second := e.tokens.finish()
+
+	out := make([]byte, 10, 10+len(first)+len(second))
+	// Frame tag: key frame, versi 0, ditampilkan, panjang partisi pertama
+	tag := uint32(1<<4) | uint32(len(first))<<5
+	out[0], out[1], out[2] = byte(tag), byte(tag>>8), byte(tag>>16)
+	out[3], out[4], out[5] = 0x9d, 0x01, 0x2a
+	binary.LittleEndian.PutUint16(out[6:], uint16(e.width))
+	binary.LittleEndian.PutUint16(out[8:], uint16(e.height))
+	out = append(out, first...)
+	return append(out, second...)
+}
+
+// writeHeader menulis header frame di partisi pertama (RFC 6386 bagian 9 dan 19.2)
+func (e *encoder) writeHeader() {
+	bw := e.modes
+	bw.putLiteral(1, 0) // color space
+	bw.putLiteral(1, 0) // clamping type
+	bw.putLiteral(1, 0) // tanpa segmentasi
+	bw.putLiteral(1, 0) // filter normal
+	bw.putLiteral(6, 0) // loop filter level 0 = tanpa filter
+	bw.putLiteral(3, 0) // sharpness
+	bw.putLiteral(1, 0) // tanpa delta loop filter
+	bw.putLiteral(2, 0) // satu partisi token
+	bw.putLiteral(7, uint32(e.qIndex))
+	for i := 0; i < 5; i++ {
+		bw.putLiteral(1, 0) // tanpa delta kuantisasi
+	}
+	bw.putLiteral(1, 0) // refresh entropy probs
+	for i := range tokenProbUpdateProb {
+		for j := range tokenProbUpdateProb[i] {
+			for k := range tokenProbUpdateProb[i][j] {
+				for l := range tokenProbUpdateProb[i][j][k] {
+					bw.putBit(tokenProbUpdateProb[i][j][k][l], false)
+				}
+			}
+		}
+	}
+	bw.putLiteral(1, 0) // tanpa flag skip macroblock
+}
+
+func (e *encoder) encodeMacroblock(mbx, mby int) {
+	var predY [256]uint8
+	var predU, predV [64]uint8
+
+	modeY := e.bestMode(0, mbx, mby, 16, predY[:])
+	modeUV := e.bestChromaMode(mbx, mby, predU[:], predV[:])
+
+	// Mode prediksi (partisi pertama)
+	bw := e.modes
+	bw.putBit(145, true) // bukan B_PRED
+	switch modeY {
+	case predDC:
+		bw.putBit(156, false)
+		bw.putBit(163, false)
+	case predVE:
+		bw.putBit(156, false)
+		bw.putBit(163, true)
+	case predHE:
+		bw.putBit(156, true)
+		bw.putBit(128, false)
+	case predTM:
+		bw.putBit(156, true)
+		bw.putBit(128, true)
+	}
+	switch modeUV {
+	case predDC:
+		bw.putBit(142, false)
+	case predVE:
+		bw.putBit(142, true)
+		bw.putBit(114, false)
+	case predHE:
+		bw.putBit(142, true)
+		bw.putBit(114, true)
+		bw.putBit(183, false)
+	case predTM:
+		bw.putBit(142, true)
+		bw.putBit(114, true)
+		bw.putBit(183, true)
+	}
+
+	e.transformLuma(mbx, mby, predY[:])
+	e.transformChroma(1, mbx, mby, predU[:], 16)
+	e.transformChroma(2, mbx, mby, predV[:], 20)
+	e.writeTokens(mbx)
+}
+
+// edges mengembalikan piksel rekonstruksi di atas, di kiri dan pojok kiri atas blok,
+// dengan nilai pengganti 127/129 di tepi frame seperti decoder
+func (e *encoder) edges(p int, mbx, mby, size int) (top, left []int32, topLeft int32) {
+	rec := &e.rec[p]
+	x0, y0 := mbx*size, mby*size
+	top = make([]int32, size)
+	left = make([]int32, size)
+	for i := 0; i < size; i++ {
+		if mby == 0 {
+			top[i] = 127
+		} else {
+			top[i] = int32(rec.at(x0+i, y0-1))
+		}
+		if mbx == 0 {
+			left[i] = 129
+		} else {
+			left[i] = int32(rec.at(x0-1, y0+i))
+		}
+	}
+	switch {
+	case mby == 0:
+		topLeft = 127
+	case mbx == 0:
+		topLeft = 129
+	default:
+		topLeft = int32(rec.at(x0-1, y0-1))
+	}
+	return top, left, topLeft
+}
+
+func predict(mode int, mbx, mby, size int, top, left []int32, topLeft int32, out []uint8) {
+	switch mode {
+	case predDC:
+		var dc int32
+		shift := uint(3)
+		if size == 16 {
+			shift = 4
+		}
+		switch {
+		case mbx == 0 && mby == 0:
+			dc = 128
+		case mbx == 0:
+			sum := int32(size / 2)
+			for _, v := range top {
+				sum += v
+			}
+			dc = sum >> shift
+		case mby == 0:
+			sum := int32(size / 2)
+			for _, v := range left {
+				sum += v
+			}
+			dc = sum >> shift
+		default:
+			sum := int32(size)
+			for i := range top {
+				sum += top[i] + left[i]
+			}
+			dc = sum >> (shift + 1)
+		}
+		for i := range out[:size*size] {
+			out[i] = uint8(dc)
+		}
+	case predVE:
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				out[y*size+x] = uint8(top[x])
+			}
+		}
+	case predHE:
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				out[y*size+x] = uint8(left[y])
+			}
+		}
+	case predTM:
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				out[y*size+x] = clip8(left[y] + top[x] - topLeft)
+			}
+		}
+	}
+}
+
+// bestMode memilih prediksi dengan selisih absolut terkecil terhadap sumber
+func (e *encoder) bestMode(p int, mbx, mby, size int, out []uint8) int {
+	top, left, topLeft := e.edges(p, mbx, mby, size)
+	best, bestCost := predDC, int32(math.MaxInt32)
+	cand := make([]uint8, size*size)
+	for _, mode := range []int{predDC, predVE, predHE, predTM} {
+		predict(mode, mbx, mby, size, top, left, topLeft, cand)
+		cost := e.sad(p, mbx*size, mby*size, size, cand)
+		if cost < bestCost {
+			best, bestCost = mode, cost
+			copy(out, cand)
+		}
+	}
+	return best
+}
+
+func (e *encoder) bestChromaMode(mbx, mby int, outU, outV []uint8) int {
+	topU, leftU, tlU := e.edges(1, mbx, mby, 8)
+	topV, leftV, tlV := e.edges(2, mbx, mby, 8)
+	best, bestCost := predDC, int32(math.MaxInt32)
+	var candU, candV [64]uint8
+	for _, mode := range []int{predDC, predVE, predHE, predTM} {
+		predict(mode, mbx, mby, 8, topU, leftU, tlU, candU[:])
+		predict(mode, mbx, mby, 8, topV, leftV, tlV, candV[:])
+		cost := e.sad(1, mbx*8, mby*8, 8, candU[:]) + e.sad(2, mbx*8, mby*8, 8, candV[:])
+		if cost < bestCost {
+			best, bestCost = mode, cost
+			copy(outU, candU[:])
+			copy(outV, candV[:])
+		}
+	}
+	return best
+}
+
+func (e *encoder) sad(p, x0, y0, size int, pred []uint8) int32 {
+	src := &e.src[p]
+	var sum int32
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			d := int32(src.at(x0+x, y0+y)) - int32(pred[y*size+x])
+			if d < 0 {
+				d = -d
+			}
+			sum += d
+		}
+	}
+	return sum
+}
+
+// transformLuma menghitung koefisien Y dan Y2, lalu merekonstruksi persis seperti decoder
+func (e *encoder) transformLuma(mbx, mby int, pred []uint8) {
+	src, rec := &e.src[0], &e.rec[0]
+	x0, y0 := mbx*16, mby*16
+
+	var coeffs [16][16]int32
+	var dcs [16]int32
+	for n := 0; n < 16; n++ {
+		bx, by := (n%4)*4, (n/4)*4
+		var res [16]int32
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				res[y*4+x] = int32(src.at(x0+bx+x, y0+by+y)) - int32(pred[(by+y)*16+bx+x])
+			}
+		}
+		coeffs[n] = forwardDCT(res)
+		dcs[n] = coeffs[n][0]
+	}
+
+	// Y2: transformasi Walsh-Hadamard dari DC ke-16 blok
+	y2 := forwardWHT(dcs)
+	e.levels[24] = quantize(y2, e.qY2, 0)
+	var deq [16]int32
+	for i := range deq {
+		deq[i] = e.levels[24][i] * e.qY2[btoi(i > 0)]
+	}
+	dcRec := inverseWHT(deq)
+
+	for n := 0; n < 16; n++ {
+		e.levels[n] = quantize(coeffs[n], e.qY1, 1)
+		var c [16]int32
+		c[0] = dcRec[n]
+		for i := 1; i < 16; i++ {
+			c[i] = e.levels[n][i] * e.qY1[1]
+		}
+		bx, by := (n%4)*4, (n/4)*4
+		var block [16]int32
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				block[y*4+x] = int32(pred[(by+y)*16+bx+x])
+			}
+		}
+		inverseDCT(c, &block)
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				rec.pix[(y0+by+y)*rec.stride+x0+bx+x] = uint8(block[y*4+x])
+			}
+		}
+	}
+}
+
+func (e *encoder) transformChroma(p, mbx, mby int, pred []uint8, levelBase int) {
+	src, rec := &e.src[p], &e.rec[p]
+	x0, y0 := mbx*8, mby*8
+	for n := 0; n < 4; n++ {
+		bx, by := (n%2)*4, (n/2)*4
+		var res, block [16]int32
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				pv := int32(pred[(by+y)*8+bx+x])
+				res[y*4+x] = int32(src.at(x0+bx+x, y0+by+y)) - pv
+				block[y*4+x] = pv
+			}
+		}
+		e.levels[levelBase+n] = quantize(forwardDCT(res), e.qUV, 0)
+		var c [16]int32
+		for i := range c {
+			c[i] = e.levels[levelBase+n][i] * e.qUV[btoi(i > 0)]
+		}
+		inverseDCT(c, &block)
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				rec.pix[(y0+by+y)*rec.stride+x0+bx+x] = uint8(block[y*4+x])
+			}
+		}
+	}
+}
+
+// writeTokens menulis koefisien satu macroblock dengan urutan dan konteks yang dibaca decoder
+func (e *encoder) writeTokens(mbx int) {
+	nz := e.putCoeffs(planeY2, e.leftY2+e.upY2[mbx], &e.levels[24], 0)
+	e.leftY2, e.upY2[mbx] = nz, nz
+
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			nz := e.putCoeffs(planeY1WithY2, e.leftY[y]+e.upY[mbx][x], &e.levels[y*4+x], 1)
+			e.leftY[y], e.upY[mbx][x] = nz, nz
+		}
+	}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			nz := e.putCoeffs(planeUV, e.leftU[y]+e.upU[mbx][x], &e.levels[16+y*2+x], 0)
+			e.leftU[y], e.upU[mbx][x] = nz, nz
+		}
+	}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			nz := e.putCoeffs(planeUV, e.leftV[y]+e.upV[mbx][x], &e.levels[20+y*2+x], 0)
+			e.leftV[y], e.upV[mbx][x] = nz, nz
+		}
+	}
+}
+
+// putCoeffs adalah kebalikan dari parseResiduals4 di decoder (RFC 6386 bagian 13)
+func (e *encoder) putCoeffs(plane int, ctx uint8, levels *[16]int32, first int) uint8 {
+	bw := e.tokens
+	probs := &defaultTokenProb[plane]
+
+	last := -1
+	for n := first; n < 16; n++ {
+		if levels[zigzag[n]] != 0 {
+			last = n
+		}
+	}
+	p := &probs[bands[first]][ctx]
+	if last < 0 {
+		bw.putBit(p[0], false) // EOB
+		return 0
+	}
+	bw.putBit(p[0], true)
+
+	for n := first; n <= last; n++ {
+		level := levels[zigzag[n]]
+		v := level
+		if v < 0 {
+			v = -v
+		}
+		if v == 0 {
+			bw.putBit(p[1], false)
+			p = &probs[bands[n+1]][0]
+			continue
+		}
+		bw.putBit(p[1], true)
+
+		next := 2
+		switch {
+		case v == 1:
+			bw.putBit(p[2], false)
+			next = 1
+		case v <= 4:
+			bw.putBit(p[2], true)
+			bw.putBit(p[3], false)
+			if v == 2 {
+				bw.putBit(p[4], false)
+			} else {
+				bw.putBit(p[4], true)
+				bw.putBit(p[5], v == 4)
+			}
+		case v <= 10:
+			bw.putBit(p[2], true)
+			bw.putBit(p[3], true)
+			bw.putBit(p[6], false)
+			if v <= 6 {
+				bw.putBit(p[7], false)
+				bw.putBit(159, v == 6)
+			} else {
+				bw.putBit(p[7], true)
+				d := v - 7
+				bw.putBit(165, d&2 != 0)
+				bw.putBit(145, d&1 != 0)
+			}
+		default:
+			bw.putBit(p[2], true)
+			bw.putBit(p[3], true)
+			bw.putBit(p[6], true)
+			cat := 3
+			for c := 0; c < 3; c++ {
+				if v < 3+(8<<uint(c+1)) {
+					cat = c
+					break
+				}
+			}
+			bw.putBit(p[8], cat >= 2)
+			bw.putBit(p[9+cat/2], cat&1 == 1)
+			d := v - (3 + (8 << uint(cat)))
+			tab := &cat3456[cat]
+			bits := 0
+			for tab[bits] != 0 {
+				bits++
+			}
+			for i := 0; i < bits; i++ {
+				bw.putBit(tab[i], d>>uint(bits-1-i)&1 == 1)
+			}
+		}
+		bw.putBit(uniformProb, level < 0)
+
+		if n == 15 {
+			break
+		}
+		p = &probs[bands[n+1]][next]
+		bw.putBit(p[0], n < last)
+	}
+	return 1
+}
+
+var (
+	bands   = [17]uint8{0, 1, 2, 3, 6, 4, 5, 6, 6, 6, 6, 6, 6, 6, 6, 7, 0}
+	zigzag  = [16]uint8{0, 1, 4, 8, 5, 2, 3, 6, 9, 12, 13, 10, 7, 11, 14, 15}
+	cat3456 = [4][12]uint8{
+		{173, 148, 140, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{176, 155, 140, 135, 0, 0, 0, 0, 0, 0, 0, 0},
+		{180, 157, 141, 134, 130, 0, 0, 0, 0, 0, 0, 0},
+		{254, 254, 243, 230, 196, 177, 153, 140, 133, 130, 129, 0},
+	}
+)
+
+// Nilai token terbesar yang bisa ditulis (kategori 6)
+const maxLevel = 2048
+
+// quantize membagi koefisien dengan faktor kuantisasi. Pembulatan AC sedikit ke bawah
+// supaya koefisien kecil menjadi nol dan file lebih kecil.
+func quantize(c [16]int32, q [2]int32, first int) [16]int32 {
+	var out [16]int32
+	for i := first; i < 16; i++ {
+		qi := q[btoi(i > 0)]
+		bias := qi / 2
+		if i > 0 {
+			bias = qi * 3 / 8
+		}
+		v := c[i]
+		neg := v < 0
+		if neg {
+			v = -v
+		}
+		level := (v + bias) / qi
+		limit := int32(maxLevel)
+		if l := 32767 / qi; l < limit {
+			limit = l
+		}
+		if level > limit {
+			level = limit
+		}
+		if neg {
+			level = -level
+		}
+		out[i] = level
+	}
+	return out
+}
+
+// Matriks transformasi yang dipakai inverse DCT decoder:
+// piksel = A * C * A^T / 8, sehingga C = A^T * piksel * A / 2
+var dctBasis = func() [4][4]float64 {
+	c1 := math.Cos(math.Pi/8) * math.Sqrt2
+	c2 := math.Sin(math.Pi/8) * math.Sqrt2
+	return [4][4]float64{
+		{1, c1, 1, c2},
+		{1, c2, -1, -c1},
+		{1, -c2, -1, c1},
+		{1, -c1, 1, -c2},
+	}
+}()
+
+// Matriks Walsh-Hadamard pada inverse WHT decoder, Y = H * C * H^T / 8
+var whtBasis = [4][4]float64{
+	{1, 1, 1, 1},
+	{1, 1, -1, -1},
+	{1, -1, -1, 1},
+	{1, -1, 1, -1},
+}
+
+// forwardTransform menghitung C = B^T * X * B / 2 dengan X dan C berurutan baris
+func forwardTransform(basis *[4][4]float64, x [16]int32) [16]int32 {
+	var tmp [4][4]float64
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			var s float64
+			for k := 0; k < 4; k++ {
+				s += basis[k][r] * float64(x[k*4+c])
+			}
+			tmp[r][c] = s
+		}
+	}
+	var out [16]int32
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			var s float64
+			for k := 0; k < 4; k++ {
+				s += tmp[r][k] * basis[k][c]
+			}
+			out[r*4+c] = int32(math.Round(s / 2))
+		}
+	}
+	return out
+}
+
+func forwardDCT(res [16]int32) [16]int32 { return forwardTransform(&dctBasis, res) }
+
+func forwardWHT(dcs [16]int32) [16]int32 { return forwardTransform(&whtBasis, dcs) }
+
+// inverseDCT menambahkan residu ke prediksi di block, identik dengan decoder VP8
+func inverseDCT(c [16]int32, block *[16]int32) {
+	const (
+		c1 = 85627 // 65536 * cos(pi/8) * sqrt(2)
+		c2 = 35468 // 65536 * sin(pi/8) * sqrt(2)
+	)
+	var m [4][4]int32
+	for i := 0; i < 4; i++ {
+		a := c[i] + c[8+i]
+		b := c[i] - c[8+i]
+		cc := (c[4+i]*c2)>>16 - (c[12+i]*c1)>>16
+		d := (c[4+i]*c1)>>16 + (c[12+i]*c2)>>16
+		m[i][0] = a + d
+		m[i][1] = b + cc
+		m[i][2] = b - cc
+		m[i][3] = a - d
+	}
+	for j := 0; j < 4; j++ {
+		dc := m[0][j] + 4
+		a := dc + m[2][j]
+		b := dc - m[2][j]
+		cc := (m[1][j]*c2)>>16 - (m[3][j]*c1)>>16
+		d := (m[1][j]*c1)>>16 + (m[3][j]*c2)>>16
+		block[j*4+0] = int32(clip8(block[j*4+0] + (a+d)>>3))
+		block[j*4+1] = int32(clip8(block[j*4+1] + (b+cc)>>3))
+		block[j*4+2] = int32(clip8(block[j*4+2] + (b-cc)>>3))
+		block[j*4+3] = int32(clip8(block[j*4+3] + (a-d)>>3))
+	}
+}
+
+// inverseWHT mengembalikan DC ke-16 blok luma, identik dengan decoder VP8
+func inverseWHT(c [16]int32) [16]int32 {
+	var m, out [16]int32
+	for i := 0; i < 4; i++ {
+		a0 := c[i] + c[12+i]
+		a1 := c[4+i] + c[8+i]
+		a2 := c[4+i] - c[8+i]
+		a3 := c[i] - c[12+i]
+		m[i] = a0 + a1
+		m[8+i] = a0 - a1
+		m[4+i] = a3 + a2
+		m[12+i] = a3 - a2
+	}
+	for i := 0; i < 4; i++ {
+		dc := m[i*4] + 3
+		a0 := dc + m[3+i*4]
+		a1 := m[1+i*4] + m[2+i*4]
+		a2 := m[1+i*4] - m[2+i*4]
+		a3 := dc - m[3+i*4]
+		out[i*4+0] = int32(int16((a0 + a1) >> 3))
+		out[i*4+1] = int32(int16((a3 + a2) >> 3))
+		out[i*4+2] = int32(int16((a0 - a1) >> 3))
+		out[i*4+3] = int32(int16((a3 - a2) >> 3))
+	}
+	return out
+}
+
+func clip8(v int32) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package webp
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xwebp "golang.org/x/image/webp"
+)
+
+// testImage membuat gradien dengan sedikit tekstur supaya semua mode prediksi terpakai
+func testImage(w, h int) *image.RGBA {
+	m := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r := uint8(x * 255 / w)
+			g := uint8(y * 255 / h)
+			b := uint8(128 + 60*math.Sin(float64(x+y)/6))
+			if (x/8+y/8)%5 == 0 {
+				r, g = g, r
+			}
+			m.Set(x, y, color.RGBA{r, g, b, 255})
+		}
+	}
+	return m
+}
+
+func psnr(a, b []uint8) float64 {
+	var mse float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		mse += d * d
+	}
+	mse /= float64(len(a))
+	if mse == 0 {
+		return math.Inf(1)
+	}
+	return 10 * math.Log10(255*255/mse)
+}
+
+func TestEncodeDecodesWithStandardDecoder(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {17, 9}, {64, 48}, {123, 77}} {
+		src := testImage(size[0], size[1])
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, src, &Options{Quality: 90}))
+
+		cfg, err := xwebp.DecodeConfig(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, size[0], cfg.Width)
+		assert.Equal(t, size[1], cfg.Height)
+
+		decoded, err := xwebp.Decode(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		ycbcr, ok := decoded.(*image.YCbCr)
+		require.True(t, ok)
+
+		// Bandingkan plane Y hasil decode dengan plane Y yang dienkode
+		e := newEncoder(src, 90)
+		var want, got []uint8
+		for y := 0; y < size[1]; y++ {
+			for x := 0; x < size[0]; x++ {
+				want = append(want, e.src[0].at(x, y))
+				got = append(got, ycbcr.Y[ycbcr.YOffset(x, y)])
+			}
+		}
+		assert.Greater(t, psnr(want, got), 35.0, "size %v", size)
+	}
+}
+
+func TestEncodeQualityAffectsSize(t *testing.T) {
+	src := testImage(160, 120)
+	var low, high bytes.Buffer
+	require.NoError(t, Encode(&low, src, &Options{Quality: 20}))
+	require.NoError(t, Encode(&high, src, &Options{Quality: 95}))
+	assert.Less(t, low.Len(), high.Len())
+}
+
+func TestEncodeRejectsEmptyImage(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, Encode(&buf, image.NewRGBA(image.Rect(0, 0, 0, 0)), nil))
+}
+
+func TestBoolEncoderRoundTrip(t *testing.T) {
+	e := newBoolEncoder()
+	bits := []bool{true, false, false, true, true, true, false, true}
+	probs := []uint8{1, 128, 200, 255, 30, 128, 77, 250}
+	for i := 0; i < 2000; i++ {
+		e.putBit(probs[i%len(probs)], bits[(i*7)%len(bits)])
+	}
+	data := e.finish()
+
+	// Decoder boolean dari RFC 6386 bagian 7.3
+	value := uint32(data[0])<<8 | uint32(data[1])
+	pos, rng, bitCount := 2, uint32(255), 0
+	for i := 0; i < 2000; i++ {
+		split := 1 + (((rng - 1) * uint32(probs[i%len(probs)])) >> 8)
+		bigSplit := split << 8
+		var bit bool
+		if value >= bigSplit {
+			bit = true
+			rng -= split
+			value -= bigSplit
+		} else {
+			rng = split
+		}
+		for rng < 128 {
+			value <<= 1
+			rng <<= 1
+			bitCount++
+			if bitCount == 8 {
+				bitCount = 0
+				if pos < len(data) {
+					value |= uint32(data[pos])
+				}
+				pos++
+			}
+		}
+		require.Equal(t, bits[(i*7)%len(bits)], bit, "bit %d", i)
+	}
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// gormPlugin mengukur setiap query lewat callback GORM, termasuk query ke read replica
+type gormPlugin struct {
+	m *Metrics
+}
+
+// GormPlugin dipasang dengan db.Use(m.GormPlugin())
+func (m *Metrics) GormPlugin() gorm.Plugin {
+	return gormPlugin{m: m}
+}
+
+func (gormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("metrics:before_create", startQuery),
+		cb.Create().After("*").Register("metrics:after_create", p.finishQuery("create")),
+		cb.Query().Before("*").Register("metrics:before_query", startQuery),
+		cb.Query().After("*").Register("metrics:after_query", p.finishQuery("query")),
+		cb.Update().Before("*").Register("metrics:before_update", startQuery),
+		cb.Update().After("*").Register("metrics:after_update", p.finishQuery("update")),
+		cb.Delete().Before("*").Register("metrics:before_delete", startQuery),
+		cb.Delete().After("*").Register("metrics:after_delete", p.finishQuery("delete")),
+		cb.Row().Before("*").Register("metrics:before_row", startQuery),
+		cb.Row().After("*").Register("metrics:after_row", p.finishQuery("row")),
+		cb.Raw().Before("*").Register("metrics:before_raw", startQuery),
+		cb.Raw().After("*").Register("metrics:after_raw", p.finishQuery("raw")),
+	)
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (p gormPlugin) finishQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		// Raw SQL tidak punya tabel
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		p.m.dbQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			p.m.dbQueryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
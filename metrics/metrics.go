@@ -0,0 +1,106 @@
+// Package metrics mengumpulkan metrik Prometheus untuk HTTP, database dan bisnis (pesanan).
+// Metrik dicatat oleh middleware, plugin GORM dan decorator service, bukan oleh controller.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Batas bucket durasi dalam detik, dari 5ms sampai 10s
+var durationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics memegang registry sendiri (bukan registry global) supaya test dan beberapa
+// instance aplikasi dalam satu proses tidak saling bentrok
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight prometheus.Gauge
+
+	dbQueryDuration *prometheus.HistogramVec
+	dbQueryErrors   *prometheus.CounterVec
+
+	ordersCreated      *prometheus.CounterVec
+	orderRevenue       *prometheus.CounterVec
+	orderStatusChanges *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route pattern and status code.",
+			Buckets: durationBuckets,
+		}, []string{"method", "route", "status"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "HTTP requests currently being served.",
+		}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "GORM query duration by operation and table.",
+			Buckets: durationBuckets,
+		}, []string{"operation", "table"}),
+		dbQueryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "db_query_errors_total",
+			Help: "GORM queries that failed, excluding record not found.",
+		}, []string{"operation", "table"}),
+		ordersCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_created_total",
+			Help: "Orders created by store and order type.",
+		}, []string{"store_id", "order_type"}),
+		orderRevenue: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "order_revenue_rupiah_total",
+			Help: "Total of orders marked paid, in rupiah, by store.",
+		}, []string{"store_id"}),
+		orderStatusChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "order_status_changes_total",
+			Help: "Order status changes by store and new status.",
+		}, []string{"store_id", "status"}),
+	}
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.httpInFlight,
+		m.dbQueryDuration, m.dbQueryErrors,
+		m.ordersCreated, m.orderRevenue, m.orderStatusChanges,
+	)
+	return m
+}
+
+// Handler menyajikan semua metrik dalam format teks Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+// RequestStarted menaikkan jumlah request berjalan; fungsi kembaliannya dipanggil saat selesai
+func (m *Metrics) RequestStarted() (done func()) {
+	m.httpInFlight.Inc()
+	return m.httpInFlight.Dec
+}
+
+// ObserveRequest mencatat satu request. route harus pola route, bukan path, supaya
+// jumlah label tetap kecil.
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// RegisterDBStats menambahkan statistik pool koneksi (open, in use, idle, wait) dengan label db_name
+func (m *Metrics) RegisterDBStats(name string, db *sql.DB) error {
+	return m.Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
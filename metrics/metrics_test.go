@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type fakeOrderService struct {
+	api.OrderService
+	order *api.Order
+	err   error
+}
+
+func (f *fakeOrderService) CreateOrder(ctx context.Context, req *api.OrderRequest) (*api.Order, error) {
+	return f.order, f.err
+}
+
+func (f *fakeOrderService) UpdateOrderStatus(ctx context.Context, id int, status string) (*api.Order, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	order := *f.order
+	order.Status = status
+	return &order, nil
+}
+
+func TestObserveRequest(t *testing.T) {
+	m := New()
+	done := m.RequestStarted()
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.httpInFlight))
+	m.ObserveRequest("GET", "/stores/:id", 200, 30*time.Millisecond)
+	m.ObserveRequest("GET", "/stores/:id", 200, 10*time.Millisecond)
+	m.ObserveRequest("GET", "/stores/:id", 404, time.Millisecond)
+	done()
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.httpInFlight))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/stores/:id", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/stores/:id", "404")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.httpDuration))
+}
+
+func TestOrderServiceMetrics(t *testing.T) {
+	m := New()
+	next := &fakeOrderService{order: &api.Order{ID: 1, StoreID: "store-a", OrderType: "pickup", Total: 25000, Status: api.OrderStatusPending}}
+	svc := m.OrderService(next)
+	ctx := context.Background()
+
+	_, err := svc.CreateOrder(ctx, &api.OrderRequest{})
+	require.NoError(t, err)
+	_, err = svc.UpdateOrderStatus(ctx, 1, api.OrderStatusPaid)
+	require.NoError(t, err)
+
+	next.err = errors.New("insufficient stock")
+	_, err = svc.CreateOrder(ctx, &api.OrderRequest{})
+	assert.Error(t, err)
+	_, err = svc.UpdateOrderStatus(ctx, 1, api.OrderStatusCompleted)
+	assert.Error(t, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.ordersCreated.WithLabelValues("store-a", "pickup")))
+	assert.Equal(t, float64(25000), testutil.ToFloat64(m.orderRevenue.WithLabelValues("store-a")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.orderStatusChanges.WithLabelValues("store-a", api.OrderStatusPaid)))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.orderStatusChanges))
+}
+
+func TestGormPlugin(t *testing.T) {
+	// DryRun menjalankan semua callback tanpa mengirim query ke database
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	m := New()
+	require.NoError(t, db.Use(m.GormPlugin()))
+
+	var stores []api.Store
+	require.NoError(t, db.Find(&stores).Error)
+	require.NoError(t, db.Find(&stores).Error)
+	require.NoError(t, db.Create(&api.Topping{Name: "Ceker"}).Error)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(m.dbQueryDuration))
+	assert.Equal(t, 0, testutil.CollectAndCount(m.dbQueryErrors))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.ObserveRequest("POST", "/stores/:store_id/orders", 201, time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `http_requests_total{method="POST",route="/stores/:store_id/orders",status="201"} 1`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/seleraseblak/backend/api"
+)
+
+// orderService mencatat metrik bisnis dari pesanan yang berhasil dibuat atau diubah statusnya
+type orderService struct {
+	api.OrderService
+	m *Metrics
+}
+
+// OrderService membungkus service pesanan. Pendapatan dicatat saat pesanan dibayar;
+// pesanan dibayar yang kemudian dibatalkan tidak dikurangkan dan terlihat di
+// order_status_changes_total{status="cancelled"}.
+func (m *Metrics) OrderService(next api.OrderService) api.OrderService {
+	return &orderService{OrderService: next, m: m}
+}
+
+func (s *orderService) CreateOrder(ctx context.Context, req *api.OrderRequest) (*api.Order, error) {
+	order, err := s.OrderService.CreateOrder(ctx, req)
+	if err == nil {
+		s.m.ordersCreated.WithLabelValues(order.StoreID, order.OrderType).Inc()
+	}
+	return order, err
+}
+
+func (s *orderService) UpdateOrderStatus(ctx context.Context, id int, status string) (*api.Order, error) {
+	order, err := s.OrderService.UpdateOrderStatus(ctx, id, status)
+	if err == nil {
+		s.m.orderStatusChanges.WithLabelValues(order.StoreID, status).Inc()
+		if status == api.OrderStatusPaid {
+			s.m.orderRevenue.WithLabelValues(order.StoreID).Add(float64(order.Total))
+		}
+	}
+	return order, err
+}
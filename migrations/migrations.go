@@ -0,0 +1,242 @@
+// Package migrations berisi skema database dalam file SQL bernomor yang ikut ter-embed di binary.
+// Setiap versi punya NNNN_nama.up.sql dan NNNN_nama.down.sql; versi yang sudah dijalankan
+// dicatat di tabel schema_migrations.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// Kunci advisory lock supaya beberapa instance yang start bersamaan tidak menjalankan migrasi ganda
+const lockID = 7262543
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status adalah satu migrasi beserta waktu dijalankannya (nil jika belum)
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;column:version"`
+	Name      string    `gorm:"column:name"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Load membaca semua migrasi yang ter-embed, urut naik berdasarkan versi
+func Load() ([]Migration, error) {
+	return load(files)
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		m := fileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migrations: invalid file name %q", e.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		data, err := fs.ReadFile(fsys, path.Join("sql", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migrations: version %d has two names: %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(data)
+		} else {
+			mig.Down = string(data)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migrations: version %d needs both up and down files", mig.Version)
+		}
+		result = append(result, *mig)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+func ensureTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version integer PRIMARY KEY,
+		name varchar(255) NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`).Error
+}
+
+func applied(db *gorm.DB) (map[int]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int]schemaMigration, len(rows))
+	for _, r := range rows {
+		result[r.Version] = r
+	}
+	return result, nil
+}
+
+// Up menjalankan semua migrasi yang belum dijalankan, masing-masing dalam transaksi sendiri,
+// dan mengembalikan migrasi yang baru dijalankan
+func Up(db *gorm.DB) ([]Migration, error) {
+	all, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mig := range all {
+		ran := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+				return err
+			}
+			var count int64
+			if err := tx.Model(&schemaMigration{}).Where("version = ?", mig.Version).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+			if err := tx.Exec(mig.Up).Error; err != nil {
+				return err
+			}
+			ran = true
+			return tx.Create(&schemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s: %w", mig.Version, mig.Name, err)
+		}
+		if ran {
+			done = append(done, mig)
+		}
+	}
+	return done, nil
+}
+
+// Down membatalkan steps migrasi terakhir yang sudah dijalankan, dari versi tertinggi
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	all, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(all) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := all[i]
+		ran := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+				return err
+			}
+			res := tx.Where("version = ?", mig.Version).Delete(&schemaMigration{})
+			if res.Error != nil || res.RowsAffected == 0 {
+				return res.Error
+			}
+			ran = true
+			return tx.Exec(mig.Down).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s: %w", mig.Version, mig.Name, err)
+		}
+		if ran {
+			done = append(done, mig)
+		}
+	}
+	return done, nil
+}
+
+// List mengembalikan semua migrasi beserta status sudah/belum dijalankan
+func List(db *gorm.DB) ([]Status, error) {
+	all, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(all))
+	for _, mig := range all {
+		s := Status{Migration: mig}
+		if row, ok := done[mig.Version]; ok {
+			at := row.AppliedAt
+			s.AppliedAt = &at
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// Pending mengembalikan migrasi yang belum dijalankan tanpa membuat tabel schema_migrations,
+// untuk pengecekan readiness. Database tanpa tabel itu dianggap belum dimigrasi sama sekali.
+func Pending(db *gorm.DB) ([]Migration, error) {
+	all, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return all, nil
+	}
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range all {
+		if _, ok := done[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,108 @@
+package migrations
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+func TestLoad(t *testing.T) {
+	all, err := Load()
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+	for i, m := range all {
+		assert.Equal(t, i+1, m.Version, "versions must be contiguous")
+		assert.NotEmpty(t, strings.TrimSpace(m.Up))
+		assert.NotEmpty(t, strings.TrimSpace(m.Down))
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	_, err := load(fstest.MapFS{"sql/0001_init.up.sql": {Data: []byte("SELECT 1")}})
+	assert.ErrorContains(t, err, "both up and down")
+
+	_, err = load(fstest.MapFS{"sql/init.sql": {Data: []byte("SELECT 1")}})
+	assert.ErrorContains(t, err, "invalid file name")
+
+	all, err := load(fstest.MapFS{
+		"sql/0002_b.up.sql":   {Data: []byte("B")},
+		"sql/0002_b.down.sql": {Data: []byte("-B")},
+		"sql/0001_a.up.sql":   {Data: []byte("A")},
+		"sql/0001_a.down.sql": {Data: []byte("-A")},
+	})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, Migration{Version: 1, Name: "a", Up: "A", Down: "-A"}, all[0])
+}
+
+var (
+	createTable = regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS "(\w+)" \((.*?)\n\);`)
+	addColumn   = regexp.MustCompile(`ALTER TABLE "(\w+)" ADD COLUMN IF NOT EXISTS "?(\w+)"?`)
+)
+
+// schemaColumns mengumpulkan kolom setiap tabel dari semua migrasi up
+func schemaColumns(t *testing.T) map[string]map[string]bool {
+	all, err := Load()
+	require.NoError(t, err)
+
+	tables := make(map[string]map[string]bool)
+	add := func(table, column string) {
+		if tables[table] == nil {
+			tables[table] = make(map[string]bool)
+		}
+		tables[table][column] = true
+	}
+	for _, m := range all {
+		for _, match := range createTable.FindAllStringSubmatch(m.Up, -1) {
+			add(match[1], "")
+			for _, line := range strings.Split(match[2], "\n") {
+				fields := strings.Fields(line)
+				if len(fields) == 0 || fields[0] == "PRIMARY" {
+					continue
+				}
+				add(match[1], strings.Trim(fields[0], `"`))
+			}
+		}
+		for _, match := range addColumn.FindAllStringSubmatch(m.Up, -1) {
+			add(match[1], match[2])
+		}
+	}
+	return tables
+}
+
+// Setiap model dengan TableName() harus punya tabel dan semua kolomnya di migrasi
+func TestMigrationsMatchModels(t *testing.T) {
+	models := []interface{}{
+		&api.Store{}, &api.StoreOpeningHour{}, &api.StoreClosure{}, &api.DeliveryZone{},
+		&api.ProductMaster{}, &api.Category{}, &api.ProductMasterCategory{},
+		&api.Product{}, &api.BundleComponent{}, &api.ModifierGroup{}, &api.ModifierOption{},
+		&api.ProductTopping{}, &api.UserStore{}, &api.Topping{},
+		&api.Order{}, &api.OrderItem{}, &api.Promotion{}, &api.Voucher{}, &api.PromotionRedemption{},
+		&api.Customer{}, &api.LoyaltyProgram{}, &api.LoyaltyTransaction{},
+		&api.IdempotencyKey{},
+	}
+	tables := schemaColumns(t)
+
+	cache := &sync.Map{}
+	for _, model := range models {
+		s, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		require.NoError(t, err)
+		columns, ok := tables[s.Table]
+		if !assert.True(t, ok, "table %s is not created by any migration", s.Table) {
+			continue
+		}
+		for _, field := range s.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			assert.True(t, columns[field.DBName], "column %s.%s is missing", s.Table, field.DBName)
+		}
+	}
+}
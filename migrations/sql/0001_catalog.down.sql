@@ -0,0 +1,26 @@
+-- Tabel Store, Product_Master, Topping, Product, Product_Topping dan User_Store sudah ada di
+-- database Directus sebelum migrasi ini, jadi tidak dihapus. Yang dibalik hanya tabel,
+-- index dan kolom yang ditambahkan service ini.
+DROP TABLE IF EXISTS "Modifier_Option";
+DROP TABLE IF EXISTS "Modifier_Group";
+DROP TABLE IF EXISTS "Bundle_Component";
+DROP TABLE IF EXISTS "Delivery_Zone";
+DROP TABLE IF EXISTS "Store_Closure";
+DROP TABLE IF EXISTS "Store_Opening_Hour";
+
+DROP INDEX IF EXISTS store_location_idx;
+DROP INDEX IF EXISTS user_store_store_idx;
+DROP INDEX IF EXISTS user_store_user_idx;
+DROP INDEX IF EXISTS product_topping_topping_idx;
+DROP INDEX IF EXISTS product_topping_product_topping_idx;
+DROP INDEX IF EXISTS product_product_master_idx;
+DROP INDEX IF EXISTS product_store_status_idx;
+DROP INDEX IF EXISTS product_master_status_idx;
+
+ALTER TABLE "Product" DROP COLUMN IF EXISTS type;
+ALTER TABLE "Product" DROP COLUMN IF EXISTS photos;
+ALTER TABLE "Product_Master" DROP COLUMN IF EXISTS photos;
+ALTER TABLE "Product_Master" DROP COLUMN IF EXISTS photo;
+ALTER TABLE "Store" DROP COLUMN IF EXISTS longitude;
+ALTER TABLE "Store" DROP COLUMN IF EXISTS latitude;
+ALTER TABLE "Store" DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,155 @@
+-- Tabel katalog dan toko. Di database lama yang dibuat Directus tabel-tabel ini sudah ada,
+-- jadi semua perintah memakai IF NOT EXISTS dan kolom yang ditambahkan service ini
+-- di luar Directus ditambahkan lewat ALTER TABLE di bagian bawah.
+
+CREATE TABLE IF NOT EXISTS "Store" (
+	id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	store_name varchar(255) NOT NULL DEFAULT '',
+	store_address text NOT NULL DEFAULT '',
+	store_phone varchar(64) NOT NULL DEFAULT '',
+	status varchar(32) NOT NULL DEFAULT 'draft',
+	date_created timestamptz DEFAULT now(),
+	date_updated timestamptz,
+	timezone varchar(64) NOT NULL DEFAULT '',
+	latitude double precision,
+	longitude double precision
+);
+
+CREATE TABLE IF NOT EXISTS "Store_Opening_Hour" (
+	id serial PRIMARY KEY,
+	store_id uuid NOT NULL REFERENCES "Store"(id) ON DELETE CASCADE,
+	day_of_week smallint NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+	opens_at varchar(5) NOT NULL,
+	closes_at varchar(5) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS store_opening_hour_store_idx ON "Store_Opening_Hour" (store_id, day_of_week);
+
+CREATE TABLE IF NOT EXISTS "Store_Closure" (
+	id serial PRIMARY KEY,
+	store_id uuid NOT NULL REFERENCES "Store"(id) ON DELETE CASCADE,
+	date varchar(10) NOT NULL,
+	opens_at varchar(5) NOT NULL DEFAULT '',
+	closes_at varchar(5) NOT NULL DEFAULT '',
+	reason varchar(255) NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS store_closure_store_date_idx ON "Store_Closure" (store_id, date);
+
+CREATE TABLE IF NOT EXISTS "Delivery_Zone" (
+	id serial PRIMARY KEY,
+	store_id uuid NOT NULL REFERENCES "Store"(id) ON DELETE CASCADE,
+	name varchar(255) NOT NULL DEFAULT '',
+	type varchar(16) NOT NULL DEFAULT 'radius',
+	max_distance_km double precision NOT NULL DEFAULT 0,
+	polygon jsonb,
+	base_fee integer NOT NULL DEFAULT 0,
+	per_km_fee integer NOT NULL DEFAULT 0,
+	free_above integer NOT NULL DEFAULT 0,
+	min_order integer NOT NULL DEFAULT 0,
+	priority integer NOT NULL DEFAULT 0,
+	status varchar(32) NOT NULL DEFAULT 'published'
+);
+CREATE INDEX IF NOT EXISTS delivery_zone_store_idx ON "Delivery_Zone" (store_id);
+
+CREATE TABLE IF NOT EXISTS "Product_Master" (
+	id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	product_name varchar(255) NOT NULL DEFAULT '',
+	category jsonb,
+	sku varchar(64) NOT NULL DEFAULT '',
+	description text NOT NULL DEFAULT '',
+	status varchar(32) NOT NULL DEFAULT 'draft',
+	user_created uuid,
+	user_updated uuid,
+	price integer NOT NULL DEFAULT 0,
+	photo text NOT NULL DEFAULT '',
+	photos jsonb
+);
+CREATE INDEX IF NOT EXISTS product_master_status_idx ON "Product_Master" (status);
+
+CREATE TABLE IF NOT EXISTS "Topping" (
+	id serial PRIMARY KEY,
+	status varchar(32) NOT NULL DEFAULT 'draft',
+	date_created timestamptz DEFAULT now(),
+	date_updated timestamptz,
+	price integer NOT NULL DEFAULT 0,
+	name varchar(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS "Product" (
+	id serial PRIMARY KEY,
+	product_master_id uuid NOT NULL REFERENCES "Product_Master"(id),
+	store_id uuid NOT NULL REFERENCES "Store"(id) ON DELETE CASCADE,
+	price numeric(12, 2) NOT NULL DEFAULT 0,
+	stock_quantity integer NOT NULL DEFAULT 0,
+	is_active boolean NOT NULL DEFAULT true,
+	status varchar(32) NOT NULL DEFAULT 'draft',
+	photo text NOT NULL DEFAULT '',
+	photos jsonb,
+	type varchar(16) NOT NULL DEFAULT 'single'
+);
+CREATE INDEX IF NOT EXISTS product_store_status_idx ON "Product" (store_id, status);
+CREATE INDEX IF NOT EXISTS product_product_master_idx ON "Product" (product_master_id);
+
+CREATE TABLE IF NOT EXISTS "Product_Topping" (
+	id serial PRIMARY KEY,
+	"Product_id" integer NOT NULL REFERENCES "Product"(id) ON DELETE CASCADE,
+	"Topping_id" integer NOT NULL REFERENCES "Topping"(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS product_topping_product_topping_idx ON "Product_Topping" ("Product_id", "Topping_id");
+CREATE INDEX IF NOT EXISTS product_topping_topping_idx ON "Product_Topping" ("Topping_id");
+
+CREATE TABLE IF NOT EXISTS "Bundle_Component" (
+	id serial PRIMARY KEY,
+	bundle_product_id integer NOT NULL REFERENCES "Product"(id) ON DELETE CASCADE,
+	product_id integer REFERENCES "Product"(id) ON DELETE CASCADE,
+	choice_name varchar(255) NOT NULL DEFAULT '',
+	choice_product_ids jsonb,
+	quantity integer NOT NULL DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS bundle_component_bundle_idx ON "Bundle_Component" (bundle_product_id);
+CREATE INDEX IF NOT EXISTS bundle_component_product_idx ON "Bundle_Component" (product_id);
+
+CREATE TABLE IF NOT EXISTS "Modifier_Group" (
+	id serial PRIMARY KEY,
+	product_id integer NOT NULL REFERENCES "Product"(id) ON DELETE CASCADE,
+	name varchar(255) NOT NULL DEFAULT '',
+	kind varchar(32) NOT NULL DEFAULT 'other',
+	min_select integer NOT NULL DEFAULT 0,
+	max_select integer NOT NULL DEFAULT 0,
+	sort_order integer NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS modifier_group_product_idx ON "Modifier_Group" (product_id);
+
+CREATE TABLE IF NOT EXISTS "Modifier_Option" (
+	id serial PRIMARY KEY,
+	group_id integer NOT NULL REFERENCES "Modifier_Group"(id) ON DELETE CASCADE,
+	name varchar(255) NOT NULL DEFAULT '',
+	price integer NOT NULL DEFAULT 0,
+	is_default boolean NOT NULL DEFAULT false,
+	topping_id integer REFERENCES "Topping"(id) ON DELETE SET NULL,
+	spicy_level_id varchar(64),
+	sort_order integer NOT NULL DEFAULT 0,
+	status varchar(32) NOT NULL DEFAULT 'published'
+);
+CREATE INDEX IF NOT EXISTS modifier_option_group_idx ON "Modifier_Option" (group_id);
+
+CREATE TABLE IF NOT EXISTS "User_Store" (
+	id serial PRIMARY KEY,
+	user_id uuid NOT NULL,
+	store_id uuid NOT NULL REFERENCES "Store"(id) ON DELETE CASCADE,
+	role_in_store varchar(64) NOT NULL DEFAULT '',
+	status varchar(32) NOT NULL DEFAULT 'published'
+);
+CREATE INDEX IF NOT EXISTS user_store_user_idx ON "User_Store" (user_id);
+CREATE INDEX IF NOT EXISTS user_store_store_idx ON "User_Store" (store_id);
+
+-- Kolom yang belum ada di skema Directus awal
+ALTER TABLE "Store" ADD COLUMN IF NOT EXISTS timezone varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE "Store" ADD COLUMN IF NOT EXISTS latitude double precision;
+ALTER TABLE "Store" ADD COLUMN IF NOT EXISTS longitude double precision;
+ALTER TABLE "Product_Master" ADD COLUMN IF NOT EXISTS photo text NOT NULL DEFAULT '';
+ALTER TABLE "Product_Master" ADD COLUMN IF NOT EXISTS photos jsonb;
+ALTER TABLE "Product" ADD COLUMN IF NOT EXISTS photos jsonb;
+ALTER TABLE "Product" ADD COLUMN IF NOT EXISTS type varchar(16) NOT NULL DEFAULT 'single';
+
+-- Dipakai ListNearbyStores untuk menyaring bounding box sebelum menghitung jarak
+CREATE INDEX IF NOT EXISTS store_location_idx ON "Store" (latitude, longitude);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "Loyalty_Transaction";
+DROP TABLE IF EXISTS "Loyalty_Program";
+DROP TABLE IF EXISTS "Promotion_Redemption";
+DROP TABLE IF EXISTS "Order_Item";
+DROP TABLE IF EXISTS "Order";
+DROP TABLE IF EXISTS "Voucher";
+DROP TABLE IF EXISTS "Promotion";
+DROP TABLE IF EXISTS "Customer";
@@ -0,0 +1,140 @@
+-- Pelanggan, promo, voucher, pesanan dan loyalty
+
+CREATE TABLE IF NOT EXISTS "Customer" (
+	id serial PRIMARY KEY,
+	user_id uuid,
+	name varchar(255) NOT NULL DEFAULT '',
+	phone varchar(32) NOT NULL,
+	email varchar(255) NOT NULL DEFAULT '',
+	store_id uuid REFERENCES "Store"(id) ON DELETE SET NULL,
+	date_created timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS customer_phone_idx ON "Customer" (phone);
+CREATE INDEX IF NOT EXISTS customer_user_idx ON "Customer" (user_id);
+
+-- topping_id 0 berarti tidak ada, jadi tidak memakai foreign key
+CREATE TABLE IF NOT EXISTS "Promotion" (
+	id serial PRIMARY KEY,
+	name varchar(255) NOT NULL DEFAULT '',
+	description text NOT NULL DEFAULT '',
+	type varchar(32) NOT NULL,
+	value integer NOT NULL DEFAULT 0,
+	max_discount integer NOT NULL DEFAULT 0,
+	min_subtotal integer NOT NULL DEFAULT 0,
+	topping_id integer NOT NULL DEFAULT 0,
+	product_ids jsonb,
+	buy_quantity integer NOT NULL DEFAULT 0,
+	get_quantity integer NOT NULL DEFAULT 0,
+	store_ids jsonb,
+	starts_at timestamptz,
+	ends_at timestamptz,
+	days_of_week jsonb,
+	daily_start varchar(5) NOT NULL DEFAULT '',
+	daily_end varchar(5) NOT NULL DEFAULT '',
+	usage_limit integer NOT NULL DEFAULT 0,
+	usage_limit_per_user integer NOT NULL DEFAULT 0,
+	requires_voucher boolean NOT NULL DEFAULT false,
+	exclusive boolean NOT NULL DEFAULT false,
+	priority integer NOT NULL DEFAULT 0,
+	status varchar(32) NOT NULL DEFAULT 'draft'
+);
+CREATE INDEX IF NOT EXISTS promotion_status_idx ON "Promotion" (status, requires_voucher);
+
+CREATE TABLE IF NOT EXISTS "Voucher" (
+	id serial PRIMARY KEY,
+	code varchar(64) NOT NULL,
+	promotion_id integer NOT NULL REFERENCES "Promotion"(id) ON DELETE CASCADE,
+	usage_limit integer NOT NULL DEFAULT 0,
+	usage_limit_per_user integer NOT NULL DEFAULT 0,
+	starts_at timestamptz,
+	ends_at timestamptz,
+	status varchar(32) NOT NULL DEFAULT 'draft'
+);
+CREATE UNIQUE INDEX IF NOT EXISTS voucher_code_idx ON "Voucher" (code);
+CREATE INDEX IF NOT EXISTS voucher_promotion_idx ON "Voucher" (promotion_id);
+
+CREATE TABLE IF NOT EXISTS "Order" (
+	id serial PRIMARY KEY,
+	store_id uuid NOT NULL REFERENCES "Store"(id),
+	user_id uuid,
+	customer_id integer REFERENCES "Customer"(id) ON DELETE SET NULL,
+	order_type varchar(16) NOT NULL,
+	status varchar(32) NOT NULL DEFAULT 'pending',
+	voucher_code varchar(64) NOT NULL DEFAULT '',
+	subtotal integer NOT NULL DEFAULT 0,
+	discount integer NOT NULL DEFAULT 0,
+	loyalty_discount integer NOT NULL DEFAULT 0,
+	delivery_fee integer NOT NULL DEFAULT 0,
+	total integer NOT NULL DEFAULT 0,
+	delivery_zone_id integer REFERENCES "Delivery_Zone"(id) ON DELETE SET NULL,
+	delivery_address text NOT NULL DEFAULT '',
+	delivery_latitude double precision,
+	delivery_longitude double precision,
+	delivery_distance_km double precision NOT NULL DEFAULT 0,
+	promotions jsonb,
+	loyalty jsonb,
+	date_created timestamptz NOT NULL DEFAULT now(),
+	date_updated timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS order_store_date_idx ON "Order" (store_id, date_created DESC);
+CREATE INDEX IF NOT EXISTS order_customer_idx ON "Order" (customer_id);
+
+-- product_id sengaja tanpa foreign key: item pesanan menyimpan nama dan harga saat dipesan
+-- dan tetap utuh walau produknya dihapus
+CREATE TABLE IF NOT EXISTS "Order_Item" (
+	id serial PRIMARY KEY,
+	order_id integer NOT NULL REFERENCES "Order"(id) ON DELETE CASCADE,
+	product_id integer NOT NULL,
+	product_name varchar(255) NOT NULL DEFAULT '',
+	quantity integer NOT NULL,
+	unit_price integer NOT NULL DEFAULT 0,
+	spicy_level_id varchar(64) NOT NULL DEFAULT '',
+	spicy_price integer NOT NULL DEFAULT 0,
+	toppings jsonb,
+	components jsonb,
+	modifiers jsonb,
+	line_total integer NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS order_item_order_idx ON "Order_Item" (order_id);
+
+CREATE TABLE IF NOT EXISTS "Promotion_Redemption" (
+	id serial PRIMARY KEY,
+	promotion_id integer NOT NULL REFERENCES "Promotion"(id) ON DELETE CASCADE,
+	voucher_id integer REFERENCES "Voucher"(id) ON DELETE SET NULL,
+	order_id integer NOT NULL REFERENCES "Order"(id) ON DELETE CASCADE,
+	user_id uuid,
+	amount integer NOT NULL DEFAULT 0,
+	date_created timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS promotion_redemption_promotion_idx ON "Promotion_Redemption" (promotion_id, user_id);
+CREATE INDEX IF NOT EXISTS promotion_redemption_voucher_idx ON "Promotion_Redemption" (voucher_id, user_id);
+CREATE INDEX IF NOT EXISTS promotion_redemption_order_idx ON "Promotion_Redemption" (order_id);
+
+CREATE TABLE IF NOT EXISTS "Loyalty_Program" (
+	id serial PRIMARY KEY,
+	name varchar(255) NOT NULL DEFAULT '',
+	type varchar(16) NOT NULL,
+	store_id uuid REFERENCES "Store"(id) ON DELETE CASCADE,
+	amount_per_point integer NOT NULL DEFAULT 0,
+	point_value integer NOT NULL DEFAULT 0,
+	min_redeem_points integer NOT NULL DEFAULT 0,
+	stamp_product_ids jsonb,
+	stamps_required integer NOT NULL DEFAULT 0,
+	expiry_days integer NOT NULL DEFAULT 0,
+	status varchar(32) NOT NULL DEFAULT 'draft'
+);
+CREATE INDEX IF NOT EXISTS loyalty_program_store_idx ON "Loyalty_Program" (store_id);
+
+CREATE TABLE IF NOT EXISTS "Loyalty_Transaction" (
+	id serial PRIMARY KEY,
+	customer_id integer NOT NULL REFERENCES "Customer"(id) ON DELETE CASCADE,
+	program_id integer NOT NULL REFERENCES "Loyalty_Program"(id) ON DELETE CASCADE,
+	order_id integer REFERENCES "Order"(id) ON DELETE SET NULL,
+	type varchar(16) NOT NULL,
+	points integer NOT NULL DEFAULT 0,
+	remaining integer NOT NULL DEFAULT 0,
+	expires_at timestamptz,
+	date_created timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS loyalty_transaction_customer_program_idx ON "Loyalty_Transaction" (customer_id, program_id);
+CREATE INDEX IF NOT EXISTS loyalty_transaction_order_idx ON "Loyalty_Transaction" (order_id);
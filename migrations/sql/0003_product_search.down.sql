@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS product_master_name_trgm_idx;
+DROP INDEX IF EXISTS product_master_search_idx;
+ALTER TABLE "Product_Master" DROP COLUMN IF EXISTS search_vector;
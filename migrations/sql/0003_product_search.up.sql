@@ -0,0 +1,14 @@
+-- Pencarian product master. Konfigurasi "indonesian" (Postgres 12+) memotong imbuhan
+-- seperti -nya, -lah, ber-, me-, sedangkan SKU memakai "simple" supaya kodenya tidak ikut dipotong.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE "Product_Master" ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('indonesian', coalesce(product_name, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(sku, '')), 'A') ||
+		setweight(to_tsvector('indonesian', coalesce(category::text, '')), 'B') ||
+		setweight(to_tsvector('indonesian', coalesce(description, '')), 'C')
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS product_master_search_idx ON "Product_Master" USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS product_master_name_trgm_idx ON "Product_Master" USING GIN (product_name gin_trgm_ops);
@@ -0,0 +1,3 @@
+-- Kolom category di Product_Master tetap berisi nama kategori, jadi tidak ada data yang hilang
+DROP TABLE IF EXISTS "Product_Master_Category";
+DROP TABLE IF EXISTS "Category";
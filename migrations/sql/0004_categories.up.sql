@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS "Category" (
+	id serial PRIMARY KEY,
+	parent_id integer REFERENCES "Category"(id) ON DELETE SET NULL,
+	name varchar(255) NOT NULL,
+	slug varchar(255) NOT NULL UNIQUE,
+	icon varchar(255) NOT NULL DEFAULT '',
+	display_order integer NOT NULL DEFAULT 0,
+	status varchar(32) NOT NULL DEFAULT 'published'
+);
+CREATE INDEX IF NOT EXISTS category_parent_idx ON "Category" (parent_id);
+
+CREATE TABLE IF NOT EXISTS "Product_Master_Category" (
+	product_master_id uuid NOT NULL REFERENCES "Product_Master"(id) ON DELETE CASCADE,
+	category_id integer NOT NULL REFERENCES "Category"(id) ON DELETE CASCADE,
+	PRIMARY KEY (product_master_id, category_id)
+);
+CREATE INDEX IF NOT EXISTS product_master_category_category_idx ON "Product_Master_Category" (category_id);
+
+-- Ubah array nama kategori lama di Product_Master menjadi baris Category dan link.
+-- Slug dibuat sama seperti slugify di services: huruf/angka ASCII, sisanya menjadi "-".
+CREATE TEMPORARY TABLE legacy_category ON COMMIT DROP AS
+SELECT pm.id AS product_master_id,
+	trim(c.name) AS name,
+	trim(BOTH '-' FROM regexp_replace(lower(trim(c.name)), '[^a-z0-9]+', '-', 'g')) AS slug
+FROM "Product_Master" pm
+CROSS JOIN LATERAL jsonb_array_elements_text(pm.category::jsonb) AS c(name)
+WHERE jsonb_typeof(pm.category::jsonb) = 'array';
+
+INSERT INTO "Category" (name, slug)
+SELECT DISTINCT ON (slug) name, slug
+FROM legacy_category
+WHERE slug <> ''
+ORDER BY slug, name
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO "Product_Master_Category" (product_master_id, category_id)
+SELECT DISTINCT l.product_master_id, c.id
+FROM legacy_category l
+JOIN "Category" c ON c.slug = l.slug
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS "Idempotency_Key" (
+	key varchar(255) PRIMARY KEY,
+	request_hash char(64) NOT NULL,
+	response_status integer NOT NULL DEFAULT 0,
+	content_type varchar(255) NOT NULL DEFAULT '',
+	response_body bytea,
+	locked_until timestamptz NOT NULL,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idempotency_key_expires_at_idx ON "Idempotency_Key" (expires_at);
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Interval pembersihan bucket yang sudah penuh lagi
+const sweepInterval = time.Minute
+
+// Memory menyimpan bucket di memori proses. Setiap instance punya batasnya sendiri;
+// gunakan Redis jika API dijalankan lebih dari satu instance.
+type Memory struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full adalah saat bucket terisi penuh lagi; setelah itu bucket boleh dibuang
+	full time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+func (m *Memory) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+	perToken := float64(rule.perToken())
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rule.Limit), updated: now}
+		m.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(rule.Limit), b.tokens+float64(now.Sub(b.updated))/perToken)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.full = now.Add(time.Duration((float64(rule.Limit) - b.tokens) * perToken))
+	return rule.result(allowed, b.tokens), nil
+}
+
+// sweep membuang bucket yang sudah penuh, karena bucket baru akan sama isinya.
+// Dijalankan paling sering sekali per sweepInterval supaya Allow tetap murah.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package ratelimit membatasi jumlah request per key dengan token bucket, di memori
+// proses atau di Redis supaya batasnya berlaku bersama untuk beberapa instance.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule mengizinkan Limit request per Period. Bucket terisi penuh saat awal, jadi client
+// boleh memakai seluruh Limit sekaligus lalu mendapat token baru secara merata.
+type Rule struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRule membaca rule seperti "60/1m" (60 request per menit) atau "5/10s"
+func ParseRule(s string) (Rule, error) {
+	limit, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("rate limit must look like 60/1m, got %q", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil || n <= 0 {
+		return Rule{}, fmt.Errorf("rate limit count must be a positive number, got %q", s)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return Rule{}, fmt.Errorf("rate limit period must be a positive duration such as 1m, got %q", s)
+	}
+	return Rule{Limit: n, Period: d}, nil
+}
+
+func (r *Rule) UnmarshalText(text []byte) error {
+	rule, err := ParseRule(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rule
+	return nil
+}
+
+func (r Rule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%d/%s", r.Limit, r.Period)
+}
+
+// perToken adalah waktu untuk mengisi satu token
+func (r Rule) perToken() time.Duration {
+	return r.Period / time.Duration(r.Limit)
+}
+
+// Result adalah keputusan untuk satu request beserta nilai header RateLimit-*
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter adalah waktu sampai request berikutnya diizinkan; 0 jika Allowed
+	RetryAfter time.Duration
+	// Reset adalah waktu sampai bucket penuh lagi
+	Reset time.Duration
+}
+
+// result menghitung Result dari sisa token setelah request diputuskan
+func (r Rule) result(allowed bool, tokens float64) Result {
+	perToken := float64(r.perToken())
+	res := Result{
+		Allowed:   allowed,
+		Limit:     r.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(r.Limit) - tokens) * perToken),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - tokens) * perToken)
+	}
+	return res
+}
+
+// Limiter memutuskan apakah request dengan key tertentu masih boleh dilayani
+type Limiter interface {
+	Allow(ctx context.Context, key string, rule Rule) (Result, error)
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	rule, err := ParseRule("60/1m")
+	require.NoError(t, err)
+	assert.Equal(t, Rule{Limit: 60, Period: time.Minute}, rule)
+	assert.Equal(t, "60/1m0s", rule.String())
+
+	for _, bad := range []string{"60", "0/1m", "x/1m", "60/0s", "60/minute"} {
+		_, err := ParseRule(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+// checkBucket menjalankan skenario yang sama untuk setiap Limiter; advance memajukan jam
+func checkBucket(t *testing.T, limiter Limiter, advance func(time.Duration)) {
+	ctx := context.Background()
+	rule := Rule{Limit: 3, Period: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		res, err := limiter.Allow(ctx, "ip:10.0.0.1", rule)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+	res, err := limiter.Allow(ctx, "ip:10.0.0.1", rule)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.Reset)
+
+	// Key lain punya bucket sendiri
+	res, err = limiter.Allow(ctx, "ip:10.0.0.2", rule)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// Satu token kembali setiap detik
+	advance(time.Second)
+	res, err = limiter.Allow(ctx, "ip:10.0.0.1", rule)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestMemory(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	checkBucket(t, m, func(d time.Duration) { now = now.Add(d) })
+
+	// Bucket yang sudah penuh lagi dibuang saat pembersihan
+	now = now.Add(sweepInterval)
+	_, err := m.Allow(context.Background(), "ip:10.0.0.3", Rule{Limit: 1, Period: time.Second})
+	require.NoError(t, err)
+	assert.Len(t, m.buckets, 1)
+}
+
+func TestRedis(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	mr := miniredis.RunT(t)
+	mr.SetTime(now)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	r := NewRedis(client, "seleraseblak:ratelimit:")
+	defer r.Close()
+
+	checkBucket(t, r, func(d time.Duration) {
+		now = now.Add(d)
+		mr.SetTime(now)
+	})
+	assert.True(t, mr.Exists("seleraseblak:ratelimit:ip:10.0.0.1"))
+	// Key kedaluwarsa saat bucket penuh lagi, jadi client yang berhenti tidak meninggalkan key
+	assert.Equal(t, 3*time.Second, mr.TTL("seleraseblak:ratelimit:ip:10.0.0.1"))
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucket mengisi ulang dan mengambil satu token secara atomik. Waktu diambil dari
+// Redis supaya jam instance yang berbeda tidak memengaruhi hasil. Sisa token dikembalikan
+// sebagai teks karena Redis membulatkan angka pecahan dari Lua.
+var tokenBucket = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local rate = limit / period
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens = limit
+	updated = now
+end
+tokens = math.min(limit, tokens + math.max(0, now - updated) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.max(1, math.ceil((limit - tokens) / rate)))
+return {allowed, tostring(tokens)}
+`)
+
+// Redis menyimpan bucket di Redis sehingga batasnya berlaku untuk semua instance API
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis memakai client yang sudah dibuat; prefix memisahkan key aplikasi ini di Redis bersama
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+func (r *Redis) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	values, err := tokenBucket.Run(ctx, r.client, []string{r.prefix + key}, rule.Limit, rule.Period.Milliseconds()).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit reply %v", values)
+	}
+	allowed, _ := values[0].(int64)
+	text, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected rate limit reply %v", values)
+	}
+	return rule.result(allowed == 1, tokens), nil
+}
+
+// Ping memeriksa Redis masih bisa dihubungi, dipakai pengecekan readiness
+func (r *Redis) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
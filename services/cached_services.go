@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/cache"
+	"github.com/seleraseblak/backend/logging"
+)
+
+// Invalidasi memakai generasi: setiap key cache menyertakan token generasi namespace-nya,
+// dan perubahan data cukup mengganti token itu. Entri lama tidak pernah terbaca lagi dan
+// hilang sendiri lewat TTL/LRU, jadi tidak perlu menghapus key satu per satu.
+const (
+	// Produk, product master, topping, kategori dan level pedas
+	catalogNamespace = "catalog"
+	// Stok produk satu toko, berubah karena pesanan
+	storeNamespacePrefix = "store:"
+)
+
+// catalogCache dipakai bersama oleh semua decorator supaya perubahan di satu service
+// langsung membatalkan cache di service lain yang datanya ikut berubah
+type catalogCache struct {
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+func newCatalogCache(c cache.Cache, ttl time.Duration) *catalogCache {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	return &catalogCache{cache: c, ttl: ttl}
+}
+
+// generation mengembalikan token generasi namespace, membuat yang baru jika belum ada
+func (c *catalogCache) generation(ctx context.Context, namespace string) (string, error) {
+	key := "gen:" + namespace
+	value, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return string(value), nil
+	}
+	gen := randomName()
+	if err := c.cache.Set(ctx, key, []byte(gen), 0); err != nil {
+		return "", err
+	}
+	return gen, nil
+}
+
+// invalidate membuang semua entri namespace dengan mengganti generasinya. Tetap dijalankan
+// walaupun request sudah dibatalkan, karena datanya sudah berubah di database.
+func (c *catalogCache) invalidate(ctx context.Context, namespaces ...string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, ns := range namespaces {
+		if err := c.cache.Set(ctx, "gen:"+ns, []byte(randomName()), 0); err != nil {
+			logging.FromContext(ctx).Warn("cache invalidate failed", "namespace", ns, "error", err)
+		}
+	}
+}
+
+// cached membaca hasil dari cache atau memanggil load lalu menyimpannya. Hasil yang akan
+// disimpan dibaca dari primary. Cache yang bermasalah (misalnya Redis mati) hanya dicatat;
+// data tetap diambil dari load.
+func cached[T any](ctx context.Context, c *catalogCache, namespaces []string, key string, load func(context.Context) (T, error)) (T, error) {
+	logger := logging.FromContext(ctx)
+	fullKey := key
+	for _, ns := range namespaces {
+		gen, err := c.generation(ctx, ns)
+		if err != nil {
+			logger.Warn("cache generation failed", "namespace", ns, "error", err)
+			return load(ctx)
+		}
+		fullKey = ns + ":" + gen + ":" + fullKey
+	}
+
+	if data, ok, err := c.cache.Get(ctx, fullKey); err != nil {
+		logger.Warn("cache get failed", "key", key, "error", err)
+	} else if ok {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+	}
+
+	value, err := load(withPrimaryRead(ctx))
+	if err != nil {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		if err := c.cache.Set(ctx, fullKey, data, c.ttl); err != nil {
+			logger.Warn("cache set failed", "key", key, "error", err)
+		}
+	}
+	return value, nil
+}
+
+// paramsKey membuat key stabil dari params; json.Marshal mengurutkan key map
+func paramsKey(params map[string]interface{}) string {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Sprint(params)
+	}
+	return string(data)
+}
+
+// CatalogCache membungkus service katalog dengan cache baca dan invalidasi otomatis
+// saat create/update/delete. Semua decorator dari satu CatalogCache berbagi invalidasi.
+type CatalogCache struct {
+	c *catalogCache
+}
+
+func NewCatalogCache(c cache.Cache, ttl time.Duration) *CatalogCache {
+	return &CatalogCache{c: newCatalogCache(c, ttl)}
+}
+
+type cachedToppingService struct {
+	api.ToppingService
+	cache *catalogCache
+}
+
+func (cc *CatalogCache) ToppingService(next api.ToppingService) api.ToppingService {
+	return &cachedToppingService{ToppingService: next, cache: cc.c}
+}
+
+func (s *cachedToppingService) GetToppings(ctx context.Context) ([]api.Topping, error) {
+	return cached(ctx, s.cache, []string{catalogNamespace}, "toppings", s.ToppingService.GetToppings)
+}
+
+func (s *cachedToppingService) GetTopping(ctx context.Context, id int) (*api.Topping, error) {
+	return cached(ctx, s.cache, []string{catalogNamespace}, fmt.Sprintf("topping:%d", id), func(ctx context.Context) (*api.Topping, error) {
+		return s.ToppingService.GetTopping(ctx, id)
+	})
+}
+
+func (s *cachedToppingService) CreateTopping(ctx context.Context, topping *api.Topping) error {
+	return invalidateAfter(ctx, s.cache, s.ToppingService.CreateTopping(ctx, topping))
+}
+
+func (s *cachedToppingService) UpdateTopping(ctx context.Context, id int, topping *api.Topping) error {
+	return invalidateAfter(ctx, s.cache, s.ToppingService.UpdateTopping(ctx, id, topping))
+}
+
+func (s *cachedToppingService) DeleteTopping(ctx context.Context, id int) error {
+	return invalidateAfter(ctx, s.cache, s.ToppingService.DeleteTopping(ctx, id))
+}
+
+type cachedSpicyLevelService struct {
+	api.SpicyLevelService
+	cache *catalogCache
+}
+
+func (cc *CatalogCache) SpicyLevelService(next api.SpicyLevelService) api.SpicyLevelService {
+	return &cachedSpicyLevelService{SpicyLevelService: next, cache: cc.c}
+}
+
+func (s *cachedSpicyLevelService) GetSpicyLevels(ctx context.Context) ([]api.SpicyLevel, error) {
+	return cached(ctx, s.cache, []string{catalogNamespace}, "spicy-levels", s.SpicyLevelService.GetSpicyLevels)
+}
+
+type cachedProductService struct {
+	api.ProductService
+	cache *catalogCache
+}
+
+func (cc *CatalogCache) ProductService(next api.ProductService) api.ProductService {
+	return &cachedProductService{ProductService: next, cache: cc.c}
+}
+
+// ListProducts ikut dibatalkan oleh pesanan di toko itu karena berisi stok
+func (s *cachedProductService) ListProducts(ctx context.Context, storeID string, params map[string]interface{}) ([]api.Product, error) {
+	namespaces := []string{catalogNamespace, storeNamespacePrefix + storeID}
+	return cached(ctx, s.cache, namespaces, "products:"+storeID+":"+paramsKey(params), func(ctx context.Context) ([]api.Product, error) {
+		return s.ProductService.ListProducts(ctx, storeID, params)
+	})
+}
+
+func (s *cachedProductService) CreateProduct(ctx context.Context, product *api.Product) error {
+	return invalidateAfter(ctx, s.cache, s.ProductService.CreateProduct(ctx, product))
+}
+
+func (s *cachedProductService) UpdateProduct(ctx context.Context, id int, product *api.Product) error {
+	return invalidateAfter(ctx, s.cache, s.ProductService.UpdateProduct(ctx, id, product))
+}
+
+func (s *cachedProductService) DeleteProduct(ctx context.Context, id int) error {
+	return invalidateAfter(ctx, s.cache, s.ProductService.DeleteProduct(ctx, id))
+}
+
+type cachedProductMasterService struct {
+	api.ProductMasterService
+	cache *catalogCache
+}
+
+func (cc *CatalogCache) ProductMasterService(next api.ProductMasterService) api.ProductMasterService {
+	return &cachedProductMasterService{ProductMasterService: next, cache: cc.c}
+}
+
+func (s *cachedProductMasterService) ListProductMasters(ctx context.Context, params map[string]interface{}) ([]api.ProductMaster, error) {
+	return cached(ctx, s.cache, []string{catalogNamespace}, "product-masters:"+paramsKey(params), func(ctx context.Context) ([]api.ProductMaster, error) {
+		return s.ProductMasterService.ListProductMasters(ctx, params)
+	})
+}
+
+func (s *cachedProductMasterService) CreateProductMaster(ctx context.Context, product *api.ProductMaster) error {
+	return invalidateAfter(ctx, s.cache, s.ProductMasterService.CreateProductMaster(ctx, product))
+}
+
+func (s *cachedProductMasterService) UpdateProductMaster(ctx context.Context, id string, product *api.ProductMaster) error {
+	return invalidateAfter(ctx, s.cache, s.ProductMasterService.UpdateProductMaster(ctx, id, product))
+}
+
+func (s *cachedProductMasterService) DeleteProductMaster(ctx context.Context, id string) error {
+	return invalidateAfter(ctx, s.cache, s.ProductMasterService.DeleteProductMaster(ctx, id))
+}
+
+// Service berikut tidak di-cache, tapi perubahannya mengubah data katalog yang di-cache
+
+type cachedModifierService struct {
+	api.ModifierService
+	cache *catalogCache
+}
+
+func (cc *CatalogCache) ModifierService(next api.ModifierService) api.ModifierService {
+	return &cachedModifierService{ModifierService: next, cache: cc.c}
+}
+
+func (s *cachedModifierService) SetModifierGroups(ctx context.Context, productID int, groups []api.ModifierGroup) ([]api.ModifierGroup, error) {
+	result, err := s.ModifierService.SetModifierGroups(ctx, productID, groups)
+	return result, invalidateAfter(ctx, s.cache, err)
+}
+
+type cachedPhotoService struct {
+	api.PhotoService
+	cache *catalogCache
+}
+
+func (cc *CatalogCache) PhotoService(next api.PhotoService) api.PhotoService {
+	return &cachedPhotoService{PhotoService: next, cache: cc.c}
+}
+
+func (s *cachedPhotoService) UploadProductPhoto(ctx context.Context, productID int, upload api.PhotoUpload) (*api.Product, error) {
+	product, err := s.PhotoService.UploadProductPhoto(ctx, productID, upload)
+	return product, invalidateAfter(ctx, s.cache, err)
+}
+
+func (s *cachedPhotoService) UploadProductMasterPhoto(ctx context.Context, id string, upload api.PhotoUpload) (*api.ProductMaster, error) {
+	master, err := s.PhotoService.UploadProductMasterPhoto(ctx, id, upload)
+	return master, invalidateAfter(ctx, s.cache, err)
+}
+
+type cachedCategoryService struct {
+	api.CategoryService
+	cache *catalogCache
+}
+
+func (cc *CatalogCache) CategoryService(next api.CategoryService) api.CategoryService {
+	return &cachedCategoryService{CategoryService: next, cache: cc.c}
+}
+
+func (s *cachedCategoryService) CreateCategory(ctx context.Context, category *api.Category) error {
+	return invalidateAfter(ctx, s.cache, s.CategoryService.CreateCategory(ctx, category))
+}
+
+func (s *cachedCategoryService) UpdateCategory(ctx context.Context, id int, category *api.Category) error {
+	return invalidateAfter(ctx, s.cache, s.CategoryService.UpdateCategory(ctx, id, category))
+}
+
+func (s *cachedCategoryService) DeleteCategory(ctx context.Context, id int) error {
+	return invalidateAfter(ctx, s.cache, s.CategoryService.DeleteCategory(ctx, id))
+}
+
+type cachedProductToppingService struct {
+	api.ProductToppingService
+	cache *catalogCache
+}
+
+func (cc *CatalogCache) ProductToppingService(next api.ProductToppingService) api.ProductToppingService {
+	return &cachedProductToppingService{ProductToppingService: next, cache: cc.c}
+}
+
+func (s *cachedProductToppingService) CreateProductTopping(ctx context.Context, productTopping *api.ProductTopping) error {
+	return invalidateAfter(ctx, s.cache, s.ProductToppingService.CreateProductTopping(ctx, productTopping))
+}
+
+func (s *cachedProductToppingService) DeleteProductTopping(ctx context.Context, productID, toppingID int) error {
+	return invalidateAfter(ctx, s.cache, s.ProductToppingService.DeleteProductTopping(ctx, productID, toppingID))
+}
+
+type cachedCatalogService struct {
+	api.CatalogService
+	cache *catalogCache
+}
+
+func (cc *CatalogCache) CatalogService(next api.CatalogService) api.CatalogService {
+	return &cachedCatalogService{CatalogService: next, cache: cc.c}
+}
+
+func (s *cachedCatalogService) ImportCatalog(ctx context.Context, catalog *api.CatalogExport) (*api.CatalogImportResult, error) {
+	result, err := s.CatalogService.ImportCatalog(ctx, catalog)
+	return result, invalidateAfter(ctx, s.cache, err)
+}
+
+type cachedOrderService struct {
+	api.OrderService
+	cache *catalogCache
+}
+
+// OrderService membatalkan cache produk toko saat stok berubah karena pesanan dibuat atau dibatalkan
+func (cc *CatalogCache) OrderService(next api.OrderService) api.OrderService {
+	return &cachedOrderService{OrderService: next, cache: cc.c}
+}
+
+func (s *cachedOrderService) CreateOrder(ctx context.Context, req *api.OrderRequest) (*api.Order, error) {
+	order, err := s.OrderService.CreateOrder(ctx, req)
+	if err == nil {
+		s.cache.invalidate(ctx, storeNamespacePrefix+order.StoreID)
+	}
+	return order, err
+}
+
+func (s *cachedOrderService) UpdateOrderStatus(ctx context.Context, id int, status string) (*api.Order, error) {
+	order, err := s.OrderService.UpdateOrderStatus(ctx, id, status)
+	if err == nil {
+		s.cache.invalidate(ctx, storeNamespacePrefix+order.StoreID)
+	}
+	return order, err
+}
+
+// invalidateAfter membatalkan cache katalog jika operasi tulis berhasil
+func invalidateAfter(ctx context.Context, c *catalogCache, err error) error {
+	if err == nil {
+		c.invalidate(ctx, catalogNamespace)
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeToppingService struct {
+	api.ToppingService
+	toppings []api.Topping
+	reads    int
+	// reads yang diarahkan ke primary
+	primaryReads int
+	err          error
+}
+
+func (f *fakeToppingService) GetToppings(ctx context.Context) ([]api.Topping, error) {
+	f.reads++
+	if ctx.Value(primaryReadKey{}) != nil {
+		f.primaryReads++
+	}
+	return f.toppings, f.err
+}
+
+func (f *fakeToppingService) UpdateTopping(ctx context.Context, id int, topping *api.Topping) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.toppings[0] = *topping
+	return nil
+}
+
+type fakeProductService struct {
+	api.ProductService
+	reads map[string]int
+}
+
+func (f *fakeProductService) ListProducts(ctx context.Context, storeID string, params map[string]interface{}) ([]api.Product, error) {
+	f.reads[storeID]++
+	return []api.Product{{ID: 1, StoreID: storeID, StockQuantity: 10 - f.reads[storeID]}}, nil
+}
+
+type fakeOrderService struct {
+	api.OrderService
+}
+
+func (f *fakeOrderService) CreateOrder(ctx context.Context, req *api.OrderRequest) (*api.Order, error) {
+	return &api.Order{ID: 1, StoreID: "store-a"}, nil
+}
+
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (failingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func TestCachedToppingService(t *testing.T) {
+	ctx := context.Background()
+	next := &fakeToppingService{toppings: []api.Topping{{ID: 1, Name: "Ceker"}}}
+	svc := NewCatalogCache(cache.NewMemory(0), time.Minute).ToppingService(next)
+
+	for i := 0; i < 3; i++ {
+		toppings, err := svc.GetToppings(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "Ceker", toppings[0].Name)
+	}
+	assert.Equal(t, 1, next.reads)
+
+	require.NoError(t, svc.UpdateTopping(ctx, 1, &api.Topping{ID: 1, Name: "Bakso"}))
+	toppings, err := svc.GetToppings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Bakso", toppings[0].Name)
+	assert.Equal(t, 2, next.reads)
+	// Cache diisi dari primary, bukan replica yang mungkin belum menerima update
+	assert.Equal(t, 2, next.primaryReads)
+
+	// Update yang gagal tidak membuang cache
+	next.err = errors.New("db down")
+	assert.Error(t, svc.UpdateTopping(ctx, 1, &api.Topping{ID: 1, Name: "Sosis"}))
+	next.err = nil
+	_, err = svc.GetToppings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.reads)
+}
+
+func TestCachedToppingServiceDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	next := &fakeToppingService{err: errors.New("db down")}
+	svc := NewCatalogCache(cache.NewMemory(0), time.Minute).ToppingService(next)
+
+	_, err := svc.GetToppings(ctx)
+	assert.Error(t, err)
+	next.err = nil
+	_, err = svc.GetToppings(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, next.reads)
+}
+
+func TestCachedProductsInvalidatedPerStore(t *testing.T) {
+	ctx := context.Background()
+	cc := NewCatalogCache(cache.NewMemory(0), time.Minute)
+	products := &fakeProductService{reads: map[string]int{}}
+	productSvc := cc.ProductService(products)
+	orderSvc := cc.OrderService(&fakeOrderService{})
+
+	params := map[string]interface{}{"page": 1, "limit": 10}
+	for _, store := range []string{"store-a", "store-b", "store-a", "store-b"} {
+		_, err := productSvc.ListProducts(ctx, store, params)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, map[string]int{"store-a": 1, "store-b": 1}, products.reads)
+
+	// Parameter berbeda memakai key berbeda
+	_, err := productSvc.ListProducts(ctx, "store-a", map[string]interface{}{"page": 2, "limit": 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, products.reads["store-a"])
+
+	// Pesanan mengubah stok: hanya toko itu yang dibaca ulang
+	_, err = orderSvc.CreateOrder(ctx, &api.OrderRequest{})
+	require.NoError(t, err)
+	list, err := productSvc.ListProducts(ctx, "store-a", params)
+	require.NoError(t, err)
+	assert.Equal(t, 7, list[0].StockQuantity)
+	_, err = productSvc.ListProducts(ctx, "store-b", params)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"store-a": 3, "store-b": 1}, products.reads)
+}
+
+func TestCachedServiceSharedInvalidation(t *testing.T) {
+	ctx := context.Background()
+	cc := NewCatalogCache(cache.NewMemory(0), time.Minute)
+	products := &fakeProductService{reads: map[string]int{}}
+	productSvc := cc.ProductService(products)
+	toppingSvc := cc.ToppingService(&fakeToppingService{toppings: []api.Topping{{ID: 1}}})
+
+	_, err := productSvc.ListProducts(ctx, "store-a", nil)
+	require.NoError(t, err)
+	// Produk memuat topping, jadi perubahan topping membatalkan daftar produk juga
+	require.NoError(t, toppingSvc.UpdateTopping(ctx, 1, &api.Topping{ID: 1, Name: "Baru"}))
+	_, err = productSvc.ListProducts(ctx, "store-a", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, products.reads["store-a"])
+}
+
+func TestCachedServiceFallsBackWhenCacheFails(t *testing.T) {
+	ctx := context.Background()
+	next := &fakeToppingService{toppings: []api.Topping{{ID: 1, Name: "Ceker"}}}
+	svc := NewCatalogCache(failingCache{}, time.Minute).ToppingService(next)
+
+	for i := 0; i < 2; i++ {
+		toppings, err := svc.GetToppings(ctx)
+		require.NoError(t, err)
+		assert.Len(t, toppings, 1)
+	}
+	assert.Equal(t, 2, next.reads)
+	assert.NoError(t, svc.UpdateTopping(ctx, 1, &api.Topping{ID: 1}))
+}
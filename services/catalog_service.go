@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Naikkan jika format CatalogExport berubah tidak kompatibel
+const catalogExportVersion = 1
+
+type catalogService struct {
+	db *gorm.DB
+}
+
+func NewCatalogService(db *gorm.DB) api.CatalogService {
+	return &catalogService{db: db}
+}
+
+// ExportCatalog mengambil semua kategori, topping dan product master (termasuk draft dan
+// yang diarsipkan). Kategori product master ditulis sebagai category_ids.
+func (s *catalogService) ExportCatalog(ctx context.Context) (*api.CatalogExport, error) {
+	export := &api.CatalogExport{
+		Version:        catalogExportVersion,
+		ExportedAt:     time.Now().UTC(),
+		Categories:     []api.Category{},
+		Toppings:       []api.Topping{},
+		ProductMasters: []api.ProductMaster{},
+	}
+	if err := s.db.WithContext(ctx).Order("id").Find(&export.Categories).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Order("id").Find(&export.Toppings).Error; err != nil {
+		return nil, err
+	}
+	err := preloadCategories(s.db.WithContext(ctx)).Order("product_name, id").Find(&export.ProductMasters).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range export.ProductMasters {
+		export.ProductMasters[i].CategoryIDs = linkedCategoryIDs(export.ProductMasters[i].Categories)
+		export.ProductMasters[i].Categories = nil
+	}
+	return export, nil
+}
+
+// ImportCatalog menulis hasil ExportCatalog dalam satu transaksi. Baris dengan ID yang sama
+// ditimpa, baris lain di database dibiarkan.
+func (s *catalogService) ImportCatalog(ctx context.Context, catalog *api.CatalogExport) (*api.CatalogImportResult, error) {
+	if catalog.Version != catalogExportVersion {
+		return nil, &api.ValidationError{Message: fmt.Sprintf("unsupported catalog version %d", catalog.Version)}
+	}
+	categories, err := orderCategoriesForImport(catalog.Categories)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range catalog.ProductMasters {
+		if p.ID == "" {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("product master %q has no id", p.ProductName)}
+		}
+	}
+
+	upsert := clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, UpdateAll: true}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Urut parent dulu supaya foreign key parent_id selalu terpenuhi
+		for i := range categories {
+			if err := tx.Clauses(upsert).Create(&categories[i]).Error; err != nil {
+				return err
+			}
+		}
+		if len(catalog.Toppings) > 0 {
+			if err := tx.Clauses(upsert).Create(&catalog.Toppings).Error; err != nil {
+				return err
+			}
+		}
+		if err := syncSequences(tx, "Category", "Topping"); err != nil {
+			return err
+		}
+
+		for i := range catalog.ProductMasters {
+			product := &catalog.ProductMasters[i]
+			if product.CategoryIDs == nil {
+				product.CategoryIDs = linkedCategoryIDs(product.Categories)
+			}
+			if err := tx.Clauses(upsert).Omit("Categories").Create(product).Error; err != nil {
+				return err
+			}
+			if err := setProductMasterCategories(tx, product); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.CatalogImportResult{
+		Categories:     len(categories),
+		Toppings:       len(catalog.Toppings),
+		ProductMasters: len(catalog.ProductMasters),
+	}, nil
+}
+
+func linkedCategoryIDs(categories []api.Category) []int {
+	ids := make([]int, 0, len(categories))
+	for _, c := range categories {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+// orderCategoriesForImport memeriksa kategori dari file dan mengurutkannya parent lebih dulu.
+// Parent harus ada di file yang sama dan tidak boleh membentuk siklus.
+func orderCategoriesForImport(categories []api.Category) ([]api.Category, error) {
+	byID := make(map[int]api.Category, len(categories))
+	for _, c := range categories {
+		if c.ID == 0 {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("category %q has no id", c.Name)}
+		}
+		if c.Slug == "" || c.Slug != slugify(c.Slug) {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("category %d has an invalid slug %q", c.ID, c.Slug)}
+		}
+		if _, ok := byID[c.ID]; ok {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("category %d appears twice", c.ID)}
+		}
+		c.Children = nil
+		byID[c.ID] = c
+	}
+
+	ordered := make([]api.Category, 0, len(categories))
+	state := make(map[int]int) // 1 = sedang dikunjungi, 2 = sudah masuk ordered
+	var visit func(id int) error
+	visit = func(id int) error {
+		switch state[id] {
+		case 1:
+			return &api.ValidationError{Message: fmt.Sprintf("category %d is its own ancestor", id)}
+		case 2:
+			return nil
+		}
+		state[id] = 1
+		c := byID[id]
+		if c.ParentID != nil {
+			if _, ok := byID[*c.ParentID]; !ok {
+				return &api.ValidationError{Message: fmt.Sprintf("parent %d of category %d is not in the catalog", *c.ParentID, id)}
+			}
+			if err := visit(*c.ParentID); err != nil {
+				return err
+			}
+		}
+		state[id] = 2
+		ordered = append(ordered, c)
+		return nil
+	}
+	for _, c := range categories {
+		if err := visit(c.ID); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
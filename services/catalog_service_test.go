@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderCategoriesForImport(t *testing.T) {
+	one, two := 1, 2
+	ordered, err := orderCategoriesForImport([]api.Category{
+		{ID: 3, Name: "Kuah Pedas", Slug: "kuah-pedas", ParentID: &two},
+		{ID: 2, Name: "Kuah", Slug: "kuah", ParentID: &one},
+		{ID: 1, Name: "Seblak", Slug: "seblak"},
+		{ID: 4, Name: "Minuman", Slug: "minuman"},
+	})
+	require.NoError(t, err)
+	ids := make([]int, len(ordered))
+	for i, c := range ordered {
+		ids[i] = c.ID
+	}
+	assert.Equal(t, []int{1, 2, 3, 4}, ids)
+}
+
+func TestOrderCategoriesForImportRejectsInvalid(t *testing.T) {
+	one, two, nine := 1, 2, 9
+	cases := map[string][]api.Category{
+		"no id":          {{Name: "Seblak", Slug: "seblak"}},
+		"invalid slug":   {{ID: 1, Name: "Seblak", Slug: "Seblak Kuah"}},
+		"duplicate":      {{ID: 1, Slug: "a"}, {ID: 1, Slug: "b"}},
+		"missing parent": {{ID: 1, Slug: "a", ParentID: &nine}},
+		"cycle":          {{ID: 1, Slug: "a", ParentID: &two}, {ID: 2, Slug: "b", ParentID: &one}},
+	}
+	for name, categories := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := orderCategoriesForImport(categories)
+			var validationErr *api.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestImportCatalogRejectsUnknownVersion(t *testing.T) {
+	svc := &catalogService{}
+	_, err := svc.ImportCatalog(context.Background(), &api.CatalogExport{Version: 99})
+	var validationErr *api.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type categoryService struct {
+	db *gorm.DB
+}
+
+func NewCategoryService(db *gorm.DB) api.CategoryService {
+	return &categoryService{db: db}
+}
+
+// SetupCategoryJoinTable membuat GORM memakai tabel "Product_Master_Category" untuk relasi
+// many2many, bukan nama bawaan "product_master_categories". Dipanggil sekali setelah database
+// dibuka, sebelum service kategori, product master, menu dan katalog dipakai.
+func SetupCategoryJoinTable(db *gorm.DB) error {
+	return db.SetupJoinTable(&api.ProductMaster{}, "Categories", &api.ProductMasterCategory{})
+}
+
+// ListCategories mengembalikan pohon kategori, diurutkan display_order lalu nama.
+// Default hanya kategori published; params["status"] bisa "draft" atau "all" untuk admin.
+func (s *categoryService) ListCategories(ctx context.Context, params map[string]interface{}) ([]api.Category, error) {
+	query := s.db.WithContext(ctx).Model(&api.Category{})
+	switch status, _ := params["status"].(string); status {
+	case "", api.StatusPublished:
+		query = query.Where("status = ?", api.StatusPublished)
+	case "all":
+		query = query.Where("status <> ?", api.StatusArchived)
+	default:
+		query = query.Where("status = ?", status)
+	}
+
+	var categories []api.Category
+	if err := query.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories), nil
+}
+
+func (s *categoryService) GetCategory(ctx context.Context, id int) (*api.Category, error) {
+	var category api.Category
+	if err := s.db.WithContext(ctx).Where("id = ? AND status <> ?", id, api.StatusArchived).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, category *api.Category) error {
+	if err := s.validateCategory(ctx, 0, category); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Create(category).Error
+}
+
+func (s *categoryService) UpdateCategory(ctx context.Context, id int, category *api.Category) error {
+	if _, err := s.GetCategory(ctx, id); err != nil {
+		return err
+	}
+	if err := s.validateCategory(ctx, id, category); err != nil {
+		return err
+	}
+	category.ID = id
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&api.Category{}).Where("id = ?", id).
+			Select("parent_id", "name", "slug", "icon", "display_order", "status").
+			Updates(category).Error
+		if err != nil {
+			return err
+		}
+		// Nama kategori ikut tersimpan di kolom category product master
+		ids, err := linkedProductMasters(tx, id)
+		if err != nil {
+			return err
+		}
+		return syncLegacyCategories(tx, ids)
+	})
+}
+
+// DeleteCategory mengarsipkan kategori. Sub-kategori aktif harus dipindah atau dihapus dulu.
+func (s *categoryService) DeleteCategory(ctx context.Context, id int) error {
+	var children int64
+	err := s.db.WithContext(ctx).Model(&api.Category{}).Where("parent_id = ? AND status <> ?", id, api.StatusArchived).Count(&children).Error
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return &api.ValidationError{Message: "category still has subcategories"}
+	}
+	return s.db.WithContext(ctx).Model(&api.Category{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
+}
+
+func (s *categoryService) ListCategoryProducts(ctx context.Context, slug string, params map[string]interface{}) (*api.Category, []api.ProductMaster, error) {
+	var all []api.Category
+	if err := s.db.WithContext(ctx).Where("status = ?", api.StatusPublished).Find(&all).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var root *api.Category
+	for _, tree := range buildCategoryTree(all) {
+		if root = findCategory(tree, slug); root != nil {
+			break
+		}
+	}
+	if root == nil {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+
+	page, limit := 1, 50
+	if p, ok := params["page"].(int); ok && p > 0 {
+		page = p
+	}
+	if l, ok := params["limit"].(int); ok && l > 0 {
+		limit = l
+	}
+
+	var products []api.ProductMaster
+	err := preloadCategories(s.db.WithContext(ctx)).
+		Where("status = ?", api.StatusPublished).
+		Where(`id IN (SELECT product_master_id FROM "Product_Master_Category" WHERE category_id IN ?)`, categoryIDs(*root)).
+		Order("product_name").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&products).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return root, products, nil
+}
+
+func (s *categoryService) validateCategory(ctx context.Context, id int, category *api.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return &api.ValidationError{Message: "category name is required"}
+	}
+	if category.Slug == "" {
+		category.Slug = slugify(category.Name)
+	}
+	if category.Slug == "" || category.Slug != slugify(category.Slug) {
+		return &api.ValidationError{Message: "slug may only contain lowercase letters, digits and dashes"}
+	}
+	switch category.Status {
+	case "":
+		category.Status = api.StatusPublished
+	case api.StatusPublished, api.StatusDraft:
+	default:
+		return &api.ValidationError{Message: fmt.Sprintf("invalid category status %q", category.Status)}
+	}
+
+	var taken int64
+	if err := s.db.WithContext(ctx).Model(&api.Category{}).Where("slug = ? AND id <> ?", category.Slug, id).Count(&taken).Error; err != nil {
+		return err
+	}
+	if taken > 0 {
+		return &api.ValidationError{Message: fmt.Sprintf("slug %q is already used", category.Slug)}
+	}
+
+	// Parent harus ada dan tidak boleh kategori ini sendiri atau turunannya
+	for parentID := category.ParentID; parentID != nil; {
+		if *parentID == id {
+			return &api.ValidationError{Message: "category cannot be its own ancestor"}
+		}
+		var parent api.Category
+		err := s.db.WithContext(ctx).Where("id = ? AND status <> ?", *parentID, api.StatusArchived).First(&parent).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &api.ValidationError{Message: "parent category not found"}
+		}
+		if err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+// buildCategoryTree menyusun daftar datar menjadi pohon. Kategori yang parent-nya tidak
+// ada di daftar (misalnya draft atau diarsipkan) ikut disembunyikan.
+func buildCategoryTree(categories []api.Category) []api.Category {
+	children := make(map[int][]api.Category)
+	var roots []api.Category
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var attach func(list []api.Category) []api.Category
+	attach = func(list []api.Category) []api.Category {
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].DisplayOrder != list[j].DisplayOrder {
+				return list[i].DisplayOrder < list[j].DisplayOrder
+			}
+			return list[i].Name < list[j].Name
+		})
+		for i := range list {
+			list[i].Children = attach(children[list[i].ID])
+		}
+		return list
+	}
+	roots = attach(roots)
+	if roots == nil {
+		roots = []api.Category{}
+	}
+	return roots
+}
+
+func findCategory(tree api.Category, slug string) *api.Category {
+	if tree.Slug == slug {
+		return &tree
+	}
+	for _, child := range tree.Children {
+		if found := findCategory(child, slug); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// categoryIDs mengembalikan ID kategori dan semua turunannya
+func categoryIDs(tree api.Category) []int {
+	ids := []int{tree.ID}
+	for _, child := range tree.Children {
+		ids = append(ids, categoryIDs(child)...)
+	}
+	return ids
+}
+
+// slugify mengubah nama menjadi slug huruf kecil dengan tanda hubung, contoh "Seblak Kuah!" menjadi "seblak-kuah"
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func preloadCategories(db *gorm.DB) *gorm.DB {
+	return db.Preload("Categories", func(db *gorm.DB) *gorm.DB {
+		return db.Where(`"Category".status <> ?`, api.StatusArchived).Order(`"Category".display_order, "Category".name`)
+	})
+}
+
+// setProductMasterCategories mengganti kategori product master. CategoryIDs dipakai jika dikirim;
+// jika tidak, nama di Category (format lama) dicocokkan lewat slug dan dibuat bila belum ada.
+// Tanpa keduanya kategori tidak diubah.
+func setProductMasterCategories(tx *gorm.DB, product *api.ProductMaster) error {
+	var ids []int
+	switch {
+	case product.CategoryIDs != nil:
+		ids = product.CategoryIDs
+		var found int64
+		err := tx.Model(&api.Category{}).Where("id IN ? AND status <> ?", ids, api.StatusArchived).Count(&found).Error
+		if err != nil {
+			return err
+		}
+		if int(found) != len(uniqueInts(ids)) {
+			return &api.ValidationError{Message: "unknown category in category_ids"}
+		}
+	case product.Category != nil:
+		categories, err := ensureCategories(tx, product.Category)
+		if err != nil {
+			return err
+		}
+		for _, c := range categories {
+			ids = append(ids, c.ID)
+		}
+	default:
+		return nil
+	}
+
+	if err := tx.Where("product_master_id = ?", product.ID).Delete(&api.ProductMasterCategory{}).Error; err != nil {
+		return err
+	}
+	links := make([]api.ProductMasterCategory, 0, len(ids))
+	for _, id := range uniqueInts(ids) {
+		links = append(links, api.ProductMasterCategory{ProductMasterID: product.ID, CategoryID: id})
+	}
+	if len(links) > 0 {
+		if err := tx.Create(&links).Error; err != nil {
+			return err
+		}
+	}
+	if err := syncLegacyCategories(tx, []string{product.ID}); err != nil {
+		return err
+	}
+
+	var categories []api.Category
+	err := tx.Where("id IN (?)", tx.Model(&api.ProductMasterCategory{}).Select("category_id").Where("product_master_id = ?", product.ID)).
+		Order("display_order, name").Find(&categories).Error
+	if err != nil {
+		return err
+	}
+	product.Categories = categories
+	product.Category = categoryNames(categories)
+	return nil
+}
+
+// ensureCategories mencari kategori berdasarkan nama atau slug-nya, membuat yang belum ada.
+// Nama dicocokkan juga supaya kategori yang slug-nya sudah diubah tetap ditemukan.
+func ensureCategories(tx *gorm.DB, names []string) ([]api.Category, error) {
+	var categories []api.Category
+	seen := make(map[int]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		slug := slugify(name)
+		if slug == "" {
+			continue
+		}
+
+		var category api.Category
+		err := tx.Where("status <> ? AND (lower(name) = lower(?) OR slug = ?)", api.StatusArchived, name, slug).
+			Order("id").First(&category).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			category = api.Category{Name: name, Slug: slug, Status: api.StatusPublished}
+			err = tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "slug"}}, DoNothing: true}).Create(&category).Error
+			if err == nil && category.ID == 0 {
+				// Slug sudah dipakai kategori yang diarsipkan
+				err = tx.Where("slug = ?", slug).First(&category).Error
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !seen[category.ID] {
+			seen[category.ID] = true
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+// syncLegacyCategories menulis ulang kolom category (nama kategori) milik product master
+func syncLegacyCategories(tx *gorm.DB, productMasterIDs []string) error {
+	if len(productMasterIDs) == 0 {
+		return nil
+	}
+	var links []struct {
+		ProductMasterID string
+		Name            string
+	}
+	err := tx.Table(`"Product_Master_Category" AS pmc`).
+		Select("pmc.product_master_id, c.name").
+		Joins(`JOIN "Category" c ON c.id = pmc.category_id`).
+		Where("pmc.product_master_id IN ? AND c.status <> ?", productMasterIDs, api.StatusArchived).
+		Order("c.display_order, c.name").
+		Scan(&links).Error
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string][]string, len(productMasterIDs))
+	for _, id := range productMasterIDs {
+		names[id] = []string{}
+	}
+	for _, l := range links {
+		names[l.ProductMasterID] = append(names[l.ProductMasterID], l.Name)
+	}
+	for id, list := range names {
+		err := tx.Model(&api.ProductMaster{}).Where("id = ?", id).
+			Select("category").Updates(&api.ProductMaster{Category: list}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkedProductMasters mengembalikan ID product master yang memakai kategori
+func linkedProductMasters(tx *gorm.DB, categoryID int) ([]string, error) {
+	var ids []string
+	err := tx.Model(&api.ProductMasterCategory{}).Where("category_id = ?", categoryID).Pluck("product_master_id", &ids).Error
+	return ids, err
+}
+
+func categoryNames(categories []api.Category) []string {
+	names := make([]string, 0, len(categories))
+	for _, c := range categories {
+		names = append(names, c.Name)
+	}
+	return names
+}
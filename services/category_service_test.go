@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "seblak-kuah", slugify("Seblak Kuah"))
+	assert.Equal(t, "seblak-kuah", slugify("  Seblak -- Kuah!! "))
+	assert.Equal(t, "level-5", slugify("Level 5"))
+	assert.Equal(t, "es-teh", slugify("Es Teh 🍹"))
+	assert.Equal(t, "", slugify("!!!"))
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	one, two, missing := 1, 2, 99
+	tree := buildCategoryTree([]api.Category{
+		{ID: 3, Name: "Seblak Kering", ParentID: &one, DisplayOrder: 2},
+		{ID: 1, Name: "Seblak", DisplayOrder: 1},
+		{ID: 4, Name: "Seblak Kuah", ParentID: &one, DisplayOrder: 1},
+		{ID: 2, Name: "Minuman", DisplayOrder: 2},
+		{ID: 5, Name: "Es Teh", ParentID: &two},
+		{ID: 6, Name: "Yatim", ParentID: &missing},
+		{ID: 7, Name: "Camilan", DisplayOrder: 1},
+	})
+
+	require.Len(t, tree, 3)
+	assert.Equal(t, "Camilan", tree[0].Name)
+	assert.Equal(t, "Seblak", tree[1].Name)
+	assert.Equal(t, "Minuman", tree[2].Name)
+	require.Len(t, tree[1].Children, 2)
+	assert.Equal(t, "Seblak Kuah", tree[1].Children[0].Name)
+	assert.Equal(t, "Seblak Kering", tree[1].Children[1].Name)
+	assert.Equal(t, []int{1, 4, 3}, categoryIDs(tree[1]))
+
+	assert.Empty(t, buildCategoryTree(nil))
+	assert.NotNil(t, buildCategoryTree(nil))
+}
+
+func TestFindCategory(t *testing.T) {
+	one := 1
+	tree := buildCategoryTree([]api.Category{
+		{ID: 1, Name: "Seblak", Slug: "seblak"},
+		{ID: 2, Name: "Seblak Kuah", Slug: "seblak-kuah", ParentID: &one},
+	})
+
+	found := findCategory(tree[0], "seblak-kuah")
+	require.NotNil(t, found)
+	assert.Equal(t, 2, found.ID)
+	assert.Nil(t, findCategory(tree[0], "minuman"))
+}
+
+func TestProductMasterCategoriesJoinTable(t *testing.T) {
+	// Tanpa koneksi: hanya parsing schema yang dipakai
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	require.NoError(t, SetupCategoryJoinTable(db))
+
+	stmt := &gorm.Statement{DB: db}
+	require.NoError(t, stmt.Parse(&api.ProductMaster{}))
+	rel := stmt.Schema.Relationships.Relations["Categories"]
+	require.NotNil(t, rel)
+	require.NotNil(t, rel.JoinTable)
+	assert.Equal(t, "Product_Master_Category", rel.JoinTable.Table)
+	assert.Equal(t, "product_master_id", rel.References[0].ForeignKey.DBName)
+	assert.Equal(t, "category_id", rel.References[1].ForeignKey.DBName)
+}
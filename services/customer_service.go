@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+)
+
+type customerService struct {
+	db *gorm.DB
+}
+
+func NewCustomerService(db *gorm.DB) api.CustomerService {
+	return &customerService{db: db}
+}
+
+func (s *customerService) CreateCustomer(ctx context.Context, customer *api.Customer) error {
+	customer.Phone = strings.TrimSpace(customer.Phone)
+	if customer.Phone == "" {
+		return &api.ValidationError{Message: "phone is required"}
+	}
+
+	var existing int64
+	if err := s.db.WithContext(ctx).Model(&api.Customer{}).Where("phone = ?", customer.Phone).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return &api.ValidationError{Message: "a customer with this phone number already exists"}
+	}
+
+	return s.db.WithContext(ctx).Create(customer).Error
+}
+
+func (s *customerService) GetCustomer(ctx context.Context, id int) (*api.Customer, error) {
+	var customer api.Customer
+	if err := s.db.WithContext(ctx).First(&customer, id).Error; err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (s *customerService) UpdateCustomer(ctx context.Context, id int, customer *api.Customer) error {
+	customer.Phone = strings.TrimSpace(customer.Phone)
+	return s.db.WithContext(ctx).Model(&api.Customer{}).Where("id = ?", id).Updates(customer).Error
+}
+
+func (s *customerService) ListCustomers(ctx context.Context, params map[string]interface{}) ([]api.Customer, error) {
+	var customers []api.Customer
+	query := s.db.WithContext(ctx).Model(&api.Customer{})
+
+	if phone, ok := params["phone"].(string); ok && phone != "" {
+		query = query.Where("phone = ?", phone)
+	}
+	if search, ok := params["search"].(string); ok && search != "" {
+		query = query.Where("name ILIKE ?", "%"+search+"%")
+	}
+	if storeID, ok := params["store_id"].(string); ok && storeID != "" {
+		query = query.Where("store_id = ?", storeID)
+	}
+
+	page := 1
+	limit := 20
+	if p, ok := params["page"].(int); ok && p > 0 {
+		page = p
+	}
+	if l, ok := params["limit"].(int); ok && l > 0 {
+		limit = l
+	}
+
+	if err := query.Offset((page - 1) * limit).Limit(limit).Find(&customers).Error; err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
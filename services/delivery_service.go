@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+)
+
+type deliveryService struct {
+	db *gorm.DB
+}
+
+func NewDeliveryService(db *gorm.DB) api.DeliveryService {
+	return &deliveryService{db: db}
+}
+
+func (s *deliveryService) ListDeliveryZones(ctx context.Context, storeID string) ([]api.DeliveryZone, error) {
+	var zones []api.DeliveryZone
+	err := s.db.WithContext(ctx).Where("store_id = ? AND status <> ?", storeID, api.StatusArchived).
+		Order("priority DESC, id").
+		Find(&zones).Error
+	if err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+func (s *deliveryService) CreateDeliveryZone(ctx context.Context, zone *api.DeliveryZone) error {
+	if err := validateDeliveryZone(zone); err != nil {
+		return err
+	}
+	var store api.Store
+	if err := s.db.WithContext(ctx).Where("id = ?", zone.StoreID).First(&store).Error; err != nil {
+		return err
+	}
+	if zone.Status == "" {
+		zone.Status = api.StatusPublished
+	}
+	return s.db.WithContext(ctx).Create(zone).Error
+}
+
+func (s *deliveryService) UpdateDeliveryZone(ctx context.Context, storeID string, id int, zone *api.DeliveryZone) error {
+	if err := validateDeliveryZone(zone); err != nil {
+		return err
+	}
+	zone.ID = id
+	zone.StoreID = storeID
+	if zone.Status == "" {
+		zone.Status = api.StatusPublished
+	}
+	res := s.db.WithContext(ctx).Model(&api.DeliveryZone{}).
+		Where("store_id = ? AND id = ?", storeID, id).
+		Select("*").Omit("id", "store_id").
+		Updates(zone)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *deliveryService) DeleteDeliveryZone(ctx context.Context, storeID string, id int) error {
+	return s.db.WithContext(ctx).Model(&api.DeliveryZone{}).
+		Where("store_id = ? AND id = ?", storeID, id).
+		Update("status", api.StatusArchived).Error
+}
+
+func (s *deliveryService) CheckDelivery(ctx context.Context, storeID string, lat, lng float64, subtotal int) (*api.DeliveryQuote, error) {
+	if err := validateCoordinates(lat, lng); err != nil {
+		return nil, err
+	}
+	var store api.Store
+	err := s.db.WithContext(ctx).Where("id = ? AND status = ?", storeID, api.StatusPublished).First(&store).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, &api.ValidationError{Message: "store not found"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return loadDeliveryQuote(s.db.WithContext(ctx), &store, lat, lng, subtotal)
+}
+
+func validateDeliveryZone(zone *api.DeliveryZone) error {
+	if zone.Name == "" {
+		return &api.ValidationError{Message: "zone name is required"}
+	}
+	switch zone.Type {
+	case api.DeliveryZoneRadius:
+		if zone.MaxDistanceKm <= 0 {
+			return &api.ValidationError{Message: "radius zone requires a positive max_distance_km"}
+		}
+		zone.Polygon = nil
+	case api.DeliveryZonePolygon:
+		if len(zone.Polygon) < 3 {
+			return &api.ValidationError{Message: "polygon zone requires at least 3 points"}
+		}
+		for _, p := range zone.Polygon {
+			if err := validateCoordinates(p[0], p[1]); err != nil {
+				return err
+			}
+		}
+	default:
+		return &api.ValidationError{Message: fmt.Sprintf("invalid zone type %q", zone.Type)}
+	}
+	if zone.BaseFee < 0 || zone.PerKmFee < 0 || zone.FreeAbove < 0 || zone.MinOrder < 0 {
+		return &api.ValidationError{Message: "fees and minimum order cannot be negative"}
+	}
+	return nil
+}
+
+// loadDeliveryQuote memilih zona toko yang mencakup titik tujuan lalu menghitung ongkirnya.
+// subtotal 0 berarti keranjang belum diketahui sehingga minimum order tidak dicek.
+func loadDeliveryQuote(db *gorm.DB, store *api.Store, lat, lng float64, subtotal int) (*api.DeliveryQuote, error) {
+	if store.Latitude == nil || store.Longitude == nil {
+		return &api.DeliveryQuote{Reason: "store location is not set"}, nil
+	}
+
+	var zones []api.DeliveryZone
+	if err := db.Where("store_id = ? AND status = ?", store.ID, api.StatusPublished).Find(&zones).Error; err != nil {
+		return nil, err
+	}
+
+	distance := haversineKm(*store.Latitude, *store.Longitude, lat, lng)
+	return quoteDelivery(zones, distance, lat, lng, subtotal), nil
+}
+
+// quoteDelivery memilih zona dengan prioritas tertinggi; jika sama, polygon didahulukan
+// lalu radius terkecil, sehingga zona radius bisa dipakai sebagai tingkatan tarif
+func quoteDelivery(zones []api.DeliveryZone, distanceKm, lat, lng float64, subtotal int) *api.DeliveryQuote {
+	quote := &api.DeliveryQuote{DistanceKm: math.Round(distanceKm*100) / 100}
+
+	matching := make([]api.DeliveryZone, 0)
+	for _, z := range zones {
+		if zoneContains(z, distanceKm, lat, lng) {
+			matching = append(matching, z)
+		}
+	}
+	if len(matching) == 0 {
+		quote.Reason = "address is outside the delivery area"
+		return quote
+	}
+
+	sort.SliceStable(matching, func(i, j int) bool {
+		a, b := matching[i], matching[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Type != b.Type {
+			return a.Type == api.DeliveryZonePolygon
+		}
+		if a.MaxDistanceKm != b.MaxDistanceKm {
+			return a.MaxDistanceKm < b.MaxDistanceKm
+		}
+		return a.ID < b.ID
+	})
+	zone := matching[0]
+
+	quote.ZoneID = zone.ID
+	quote.ZoneName = zone.Name
+	quote.MinOrder = zone.MinOrder
+	quote.FreeAbove = zone.FreeAbove
+	quote.Fee = deliveryFee(zone, distanceKm, subtotal)
+
+	if subtotal > 0 && subtotal < zone.MinOrder {
+		quote.Reason = fmt.Sprintf("minimum order for %s is Rp%d", zone.Name, zone.MinOrder)
+		return quote
+	}
+	quote.Deliverable = true
+	return quote
+}
+
+func zoneContains(zone api.DeliveryZone, distanceKm, lat, lng float64) bool {
+	switch zone.Type {
+	case api.DeliveryZoneRadius:
+		return distanceKm <= zone.MaxDistanceKm
+	case api.DeliveryZonePolygon:
+		return pointInPolygon(lat, lng, zone.Polygon)
+	}
+	return false
+}
+
+func deliveryFee(zone api.DeliveryZone, distanceKm float64, subtotal int) int {
+	if zone.FreeAbove > 0 && subtotal >= zone.FreeAbove {
+		return 0
+	}
+	return zone.BaseFee + zone.PerKmFee*int(math.Ceil(distanceKm))
+}
+
+// pointInPolygon memakai ray casting; cukup akurat untuk area sekecil kota
+func pointInPolygon(lat, lng float64, polygon [][2]float64) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		yi, xi := polygon[i][0], polygon[i][1]
+		yj, xj := polygon[j][0], polygon[j][1]
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
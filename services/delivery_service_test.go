@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointInPolygon(t *testing.T) {
+	square := [][2]float64{{-6.1, 106.7}, {-6.1, 106.9}, {-6.3, 106.9}, {-6.3, 106.7}}
+
+	assert.True(t, pointInPolygon(-6.2, 106.8, square))
+	assert.False(t, pointInPolygon(-6.4, 106.8, square))
+	assert.False(t, pointInPolygon(-6.2, 107.0, square))
+}
+
+func TestQuoteDelivery(t *testing.T) {
+	zones := []api.DeliveryZone{
+		{ID: 1, Name: "0-3 km", Type: api.DeliveryZoneRadius, MaxDistanceKm: 3, BaseFee: 5000, MinOrder: 25000},
+		{ID: 2, Name: "3-7 km", Type: api.DeliveryZoneRadius, MaxDistanceKm: 7, BaseFee: 5000, PerKmFee: 2000, MinOrder: 50000, FreeAbove: 150000},
+		{ID: 3, Name: "Kompleks", Type: api.DeliveryZonePolygon, BaseFee: 3000,
+			Polygon: [][2]float64{{-6.19, 106.79}, {-6.19, 106.81}, {-6.21, 106.81}, {-6.21, 106.79}}},
+	}
+
+	tests := []struct {
+		name        string
+		distance    float64
+		lat, lng    float64
+		subtotal    int
+		deliverable bool
+		zoneID      int
+		fee         int
+		reason      string
+	}{
+		{name: "Inner tier", distance: 2.1, lat: -6.0, lng: 106.0, subtotal: 30000, deliverable: true, zoneID: 1, fee: 5000},
+		{name: "Outer tier charges per km", distance: 4.2, lat: -6.0, lng: 106.0, subtotal: 60000, deliverable: true, zoneID: 2, fee: 15000},
+		{name: "Free above threshold", distance: 4.2, lat: -6.0, lng: 106.0, subtotal: 150000, deliverable: true, zoneID: 2, fee: 0},
+		{name: "Polygon wins over radius", distance: 1, lat: -6.2, lng: 106.8, subtotal: 30000, deliverable: true, zoneID: 3, fee: 3000},
+		{name: "Below minimum order", distance: 5, lat: -6.0, lng: 106.0, subtotal: 40000, zoneID: 2, fee: 15000, reason: "minimum order for 3-7 km is Rp50000"},
+		{name: "Unknown subtotal skips minimum", distance: 5, lat: -6.0, lng: 106.0, deliverable: true, zoneID: 2, fee: 15000},
+		{name: "Outside every zone", distance: 9, lat: -6.0, lng: 106.0, subtotal: 60000, reason: "address is outside the delivery area"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := quoteDelivery(zones, tt.distance, tt.lat, tt.lng, tt.subtotal)
+
+			assert.Equal(t, tt.deliverable, quote.Deliverable)
+			assert.Equal(t, tt.zoneID, quote.ZoneID)
+			assert.Equal(t, tt.fee, quote.Fee)
+			assert.Equal(t, tt.reason, quote.Reason)
+		})
+	}
+}
+
+func TestValidateDeliveryZone(t *testing.T) {
+	assert.NoError(t, validateDeliveryZone(&api.DeliveryZone{Name: "Dekat", Type: api.DeliveryZoneRadius, MaxDistanceKm: 3}))
+	assert.Error(t, validateDeliveryZone(&api.DeliveryZone{Name: "Dekat", Type: api.DeliveryZoneRadius}))
+	assert.Error(t, validateDeliveryZone(&api.DeliveryZone{Name: "Area", Type: api.DeliveryZonePolygon, Polygon: [][2]float64{{-6.1, 106.7}, {-6.2, 106.8}}}))
+	assert.Error(t, validateDeliveryZone(&api.DeliveryZone{Name: "Mahal", Type: api.DeliveryZoneRadius, MaxDistanceKm: 3, BaseFee: -1}))
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/seleraseblak/backend/api"
+)
+
+const (
+	earthRadiusKm = 6371.0
+	// Panjang satu derajat lintang dalam km
+	kmPerDegree = 111.045
+	// Radius pencarian maksimum supaya bounding box tetap kecil
+	maxNearbyRadiusKm = 50.0
+)
+
+// haversineSQL menghitung jarak (km) dari kolom latitude/longitude ke titik (?, ?).
+// Parameter: lat, lat, lng.
+const haversineSQL = `(2 * 6371 * ASIN(SQRT(
+	POWER(SIN(RADIANS(latitude - ?) / 2), 2) +
+	COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2)
+)))`
+
+// haversineKm adalah versi Go dari haversineSQL
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLng := (lng2 - lng1) * math.Pi / 180
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+type boundingBox struct {
+	MinLat, MaxLat, MinLng, MaxLng float64
+}
+
+// boundingBoxAround adalah kotak yang pasti memuat lingkaran radiusKm di sekitar titik,
+// dipakai sebagai filter awal yang bisa memakai index sebelum menghitung haversine
+func boundingBoxAround(lat, lng, radiusKm float64) boundingBox {
+	dLat := radiusKm / kmPerDegree
+	dLng := radiusKm / (kmPerDegree * math.Cos(lat*math.Pi/180))
+	return boundingBox{
+		MinLat: lat - dLat,
+		MaxLat: lat + dLat,
+		MinLng: lng - dLng,
+		MaxLng: lng + dLng,
+	}
+}
+
+func validateCoordinates(lat, lng float64) error {
+	if lat < -90 || lat > 90 {
+		return &api.ValidationError{Message: fmt.Sprintf("latitude %v is out of range", lat)}
+	}
+	if lng < -180 || lng > 180 {
+		return &api.ValidationError{Message: fmt.Sprintf("longitude %v is out of range", lng)}
+	}
+	return nil
+}
+
+// validateStoreLocation memastikan latitude dan longitude diisi bersamaan
+func validateStoreLocation(store *api.Store) error {
+	if (store.Latitude == nil) != (store.Longitude == nil) {
+		return &api.ValidationError{Message: "latitude and longitude must be set together"}
+	}
+	if store.Latitude != nil {
+		return validateCoordinates(*store.Latitude, *store.Longitude)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHaversineKm(t *testing.T) {
+	// Monas ke Bundaran HI kurang lebih 2,2 km
+	d := haversineKm(-6.175392, 106.827153, -6.195165, 106.822994)
+	assert.InDelta(t, 2.24, d, 0.05)
+
+	assert.InDelta(t, 0, haversineKm(-6.2, 106.8, -6.2, 106.8), 1e-9)
+}
+
+func TestBoundingBoxAround(t *testing.T) {
+	lat, lng, radius := -6.2, 106.8, 5.0
+	box := boundingBoxAround(lat, lng, radius)
+
+	// Titik di tepi radius ke empat arah harus masih di dalam kotak
+	edges := [][2]float64{
+		{box.MinLat, lng}, {box.MaxLat, lng}, {lat, box.MinLng}, {lat, box.MaxLng},
+	}
+	for _, e := range edges {
+		assert.InDelta(t, radius, haversineKm(lat, lng, e[0], e[1]), 0.05)
+	}
+	assert.Less(t, box.MinLat, box.MaxLat)
+	assert.Less(t, box.MinLng, box.MaxLng)
+}
+
+func TestValidateStoreLocation(t *testing.T) {
+	lat, lng, bad := -6.2, 106.8, 200.0
+
+	assert.NoError(t, validateStoreLocation(&api.Store{}))
+	assert.NoError(t, validateStoreLocation(&api.Store{Latitude: &lat, Longitude: &lng}))
+	assert.Error(t, validateStoreLocation(&api.Store{Latitude: &lat}))
+	assert.Error(t, validateStoreLocation(&api.Store{Latitude: &lat, Longitude: &bad}))
+}
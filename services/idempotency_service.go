@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+type idempotencyService struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+func NewIdempotencyService(db *gorm.DB) api.IdempotencyService {
+	return &idempotencyService{db: db, now: time.Now}
+}
+
+// Reserve memakai satu INSERT ... ON CONFLICT sehingga dua request dengan key yang sama
+// yang datang bersamaan (juga di instance berbeda) tidak bisa sama-sama lolos. Key yang
+// sudah kedaluwarsa, atau masih diproses tapi lewat LockedUntil, diambil alih.
+func (s *idempotencyService) Reserve(ctx context.Context, key *api.IdempotencyKey) (*api.IdempotencyKey, error) {
+	db := s.db.WithContext(ctx)
+	// Dicoba dua kali: key lama bisa terhapus DeleteExpired di antara INSERT dan SELECT
+	for attempt := 0; attempt < 2; attempt++ {
+		now := s.now()
+		result := db.Clauses(reserveConflict(now)).Create(key)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil, nil
+		}
+
+		// Dibaca dari primary: replica bisa belum menerima baris yang baru saja bentrok
+		var existing api.IdempotencyKey
+		err := db.Clauses(dbresolver.Write).Where("key = ?", key.Key).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
+	return nil, errors.New("idempotency key changed during reservation")
+}
+
+// reserveConflict menimpa baris lama hanya jika sudah tidak berlaku
+func reserveConflict(now time.Time) clause.OnConflict {
+	return clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"request_hash":    clause.Column{Table: "excluded", Name: "request_hash"},
+			"response_status": 0,
+			"content_type":    "",
+			"response_body":   nil,
+			"locked_until":    clause.Column{Table: "excluded", Name: "locked_until"},
+			"expires_at":      clause.Column{Table: "excluded", Name: "expires_at"},
+			"created_at":      clause.Column{Table: "excluded", Name: "created_at"},
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Or(
+			clause.Expr{SQL: `"Idempotency_Key".expires_at <= ?`, Vars: []interface{}{now}},
+			clause.Expr{SQL: `"Idempotency_Key".response_status = 0 AND "Idempotency_Key".locked_until <= ?`, Vars: []interface{}{now}},
+		)}},
+	}
+}
+
+// Complete menyimpan response. Hanya baris yang masih diproses dengan request yang sama
+// yang diubah, jadi request yang sudah diambil alih tidak menimpa response penggantinya.
+func (s *idempotencyService) Complete(ctx context.Context, key *api.IdempotencyKey) error {
+	return s.db.WithContext(ctx).Model(&api.IdempotencyKey{}).
+		Where("key = ? AND request_hash = ? AND response_status = 0", key.Key, key.RequestHash).
+		Updates(map[string]interface{}{
+			"response_status": key.ResponseStatus,
+			"content_type":    key.ContentType,
+			"response_body":   key.ResponseBody,
+		}).Error
+}
+
+func (s *idempotencyService) Release(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ? AND response_status = 0", key).Delete(&api.IdempotencyKey{}).Error
+}
+
+// DeleteExpired membuang key yang sudah lewat masa berlakunya, dijalankan berkala oleh server
+func (s *idempotencyService) DeleteExpired(ctx context.Context) (int64, error) {
+	result := s.db.WithContext(ctx).Where("expires_at <= ?", s.now()).Delete(&api.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
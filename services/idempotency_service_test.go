@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+// dryRunDB membuat koneksi yang hanya menyusun SQL tanpa menjalankannya
+func dryRunDB(t *testing.T, dialector gorm.Dialector) *gorm.DB {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+// Baris lama hanya boleh ditimpa jika sudah kedaluwarsa atau request pertamanya terhenti
+func TestReserveOverwritesOnlyStaleKeys(t *testing.T) {
+	db := dryRunDB(t, postgres.Open("host=localhost"))
+
+	now := time.Now()
+	stmt := db.Clauses(reserveConflict(now)).Create(&api.IdempotencyKey{Key: "order-1", ExpiresAt: now, LockedUntil: now}).Statement
+	sql := stmt.SQL.String()
+	assert.Contains(t, sql, `ON CONFLICT ("key") DO UPDATE SET`)
+	assert.Contains(t, sql, `"request_hash"="excluded"."request_hash"`)
+	assert.Contains(t, sql, `WHERE ("Idempotency_Key".expires_at <= $12 OR ("Idempotency_Key".response_status = 0 AND "Idempotency_Key".locked_until <= $13))`)
+	assert.Equal(t, now, stmt.Vars[11])
+}
+
+// namedPool membedakan koneksi primary dan replica; tidak pernah dipakai karena DryRun
+type namedPool struct {
+	gorm.ConnPool
+	name string
+}
+
+// Key yang bentrok harus dibaca dari primary, replica bisa belum menerima barisnya
+func TestReserveReadsConflictFromPrimary(t *testing.T) {
+	primary, replica := &namedPool{name: "primary"}, &namedPool{name: "replica"}
+	db := dryRunDB(t, postgres.New(postgres.Config{Conn: primary}))
+	require.NoError(t, db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{postgres.New(postgres.Config{Conn: replica})},
+	})))
+
+	var pools []gorm.ConnPool
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:pool", func(tx *gorm.DB) {
+		pools = append(pools, tx.Statement.ConnPool)
+	}))
+
+	now := time.Now()
+	_, err := NewIdempotencyService(db).Reserve(context.Background(), &api.IdempotencyKey{Key: "order-1", ExpiresAt: now, LockedUntil: now})
+	require.NoError(t, err)
+	require.Len(t, pools, 1)
+	assert.Same(t, primary, pools[0])
+}
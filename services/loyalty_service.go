@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type loyaltyService struct {
+	db *gorm.DB
+}
+
+func NewLoyaltyService(db *gorm.DB) api.LoyaltyService {
+	return &loyaltyService{db: db}
+}
+
+func (s *loyaltyService) CreateProgram(ctx context.Context, program *api.LoyaltyProgram) error {
+	if err := validateLoyaltyProgram(program); err != nil {
+		return err
+	}
+	program.Status = api.StatusDraft
+	return s.db.WithContext(ctx).Create(program).Error
+}
+
+func (s *loyaltyService) GetProgram(ctx context.Context, id int) (*api.LoyaltyProgram, error) {
+	var program api.LoyaltyProgram
+	if err := s.db.WithContext(ctx).First(&program, id).Error; err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+func (s *loyaltyService) UpdateProgram(ctx context.Context, id int, program *api.LoyaltyProgram) error {
+	if program.Type != "" {
+		if err := validateLoyaltyProgram(program); err != nil {
+			return err
+		}
+	}
+	return s.db.WithContext(ctx).Model(&api.LoyaltyProgram{}).Where("id = ?", id).Updates(program).Error
+}
+
+func (s *loyaltyService) DeleteProgram(ctx context.Context, id int) error {
+	return s.db.WithContext(ctx).Model(&api.LoyaltyProgram{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
+}
+
+func (s *loyaltyService) ListPrograms(ctx context.Context, params map[string]interface{}) ([]api.LoyaltyProgram, error) {
+	var programs []api.LoyaltyProgram
+	query := s.db.WithContext(ctx).Model(&api.LoyaltyProgram{})
+
+	if status, ok := params["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if storeID, ok := params["store_id"].(string); ok && storeID != "" {
+		// Program tanpa store_id berlaku untuk semua toko
+		query = query.Where("store_id IS NULL OR store_id = ?", storeID)
+	}
+
+	if err := query.Find(&programs).Error; err != nil {
+		return nil, err
+	}
+	return programs, nil
+}
+
+func (s *loyaltyService) GetBalances(ctx context.Context, customerID int, storeID string) ([]api.LoyaltyBalance, error) {
+	now := time.Now()
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return expireLoyalty(tx, customerID, now)
+	}); err != nil {
+		return nil, err
+	}
+
+	var programs []api.LoyaltyProgram
+	query := s.db.WithContext(ctx).Where("status = ?", api.StatusPublished)
+	if storeID != "" {
+		query = query.Where("store_id IS NULL OR store_id = ?", storeID)
+	}
+	if err := query.Find(&programs).Error; err != nil {
+		return nil, err
+	}
+
+	balances := make([]api.LoyaltyBalance, 0, len(programs))
+	for _, program := range programs {
+		var lots []api.LoyaltyTransaction
+		err := s.db.WithContext(ctx).Where("customer_id = ? AND program_id = ? AND remaining > 0", customerID, program.ID).
+			Order("expires_at NULLS LAST, id").
+			Find(&lots).Error
+		if err != nil {
+			return nil, err
+		}
+
+		balance := api.LoyaltyBalance{
+			ProgramID:   program.ID,
+			ProgramName: program.Name,
+			Type:        program.Type,
+		}
+		for _, lot := range lots {
+			balance.Points += lot.Remaining
+			if lot.ExpiresAt == nil {
+				continue
+			}
+			if balance.NextExpiry == nil {
+				balance.NextExpiry = lot.ExpiresAt
+			}
+			if lot.ExpiresAt.Equal(*balance.NextExpiry) {
+				balance.ExpiringPoints += lot.Remaining
+			}
+		}
+		if program.Type == api.LoyaltyTypeStamps && program.StampsRequired > 0 {
+			balance.RewardsReady = balance.Points / program.StampsRequired
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, nil
+}
+
+func (s *loyaltyService) GetHistory(ctx context.Context, customerID int, params map[string]interface{}) ([]api.LoyaltyTransaction, error) {
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return expireLoyalty(tx, customerID, time.Now())
+	}); err != nil {
+		return nil, err
+	}
+
+	var history []api.LoyaltyTransaction
+	query := s.db.WithContext(ctx).Model(&api.LoyaltyTransaction{}).Where("customer_id = ?", customerID)
+
+	if programID, ok := params["program_id"].(int); ok && programID > 0 {
+		query = query.Where("program_id = ?", programID)
+	}
+
+	page := 1
+	limit := 20
+	if p, ok := params["page"].(int); ok && p > 0 {
+		page = p
+	}
+	if l, ok := params["limit"].(int); ok && l > 0 {
+		limit = l
+	}
+
+	err := query.Order("date_created DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func validateLoyaltyProgram(p *api.LoyaltyProgram) error {
+	switch p.Type {
+	case api.LoyaltyTypePoints:
+		if p.AmountPerPoint <= 0 {
+			return &api.ValidationError{Message: "points program requires amount_per_point"}
+		}
+		if p.PointValue <= 0 {
+			return &api.ValidationError{Message: "points program requires point_value"}
+		}
+	case api.LoyaltyTypeStamps:
+		if p.StampsRequired <= 0 {
+			return &api.ValidationError{Message: "stamps program requires stamps_required"}
+		}
+	default:
+		return &api.ValidationError{Message: fmt.Sprintf("invalid loyalty program type %q", p.Type)}
+	}
+	if p.ExpiryDays < 0 {
+		return &api.ValidationError{Message: "expiry_days cannot be negative"}
+	}
+	return nil
+}
+
+// loyaltyProgramsForStore mengambil program published yang berlaku di toko
+func loyaltyProgramsForStore(db *gorm.DB, storeID string) ([]api.LoyaltyProgram, error) {
+	var programs []api.LoyaltyProgram
+	err := db.Where("status = ? AND (store_id IS NULL OR store_id = ?)", api.StatusPublished, storeID).
+		Find(&programs).Error
+	return programs, err
+}
+
+// expireLoyalty menutup lot poin yang sudah lewat masa berlaku dan mencatatnya di riwayat
+func expireLoyalty(tx *gorm.DB, customerID int, now time.Time) error {
+	var lots []api.LoyaltyTransaction
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("customer_id = ? AND remaining > 0 AND expires_at <= ?", customerID, now).
+		Find(&lots).Error
+	if err != nil {
+		return err
+	}
+
+	for _, lot := range lots {
+		expired := api.LoyaltyTransaction{
+			CustomerID: customerID,
+			ProgramID:  lot.ProgramID,
+			Type:       api.LoyaltyTxExpire,
+			Points:     -lot.Remaining,
+		}
+		if err := tx.Create(&expired).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&api.LoyaltyTransaction{}).Where("id = ?", lot.ID).Update("remaining", 0).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// availableLoyalty adalah saldo yang masih bisa ditukar untuk satu program
+func availableLoyalty(db *gorm.DB, customerID, programID int, now time.Time) (int, error) {
+	var total int64
+	err := db.Model(&api.LoyaltyTransaction{}).
+		Select("COALESCE(SUM(remaining), 0)").
+		Where("customer_id = ? AND program_id = ? AND remaining > 0", customerID, programID).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Scan(&total).Error
+	return int(total), err
+}
+
+// quoteLoyalty menghitung potongan dari penukaran poin/stamp. payable adalah total
+// setelah promosi, potongan loyalty tidak akan melebihinya.
+func quoteLoyalty(db *gorm.DB, req *api.OrderRequest, items []api.OrderItem, payable int, now time.Time) ([]api.AppliedLoyalty, int, error) {
+	applied := make([]api.AppliedLoyalty, 0, len(req.RedeemLoyalty))
+	if len(req.RedeemLoyalty) == 0 {
+		return applied, 0, nil
+	}
+	if req.CustomerID == 0 {
+		return nil, 0, &api.ValidationError{Message: "customer_id is required to redeem loyalty"}
+	}
+
+	programs, err := loyaltyProgramsForStore(db, req.StoreID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	for _, redeem := range req.RedeemLoyalty {
+		program := findLoyaltyProgram(programs, redeem.ProgramID)
+		if program == nil {
+			return nil, 0, &api.ValidationError{Message: fmt.Sprintf("loyalty program %d is not available at this store", redeem.ProgramID)}
+		}
+		if redeem.Points <= 0 {
+			return nil, 0, &api.ValidationError{Message: "points to redeem must be positive"}
+		}
+
+		available, err := availableLoyalty(db, req.CustomerID, program.ID, now)
+		if err != nil {
+			return nil, 0, err
+		}
+		if available < redeem.Points {
+			return nil, 0, &api.ValidationError{Message: fmt.Sprintf("not enough balance in %s: %d available", program.Name, available)}
+		}
+
+		points, amount, err := redeemLoyalty(*program, items, redeem.Points, payable-total)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += amount
+		applied = append(applied, api.AppliedLoyalty{
+			ProgramID: program.ID,
+			Name:      program.Name,
+			Points:    points,
+			Amount:    amount,
+		})
+	}
+
+	return applied, total, nil
+}
+
+// redeemLoyalty menghitung potongan dari penukaran points poin/stamp satu program. Potongan
+// tidak melebihi remaining, dan poin yang dipakai hanya sebanyak potongan yang diberikan.
+func redeemLoyalty(program api.LoyaltyProgram, items []api.OrderItem, points, remaining int) (int, int, error) {
+	if remaining <= 0 {
+		return 0, 0, &api.ValidationError{Message: fmt.Sprintf("order has nothing left to pay with %s", program.Name)}
+	}
+
+	switch program.Type {
+	case api.LoyaltyTypePoints:
+		if points < program.MinRedeemPoints {
+			return 0, 0, &api.ValidationError{Message: fmt.Sprintf("at least %d points are required to redeem", program.MinRedeemPoints)}
+		}
+		amount := points * program.PointValue
+		if amount <= remaining {
+			return points, amount, nil
+		}
+		// Poin terakhir dibulatkan ke atas supaya sisa tagihan tertutup penuh
+		points = (remaining + program.PointValue - 1) / program.PointValue
+		if points < program.MinRedeemPoints {
+			return 0, 0, &api.ValidationError{Message: fmt.Sprintf("order total is too small to redeem at least %d points", program.MinRedeemPoints)}
+		}
+		return points, remaining, nil
+	case api.LoyaltyTypeStamps:
+		if points%program.StampsRequired != 0 {
+			return 0, 0, &api.ValidationError{Message: fmt.Sprintf("stamps must be redeemed in multiples of %d", program.StampsRequired)}
+		}
+		rewards, amount := stampRewards(program, items, points/program.StampsRequired, remaining)
+		if rewards == 0 {
+			return 0, 0, &api.ValidationError{Message: fmt.Sprintf("order has no item eligible for %s", program.Name)}
+		}
+		if amount > remaining {
+			amount = remaining
+		}
+		return rewards * program.StampsRequired, amount, nil
+	}
+	return points, 0, nil
+}
+
+// stampRewards menggratiskan item termurah yang berhak, satu unit per reward, sampai reward
+// habis atau potongan sudah mencapai limit. Mengembalikan jumlah reward yang terpakai dan potongannya.
+func stampRewards(program api.LoyaltyProgram, items []api.OrderItem, rewards, limit int) (int, int) {
+	var unitPrices []int
+	for _, item := range items {
+		if len(program.StampProductIDs) > 0 && !containsInt(program.StampProductIDs, item.ProductID) {
+			continue
+		}
+		for i := 0; i < item.Quantity; i++ {
+			unitPrices = append(unitPrices, item.UnitPrice)
+		}
+	}
+	sort.Ints(unitPrices)
+
+	used, amount := 0, 0
+	for _, price := range unitPrices {
+		if used == rewards || amount >= limit {
+			break
+		}
+		amount += price
+		used++
+	}
+	return used, amount
+}
+
+// consumeLoyalty mengurangi lot poin secara FIFO (yang paling cepat kadaluarsa lebih dulu)
+func consumeLoyalty(tx *gorm.DB, customerID, orderID int, applied []api.AppliedLoyalty, now time.Time) error {
+	for _, a := range applied {
+		var lots []api.LoyaltyTransaction
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("customer_id = ? AND program_id = ? AND remaining > 0", customerID, a.ProgramID).
+			Where("expires_at IS NULL OR expires_at > ?", now).
+			Order("expires_at NULLS LAST, id").
+			Find(&lots).Error
+		if err != nil {
+			return err
+		}
+
+		needed := a.Points
+		for _, lot := range lots {
+			if needed == 0 {
+				break
+			}
+			take := lot.Remaining
+			if take > needed {
+				take = needed
+			}
+			if err := tx.Model(&api.LoyaltyTransaction{}).Where("id = ?", lot.ID).
+				Update("remaining", lot.Remaining-take).Error; err != nil {
+				return err
+			}
+			needed -= take
+		}
+		if needed > 0 {
+			return &api.ValidationError{Message: fmt.Sprintf("not enough balance in %s", a.Name)}
+		}
+
+		redeemed := api.LoyaltyTransaction{
+			CustomerID: customerID,
+			ProgramID:  a.ProgramID,
+			OrderID:    &orderID,
+			Type:       api.LoyaltyTxRedeem,
+			Points:     -a.Points,
+		}
+		if err := tx.Create(&redeemed).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accrueLoyalty memberi poin/stamp untuk order yang sudah dibayar, sekali per order
+func accrueLoyalty(tx *gorm.DB, order *api.Order, now time.Time) error {
+	if order.CustomerID == nil {
+		return nil
+	}
+
+	var earned int64
+	err := tx.Model(&api.LoyaltyTransaction{}).
+		Where("order_id = ? AND type = ?", order.ID, api.LoyaltyTxEarn).
+		Count(&earned).Error
+	if err != nil || earned > 0 {
+		return err
+	}
+
+	programs, err := loyaltyProgramsForStore(tx, order.StoreID)
+	if err != nil {
+		return err
+	}
+
+	for _, program := range programs {
+		points := 0
+		switch program.Type {
+		case api.LoyaltyTypePoints:
+			points = order.Total / program.AmountPerPoint
+		case api.LoyaltyTypeStamps:
+			for _, item := range order.Items {
+				if len(program.StampProductIDs) == 0 || containsInt(program.StampProductIDs, item.ProductID) {
+					points += item.Quantity
+				}
+			}
+		}
+		if points <= 0 {
+			continue
+		}
+
+		earn := api.LoyaltyTransaction{
+			CustomerID: *order.CustomerID,
+			ProgramID:  program.ID,
+			OrderID:    &order.ID,
+			Type:       api.LoyaltyTxEarn,
+			Points:     points,
+			Remaining:  points,
+			ExpiresAt:  loyaltyExpiry(program, now),
+		}
+		if err := tx.Create(&earn).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertLoyalty membatalkan poin yang didapat dari order dan mengembalikan poin yang ditukar
+func revertLoyalty(tx *gorm.DB, order *api.Order, now time.Time) error {
+	if order.CustomerID == nil {
+		return nil
+	}
+
+	var txs []api.LoyaltyTransaction
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND type IN ?", order.ID, []string{api.LoyaltyTxEarn, api.LoyaltyTxRedeem}).
+		Find(&txs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, t := range txs {
+		switch t.Type {
+		case api.LoyaltyTxEarn:
+			// Hanya sisa yang belum ditukar yang bisa ditarik kembali
+			if t.Remaining > 0 {
+				reverted := api.LoyaltyTransaction{
+					CustomerID: t.CustomerID,
+					ProgramID:  t.ProgramID,
+					OrderID:    &order.ID,
+					Type:       api.LoyaltyTxRevert,
+					Points:     -t.Remaining,
+				}
+				if err := tx.Create(&reverted).Error; err != nil {
+					return err
+				}
+				if err := tx.Model(&api.LoyaltyTransaction{}).Where("id = ?", t.ID).Update("remaining", 0).Error; err != nil {
+					return err
+				}
+			}
+		case api.LoyaltyTxRedeem:
+			var program api.LoyaltyProgram
+			if err := tx.First(&program, t.ProgramID).Error; err != nil {
+				return err
+			}
+			refund := api.LoyaltyTransaction{
+				CustomerID: t.CustomerID,
+				ProgramID:  t.ProgramID,
+				OrderID:    &order.ID,
+				Type:       api.LoyaltyTxRefund,
+				Points:     -t.Points,
+				Remaining:  -t.Points,
+				ExpiresAt:  loyaltyExpiry(program, now),
+			}
+			if err := tx.Create(&refund).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func loyaltyExpiry(program api.LoyaltyProgram, now time.Time) *time.Time {
+	if program.ExpiryDays <= 0 {
+		return nil
+	}
+	expiresAt := now.AddDate(0, 0, program.ExpiryDays)
+	return &expiresAt
+}
+
+func findLoyaltyProgram(programs []api.LoyaltyProgram, id int) *api.LoyaltyProgram {
+	for i := range programs {
+		if programs[i].ID == id {
+			return &programs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStampRewards(t *testing.T) {
+	items := []api.OrderItem{
+		{ProductID: 1, Quantity: 2, UnitPrice: 20000},
+		{ProductID: 2, Quantity: 1, UnitPrice: 15000},
+		{ProductID: 3, Quantity: 1, UnitPrice: 5000},
+	}
+
+	tests := []struct {
+		name           string
+		program        api.LoyaltyProgram
+		rewards        int
+		limit          int
+		expectedUsed   int
+		expectedAmount int
+	}{
+		{name: "Cheapest item is free", program: api.LoyaltyProgram{}, rewards: 1, limit: 100000, expectedUsed: 1, expectedAmount: 5000},
+		{name: "Only eligible products", program: api.LoyaltyProgram{StampProductIDs: []int{1, 2}}, rewards: 2, limit: 100000, expectedUsed: 2, expectedAmount: 35000},
+		{name: "Rewards capped by items", program: api.LoyaltyProgram{StampProductIDs: []int{2}}, rewards: 3, limit: 100000, expectedUsed: 1, expectedAmount: 15000},
+		{name: "Rewards capped by limit", program: api.LoyaltyProgram{}, rewards: 3, limit: 10000, expectedUsed: 2, expectedAmount: 20000},
+		{name: "No eligible items", program: api.LoyaltyProgram{StampProductIDs: []int{9}}, rewards: 1, limit: 100000, expectedUsed: 0, expectedAmount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used, amount := stampRewards(tt.program, items, tt.rewards, tt.limit)
+			assert.Equal(t, tt.expectedUsed, used)
+			assert.Equal(t, tt.expectedAmount, amount)
+		})
+	}
+}
+
+func TestRedeemLoyalty(t *testing.T) {
+	items := []api.OrderItem{{ProductID: 1, Quantity: 3, UnitPrice: 20000}}
+	points := api.LoyaltyProgram{Name: "Poin", Type: api.LoyaltyTypePoints, PointValue: 100, MinRedeemPoints: 10}
+	stamps := api.LoyaltyProgram{Name: "Stamp", Type: api.LoyaltyTypeStamps, StampsRequired: 10}
+
+	tests := []struct {
+		name           string
+		program        api.LoyaltyProgram
+		points         int
+		remaining      int
+		expectedPoints int
+		expectedAmount int
+		expectedErr    bool
+	}{
+		{name: "Points fit the remaining total", program: points, points: 50, remaining: 10000, expectedPoints: 50, expectedAmount: 5000},
+		{name: "Points capped by the remaining total", program: points, points: 500, remaining: 4550, expectedPoints: 46, expectedAmount: 4550},
+		{name: "Capped points below the minimum", program: points, points: 500, remaining: 500, expectedErr: true},
+		{name: "Stamps fit the remaining total", program: stamps, points: 20, remaining: 60000, expectedPoints: 20, expectedAmount: 40000},
+		{name: "Stamps capped by the remaining total", program: stamps, points: 30, remaining: 25000, expectedPoints: 20, expectedAmount: 25000},
+		{name: "Stamps capped by the items", program: stamps, points: 50, remaining: 100000, expectedPoints: 30, expectedAmount: 60000},
+		{name: "Nothing left to pay", program: points, points: 50, remaining: 0, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used, amount, err := redeemLoyalty(tt.program, items, tt.points, tt.remaining)
+			if tt.expectedErr {
+				var validationErr *api.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPoints, used)
+			assert.Equal(t, tt.expectedAmount, amount)
+		})
+	}
+}
+
+func TestValidateLoyaltyProgram(t *testing.T) {
+	assert.NoError(t, validateLoyaltyProgram(&api.LoyaltyProgram{Type: api.LoyaltyTypeStamps, StampsRequired: 10}))
+	assert.NoError(t, validateLoyaltyProgram(&api.LoyaltyProgram{Type: api.LoyaltyTypePoints, AmountPerPoint: 1000, PointValue: 100}))
+	assert.Error(t, validateLoyaltyProgram(&api.LoyaltyProgram{Type: api.LoyaltyTypeStamps}))
+	assert.Error(t, validateLoyaltyProgram(&api.LoyaltyProgram{Type: "cashback"}))
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+)
+
+type menuService struct {
+	db                *gorm.DB
+	spicyLevelService api.SpicyLevelService
+}
+
+func NewMenuService(db *gorm.DB, spicyLevelService api.SpicyLevelService) api.MenuService {
+	return &menuService{db: db, spicyLevelService: spicyLevelService}
+}
+
+// GetStoreMenu memuat menu satu toko dengan jumlah query tetap, berapa pun banyaknya produk:
+// toko + jadwal, produk + semua preload, pilihan paket, lalu daftar kategori.
+func (s *menuService) GetStoreMenu(ctx context.Context, storeID string) (*api.Menu, error) {
+	var store api.Store
+	err := preloadSchedule(s.db.WithContext(ctx)).Where("id = ? AND status = ?", storeID, api.StatusPublished).First(&store).Error
+	if err != nil {
+		return nil, err
+	}
+	applyStoreSchedule(&store, time.Now())
+
+	var products []api.Product
+	err = preloadProductDetails(s.db.WithContext(ctx)).
+		Preload("ProductMaster.Categories", func(db *gorm.DB) *gorm.DB {
+			return db.Where(`"Category".status = ?`, api.StatusPublished)
+		}).
+		Where("store_id = ? AND status = ?", storeID, api.StatusPublished).
+		Order("id").
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	bundles := make([]*api.Product, 0)
+	for i := range products {
+		if err := products[i].AfterFind(); err != nil {
+			return nil, err
+		}
+		if products[i].Toppings == nil {
+			products[i].Toppings = []api.Topping{}
+		}
+		if products[i].Type == api.ProductTypeBundle {
+			bundles = append(bundles, &products[i])
+		}
+	}
+	if err := expandChoices(s.db.WithContext(ctx), bundles); err != nil {
+		return nil, err
+	}
+
+	var categories []api.Category
+	if err := s.db.WithContext(ctx).Where("status = ?", api.StatusPublished).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	levels, err := s.spicyLevelService.GetSpicyLevels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Menu{
+		Store:    store,
+		Sections: buildMenuSections(categories, products, levels),
+	}, nil
+}
+
+// buildMenuSections mengelompokkan produk per kategori mengikuti urutan pohon kategori.
+// Produk dengan beberapa kategori muncul di setiap bagiannya; produk tanpa kategori
+// yang tampil ada di bagian terakhir dengan Category nil.
+func buildMenuSections(categories []api.Category, products []api.Product, levels []api.SpicyLevel) []api.MenuSection {
+	var ordered []api.Category
+	var walk func(list []api.Category)
+	walk = func(list []api.Category) {
+		for _, c := range list {
+			children := c.Children
+			c.Children = nil
+			ordered = append(ordered, c)
+			walk(children)
+		}
+	}
+	walk(buildCategoryTree(categories))
+
+	index := make(map[int]int, len(ordered))
+	for i, c := range ordered {
+		index[c.ID] = i
+	}
+
+	grouped := make([][]api.MenuProduct, len(ordered))
+	var uncategorized []api.MenuProduct
+	for _, p := range products {
+		item := api.MenuProduct{Product: p, SpicyLevels: effectiveSpicyLevels(p, levels)}
+		placed := false
+		for _, c := range p.ProductMaster.Categories {
+			if i, ok := index[c.ID]; ok {
+				grouped[i] = append(grouped[i], item)
+				placed = true
+			}
+		}
+		if !placed {
+			uncategorized = append(uncategorized, item)
+		}
+	}
+
+	sections := make([]api.MenuSection, 0)
+	for i := range ordered {
+		if len(grouped[i]) == 0 {
+			continue
+		}
+		sortMenuProducts(grouped[i])
+		sections = append(sections, api.MenuSection{Category: &ordered[i], Products: grouped[i]})
+	}
+	if len(uncategorized) > 0 {
+		sortMenuProducts(uncategorized)
+		sections = append(sections, api.MenuSection{Products: uncategorized})
+	}
+	return sections
+}
+
+func sortMenuProducts(products []api.MenuProduct) {
+	sort.SliceStable(products, func(i, j int) bool {
+		a, b := products[i].ProductMaster.ProductName, products[j].ProductMaster.ProductName
+		if a != b {
+			return a < b
+		}
+		return products[i].ID < products[j].ID
+	})
+}
+
+// effectiveSpicyLevels mengikuti aturan pesanan: produk dengan grup modifier hanya menerima
+// level pedas yang terhubung ke pilihannya (dengan harga pilihan), produk tanpa grup
+// menerima semua level pedas umum
+func effectiveSpicyLevels(product api.Product, levels []api.SpicyLevel) []api.SpicyLevel {
+	if len(product.ModifierGroups) == 0 {
+		return append([]api.SpicyLevel{}, levels...)
+	}
+
+	byID := make(map[string]api.SpicyLevel, len(levels))
+	for _, l := range levels {
+		byID[l.ID] = l
+	}
+	result := make([]api.SpicyLevel, 0)
+	for _, g := range product.ModifierGroups {
+		for _, opt := range g.Options {
+			if opt.SpicyLevelID == nil || opt.Status != api.StatusPublished {
+				continue
+			}
+			level, ok := byID[*opt.SpicyLevelID]
+			if !ok {
+				continue
+			}
+			if opt.Name != "" {
+				level.Name = opt.Name
+			}
+			if opt.Price != nil {
+				level.Price = *opt.Price
+			}
+			result = append(result, level)
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func menuProduct(id int, name string, categoryIDs ...int) api.Product {
+	p := api.Product{ID: id, ProductMaster: api.ProductMaster{ProductName: name}}
+	for _, c := range categoryIDs {
+		p.ProductMaster.Categories = append(p.ProductMaster.Categories, api.Category{ID: c})
+	}
+	return p
+}
+
+func TestBuildMenuSections(t *testing.T) {
+	seblak := 1
+	categories := []api.Category{
+		{ID: 1, Name: "Seblak", DisplayOrder: 1},
+		{ID: 2, Name: "Seblak Kuah", ParentID: &seblak},
+		{ID: 3, Name: "Minuman", DisplayOrder: 2},
+		{ID: 4, Name: "Kosong", DisplayOrder: 3},
+	}
+	products := []api.Product{
+		menuProduct(10, "Es Teh", 3),
+		menuProduct(11, "Seblak Ceker", 2),
+		menuProduct(12, "Seblak Bakso", 2, 1),
+		menuProduct(13, "Kerupuk"),
+		menuProduct(14, "Seblak Lama", 99), // kategori draft/diarsipkan
+	}
+
+	sections := buildMenuSections(categories, products, nil)
+	require.Len(t, sections, 4)
+
+	assert.Equal(t, "Seblak", sections[0].Category.Name)
+	assert.Nil(t, sections[0].Category.Children)
+	assert.Equal(t, 12, sections[0].Products[0].ID)
+
+	assert.Equal(t, "Seblak Kuah", sections[1].Category.Name)
+	require.Len(t, sections[1].Products, 2)
+	assert.Equal(t, "Seblak Bakso", sections[1].Products[0].ProductMaster.ProductName)
+	assert.Equal(t, "Seblak Ceker", sections[1].Products[1].ProductMaster.ProductName)
+
+	assert.Equal(t, "Minuman", sections[2].Category.Name)
+
+	assert.Nil(t, sections[3].Category)
+	require.Len(t, sections[3].Products, 2)
+	assert.Equal(t, "Kerupuk", sections[3].Products[0].ProductMaster.ProductName)
+	assert.Equal(t, "Seblak Lama", sections[3].Products[1].ProductMaster.ProductName)
+
+	assert.NotNil(t, buildMenuSections(nil, nil, nil))
+}
+
+func TestEffectiveSpicyLevels(t *testing.T) {
+	levels, _ := NewSpicyLevelService().GetSpicyLevels(context.Background())
+
+	// Tanpa grup modifier: semua level umum
+	assert.Equal(t, levels, effectiveSpicyLevels(api.Product{}, levels))
+
+	one, three, unknown := "1", "3", "9"
+	product := api.Product{ModifierGroups: []api.ModifierGroup{
+		{Kind: api.ModifierKindSpicyLevel, Options: []api.ModifierOption{
+			{SpicyLevelID: &one, Name: "Tidak Pedas", Price: intPtr(0), Status: api.StatusPublished},
+			{SpicyLevelID: &three, Price: intPtr(5000), Status: api.StatusPublished},
+			{SpicyLevelID: &unknown, Status: api.StatusPublished},
+		}},
+		{Kind: api.ModifierKindTopping, Options: []api.ModifierOption{
+			{Name: "Ceker", Price: intPtr(3000), Status: api.StatusPublished},
+		}},
+	}}
+	got := effectiveSpicyLevels(product, levels)
+	require.Len(t, got, 2)
+	assert.Equal(t, api.SpicyLevel{ID: "1", Name: "Tidak Pedas", Level: 1, Price: 0}, got[0])
+	assert.Equal(t, api.SpicyLevel{ID: "3", Name: "Extra Pedas", Level: 3, Price: 5000}, got[1])
+
+	// Grup modifier tanpa level pedas: produk tidak bisa dipilih level pedasnya
+	noSpicy := api.Product{ModifierGroups: product.ModifierGroups[1:]}
+	assert.Empty(t, effectiveSpicyLevels(noSpicy, levels))
+	assert.NotNil(t, effectiveSpicyLevels(noSpicy, levels))
+}
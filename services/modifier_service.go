@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+)
+
+type modifierService struct {
+	db                *gorm.DB
+	spicyLevelService api.SpicyLevelService
+}
+
+func NewModifierService(db *gorm.DB, spicyLevelService api.SpicyLevelService) api.ModifierService {
+	return &modifierService{db: db, spicyLevelService: spicyLevelService}
+}
+
+func (s *modifierService) GetModifierGroups(ctx context.Context, productID int) ([]api.ModifierGroup, error) {
+	return loadModifierGroups(s.db.WithContext(ctx), productID)
+}
+
+// SetModifierGroups mengganti seluruh grup modifier produk. Pilihan yang terhubung ke
+// topping juga disinkronkan ke Product_Topping supaya /products/:id/toppings tetap sama.
+func (s *modifierService) SetModifierGroups(ctx context.Context, productID int, groups []api.ModifierGroup) ([]api.ModifierGroup, error) {
+	if err := s.validateGroups(groups); err != nil {
+		return nil, err
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var product api.Product
+		if err := tx.First(&product, productID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("group_id IN (?)", tx.Model(&api.ModifierGroup{}).Select("id").Where("product_id = ?", productID)).
+			Delete(&api.ModifierOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", productID).Delete(&api.ModifierGroup{}).Error; err != nil {
+			return err
+		}
+
+		toppingIDs := make([]int, 0)
+		for i := range groups {
+			groups[i].ID = 0
+			groups[i].ProductID = productID
+			for j := range groups[i].Options {
+				opt := &groups[i].Options[j]
+				opt.ID = 0
+				if opt.Status == "" {
+					opt.Status = api.StatusPublished
+				}
+				if opt.ToppingID != nil {
+					toppingIDs = append(toppingIDs, *opt.ToppingID)
+				}
+			}
+		}
+		if err := s.fillLinkedPrices(tx, groups); err != nil {
+			return err
+		}
+		if len(groups) > 0 {
+			if err := tx.Create(&groups).Error; err != nil {
+				return err
+			}
+		}
+
+		return syncProductToppings(tx, productID, uniqueInts(toppingIDs))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (s *modifierService) ValidateConfiguration(ctx context.Context, productID int, selections []api.ModifierSelection) (*api.ModifierConfiguration, error) {
+	groups, err := loadModifierGroups(s.db.WithContext(ctx), productID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := resolveModifiers(groups, selections)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &api.ModifierConfiguration{ProductID: productID, Selections: selected}
+	for _, m := range selected {
+		config.ModifierTotal += m.Price * m.Quantity
+	}
+	return config, nil
+}
+
+func (s *modifierService) validateGroups(groups []api.ModifierGroup) error {
+	for _, g := range groups {
+		if g.Name == "" {
+			return &api.ValidationError{Message: "modifier group name is required"}
+		}
+		switch g.Kind {
+		case api.ModifierKindBroth, api.ModifierKindNoodle, api.ModifierKindTopping, api.ModifierKindSpicyLevel, api.ModifierKindOther:
+		default:
+			return &api.ValidationError{Message: fmt.Sprintf("invalid modifier kind %q", g.Kind)}
+		}
+		if g.MinSelect < 0 || g.MaxSelect < 0 || (g.MaxSelect > 0 && g.MinSelect > g.MaxSelect) {
+			return &api.ValidationError{Message: fmt.Sprintf("invalid min/max selection for %s", g.Name)}
+		}
+		if len(g.Options) == 0 {
+			return &api.ValidationError{Message: fmt.Sprintf("%s requires at least one option", g.Name)}
+		}
+
+		defaults := 0
+		for _, opt := range g.Options {
+			if opt.Name == "" {
+				return &api.ValidationError{Message: fmt.Sprintf("option in %s requires a name", g.Name)}
+			}
+			if opt.Price != nil && *opt.Price < 0 {
+				return &api.ValidationError{Message: fmt.Sprintf("price of %s cannot be negative", opt.Name)}
+			}
+			if opt.IsDefault {
+				defaults++
+			}
+		}
+		if g.MaxSelect > 0 && defaults > g.MaxSelect {
+			return &api.ValidationError{Message: fmt.Sprintf("%s has more defaults than max_select", g.Name)}
+		}
+	}
+	return nil
+}
+
+// fillLinkedPrices memakai harga topping/level pedas jika harga pilihan tidak diisi.
+// Harga 0 yang dikirim eksplisit tetap gratis.
+func (s *modifierService) fillLinkedPrices(tx *gorm.DB, groups []api.ModifierGroup) error {
+	for i := range groups {
+		for j := range groups[i].Options {
+			opt := &groups[i].Options[j]
+			if opt.Price != nil {
+				continue
+			}
+			price := 0
+			if opt.ToppingID != nil {
+				var topping api.Topping
+				if err := tx.First(&topping, *opt.ToppingID).Error; err != nil {
+					return &api.ValidationError{Message: fmt.Sprintf("topping %d not found", *opt.ToppingID)}
+				}
+				price = topping.Price
+			}
+			if opt.SpicyLevelID != nil {
+				level, err := s.spicyLevelService.GetSpicyLevel(tx.Statement.Context, *opt.SpicyLevelID)
+				if err != nil {
+					return &api.ValidationError{Message: fmt.Sprintf("spicy level %s not found", *opt.SpicyLevelID)}
+				}
+				price = level.Price
+			}
+			opt.Price = &price
+		}
+	}
+	return nil
+}
+
+// syncProductToppings menyamakan Product_Topping dengan daftar topping dari grup modifier
+func syncProductToppings(tx *gorm.DB, productID int, toppingIDs []int) error {
+	query := tx.Where("Product_id = ?", productID)
+	if len(toppingIDs) > 0 {
+		query = query.Where("Topping_id NOT IN ?", toppingIDs)
+	}
+	if err := query.Delete(&api.ProductTopping{}).Error; err != nil {
+		return err
+	}
+
+	for _, toppingID := range toppingIDs {
+		var count int64
+		err := tx.Model(&api.ProductTopping{}).
+			Where("Product_id = ? AND Topping_id = ?", productID, toppingID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			link := api.ProductTopping{ProductID: productID, ToppingID: toppingID}
+			if err := tx.Omit("Product", "Topping").Create(&link).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func loadModifierGroups(db *gorm.DB, productID int) ([]api.ModifierGroup, error) {
+	var groups []api.ModifierGroup
+	err := db.Where("product_id = ?", productID).
+		Preload("Options", orderBySortOrder).
+		Order("sort_order, id").
+		Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// resolveModifiers memvalidasi pilihan terhadap aturan min/max setiap grup.
+// Grup yang tidak dipilih sama sekali memakai pilihan default-nya.
+func resolveModifiers(groups []api.ModifierGroup, selections []api.ModifierSelection) ([]api.SelectedModifier, error) {
+	type optionRef struct {
+		group  *api.ModifierGroup
+		option *api.ModifierOption
+	}
+	options := make(map[int]optionRef)
+	for i := range groups {
+		for j := range groups[i].Options {
+			opt := &groups[i].Options[j]
+			options[opt.ID] = optionRef{group: &groups[i], option: opt}
+		}
+	}
+
+	byGroup := make(map[int][]api.SelectedModifier)
+	for _, sel := range selections {
+		ref, ok := options[sel.OptionID]
+		if !ok {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("option %d is not available for this product", sel.OptionID)}
+		}
+		if ref.option.Status != api.StatusPublished {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("%s is not available", ref.option.Name)}
+		}
+		quantity := sel.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		if quantity < 0 {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("invalid quantity for %s", ref.option.Name)}
+		}
+		byGroup[ref.group.ID] = append(byGroup[ref.group.ID], selectedModifier(ref.group, ref.option, quantity))
+	}
+
+	result := make([]api.SelectedModifier, 0, len(selections))
+	for i := range groups {
+		g := &groups[i]
+		chosen := byGroup[g.ID]
+		if len(chosen) == 0 {
+			for j := range g.Options {
+				if g.Options[j].IsDefault && g.Options[j].Status == api.StatusPublished {
+					chosen = append(chosen, selectedModifier(g, &g.Options[j], 1))
+				}
+			}
+		}
+
+		count := 0
+		for _, m := range chosen {
+			count += m.Quantity
+		}
+		if count < g.MinSelect {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("choose at least %d for %s", g.MinSelect, g.Name)}
+		}
+		if g.MaxSelect > 0 && count > g.MaxSelect {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("choose at most %d for %s", g.MaxSelect, g.Name)}
+		}
+		result = append(result, chosen...)
+	}
+
+	return result, nil
+}
+
+func selectedModifier(g *api.ModifierGroup, opt *api.ModifierOption, quantity int) api.SelectedModifier {
+	return api.SelectedModifier{
+		GroupID:   g.ID,
+		GroupName: g.Name,
+		Kind:      g.Kind,
+		OptionID:  opt.ID,
+		Name:      opt.Name,
+		Price:     opt.UnitPrice(),
+		Quantity:  quantity,
+		IsDefault: opt.IsDefault,
+	}
+}
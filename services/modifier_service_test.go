@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+)
+
+func bowlGroups() []api.ModifierGroup {
+	return []api.ModifierGroup{
+		{
+			ID: 1, Name: "Kuah", Kind: api.ModifierKindBroth, MinSelect: 1, MaxSelect: 1,
+			Options: []api.ModifierOption{
+				{ID: 11, Name: "Kuah Original", IsDefault: true, Status: api.StatusPublished},
+				{ID: 12, Name: "Kuah Keju", Price: intPtr(3000), Status: api.StatusPublished},
+			},
+		},
+		{
+			ID: 2, Name: "Topping", Kind: api.ModifierKindTopping, MinSelect: 0, MaxSelect: 5,
+			Options: []api.ModifierOption{
+				{ID: 21, Name: "Ceker", Price: intPtr(4000), Status: api.StatusPublished},
+				{ID: 22, Name: "Kerupuk", Price: intPtr(2000), Status: api.StatusPublished},
+				{ID: 23, Name: "Sosis", Price: intPtr(3000), Status: api.StatusArchived},
+			},
+		},
+	}
+}
+
+func TestResolveModifiers(t *testing.T) {
+	tests := []struct {
+		name          string
+		selections    []api.ModifierSelection
+		expectedTotal int
+		expectedError string
+	}{
+		{
+			name:          "Default broth applied",
+			selections:    []api.ModifierSelection{{OptionID: 21}},
+			expectedTotal: 4000,
+		},
+		{
+			name:          "Chosen broth and toppings",
+			selections:    []api.ModifierSelection{{OptionID: 12}, {OptionID: 21, Quantity: 2}, {OptionID: 22}},
+			expectedTotal: 13000,
+		},
+		{
+			name:          "Two broths",
+			selections:    []api.ModifierSelection{{OptionID: 11}, {OptionID: 12}},
+			expectedError: "choose at most 1 for Kuah",
+		},
+		{
+			name:          "Too many toppings",
+			selections:    []api.ModifierSelection{{OptionID: 21, Quantity: 4}, {OptionID: 22, Quantity: 2}},
+			expectedError: "choose at most 5 for Topping",
+		},
+		{
+			name:          "Unavailable option",
+			selections:    []api.ModifierSelection{{OptionID: 23}},
+			expectedError: "Sosis is not available",
+		},
+		{
+			name:          "Unknown option",
+			selections:    []api.ModifierSelection{{OptionID: 99}},
+			expectedError: "option 99 is not available for this product",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := resolveModifiers(bowlGroups(), tt.selections)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+
+			total := 0
+			for _, m := range selected {
+				total += m.Price * m.Quantity
+			}
+			assert.Equal(t, tt.expectedTotal, total)
+		})
+	}
+}
+
+func TestResolveModifiersRequiresMinimum(t *testing.T) {
+	groups := bowlGroups()
+	groups[0].Options[0].IsDefault = false
+
+	_, err := resolveModifiers(groups, nil)
+	assert.EqualError(t, err, "choose at least 1 for Kuah")
+}
+
+// Harga kosong mengikuti level pedas, harga 0 yang dikirim eksplisit tetap gratis
+func TestFillLinkedPrices(t *testing.T) {
+	service := &modifierService{spicyLevelService: NewSpicyLevelService()}
+	tx := dryRunDB(t, postgres.Open("host=localhost")).WithContext(context.Background())
+
+	level := "3"
+	groups := []api.ModifierGroup{{Options: []api.ModifierOption{
+		{Name: "Extra Pedas", SpicyLevelID: &level},
+		{Name: "Extra Pedas Promo", SpicyLevelID: &level, Price: intPtr(0)},
+		{Name: "Tanpa Sayur"},
+	}}}
+	require.NoError(t, service.fillLinkedPrices(tx, groups))
+
+	options := groups[0].Options
+	assert.Equal(t, intPtr(4000), options[0].Price)
+	assert.Equal(t, intPtr(0), options[1].Price)
+	assert.Equal(t, intPtr(0), options[2].Price)
+}
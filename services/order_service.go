@@ -0,0 +1,781 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type orderService struct {
+	db                *gorm.DB
+	spicyLevelService api.SpicyLevelService
+}
+
+func NewOrderService(db *gorm.DB, spicyLevelService api.SpicyLevelService) api.OrderService {
+	return &orderService{db: db, spicyLevelService: spicyLevelService}
+}
+
+func (s *orderService) QuoteOrder(ctx context.Context, req *api.OrderRequest) (*api.OrderQuote, error) {
+	store, err := loadOrderStore(s.db.WithContext(ctx), req.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	return s.quote(s.db.WithContext(ctx), store, req, false)
+}
+
+func (s *orderService) CreateOrder(ctx context.Context, req *api.OrderRequest) (*api.Order, error) {
+	store, err := loadOrderStore(s.db.WithContext(ctx), req.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	if now := time.Now(); !storeOpenAt(store, now) {
+		msg := fmt.Sprintf("%s is closed", store.StoreName)
+		if next := storeNextOpenAt(store, now); next != nil {
+			msg += ", opens again at " + next.In(storeLocation(store.Timezone)).Format("Mon 02 Jan 15:04 MST")
+		}
+		return nil, &api.ValidationError{Message: msg}
+	}
+
+	var order *api.Order
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock promosi supaya batas pemakaian tidak terlewati oleh order yang bersamaan
+		quote, err := s.quote(tx, store, req, true)
+		if err != nil {
+			return err
+		}
+
+		if req.VoucherCode != "" && !voucherApplied(quote, req.VoucherCode) {
+			return &api.ValidationError{Message: voucherRejection(quote, req.VoucherCode)}
+		}
+
+		order = &api.Order{
+			StoreID:         req.StoreID,
+			UserID:          req.UserID,
+			OrderType:       req.OrderType,
+			Status:          api.OrderStatusPending,
+			VoucherCode:     normalizeVoucherCode(req.VoucherCode),
+			Subtotal:        quote.Subtotal,
+			Discount:        quote.Discount,
+			LoyaltyDiscount: quote.LoyaltyDiscount,
+			Total:           quote.Total,
+			Items:           quote.Items,
+			Promotions:      quote.Applied,
+			Loyalty:         quote.Loyalty,
+		}
+		if req.CustomerID > 0 {
+			order.CustomerID = &req.CustomerID
+		}
+		if quote.Delivery != nil {
+			order.DeliveryFee = quote.DeliveryFee
+			order.DeliveryZoneID = &quote.Delivery.ZoneID
+			order.DeliveryAddress = req.DeliveryAddress
+			order.DeliveryLatitude = req.DeliveryLatitude
+			order.DeliveryLongitude = req.DeliveryLongitude
+			order.DeliveryDistance = quote.Delivery.DistanceKm
+		}
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+
+		if err := consumeLoyalty(tx, req.CustomerID, order.ID, quote.Loyalty, time.Now()); err != nil {
+			return err
+		}
+
+		for _, m := range stockMovements(order.Items) {
+			res := tx.Model(&api.Product{}).
+				Where("id = ? AND stock_quantity >= ?", m.ProductID, m.Quantity).
+				Update("stock_quantity", gorm.Expr("stock_quantity - ?", m.Quantity))
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return &api.ValidationError{Message: fmt.Sprintf("%s is out of stock", stockItemName(order.Items, m.ProductID))}
+			}
+		}
+
+		return s.recordRedemptions(tx, order, quote.Applied)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *orderService) GetOrder(ctx context.Context, id int) (*api.Order, error) {
+	var order api.Order
+	err := s.db.WithContext(ctx).Preload("Items").Where("id = ?", id).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// orderTransitions adalah perubahan status order yang diperbolehkan
+var orderTransitions = map[string][]string{
+	api.OrderStatusPending: {api.OrderStatusPaid, api.OrderStatusCancelled},
+	api.OrderStatusPaid:    {api.OrderStatusCompleted, api.OrderStatusCancelled},
+}
+
+func (s *orderService) UpdateOrderStatus(ctx context.Context, id int, status string) (*api.Order, error) {
+	var order api.Order
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Items").
+			Where("id = ?", id).
+			First(&order).Error
+		if err != nil {
+			return err
+		}
+
+		allowed := false
+		for _, next := range orderTransitions[order.Status] {
+			if next == status {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return &api.ValidationError{Message: fmt.Sprintf("cannot change order status from %s to %s", order.Status, status)}
+		}
+
+		if err := tx.Model(&order).Update("status", status).Error; err != nil {
+			return err
+		}
+
+		switch status {
+		case api.OrderStatusPaid:
+			return accrueLoyalty(tx, &order, time.Now())
+		case api.OrderStatusCancelled:
+			return s.cancelOrder(tx, &order)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// cancelOrder mengembalikan stok, kuota promosi dan poin loyalty milik order
+func (s *orderService) cancelOrder(tx *gorm.DB, order *api.Order) error {
+	for _, m := range stockMovements(order.Items) {
+		err := tx.Model(&api.Product{}).Where("id = ?", m.ProductID).
+			Update("stock_quantity", gorm.Expr("stock_quantity + ?", m.Quantity)).Error
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("order_id = ?", order.ID).Delete(&api.PromotionRedemption{}).Error; err != nil {
+		return err
+	}
+	return revertLoyalty(tx, order, time.Now())
+}
+
+func (s *orderService) ListOrders(ctx context.Context, storeID string, params map[string]interface{}) ([]api.Order, error) {
+	var orders []api.Order
+	query := s.db.WithContext(ctx).Model(&api.Order{}).Where("store_id = ?", storeID).Preload("Items")
+
+	if status, ok := params["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	page := 1
+	limit := 20
+	if p, ok := params["page"].(int); ok && p > 0 {
+		page = p
+	}
+	if l, ok := params["limit"].(int); ok && l > 0 {
+		limit = l
+	}
+
+	err := query.Order("date_created DESC").Offset((page - 1) * limit).Limit(limit).Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// quote menghitung harga item dan mengevaluasi promosi. Jika lock bernilai true,
+// baris promosi dan voucher yang terpakai dikunci sampai transaksi selesai.
+func (s *orderService) quote(db *gorm.DB, store *api.Store, req *api.OrderRequest, lock bool) (*api.OrderQuote, error) {
+	if err := validateOrderRequest(req); err != nil {
+		return nil, err
+	}
+
+	if req.CustomerID > 0 {
+		var customer api.Customer
+		if err := db.First(&customer, req.CustomerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, &api.ValidationError{Message: "customer not found"}
+			}
+			return nil, err
+		}
+	}
+
+	items, err := s.priceItems(db, req)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, rejected, err := loadPromotionCandidates(db, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	local := now.In(storeLocation(store.Timezone))
+	cart := promotionCart{StoreID: req.StoreID, UserID: req.UserID, Items: items}
+	applied, notApplied, discount := evaluatePromotions(cart, candidates, local)
+	if lock {
+		// Pemakaian dihitung ulang setelah terkunci karena order lain bisa sudah tercatat.
+		// Jika hasilnya memakai promosi yang belum terkunci, promosi itu ikut dikunci.
+		locked := make(map[int]bool)
+		for {
+			more, err := lockAppliedPromotions(db, candidates, applied, locked)
+			if err != nil {
+				return nil, err
+			}
+			if !more {
+				break
+			}
+			if err := countPromotionUsage(db, candidates, req.UserID); err != nil {
+				return nil, err
+			}
+			applied, notApplied, discount = evaluatePromotions(cart, candidates, local)
+		}
+	}
+	subtotal := cart.subtotal()
+
+	loyalty, loyaltyDiscount, err := quoteLoyalty(db, req, items, subtotal-discount, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ongkir tidak ikut didiskon dan minimum order dihitung dari subtotal sebelum diskon
+	var delivery *api.DeliveryQuote
+	if req.OrderType == api.OrderTypeDelivery {
+		delivery, err = loadDeliveryQuote(db, store, *req.DeliveryLatitude, *req.DeliveryLongitude, subtotal)
+		if err != nil {
+			return nil, err
+		}
+		if !delivery.Deliverable {
+			return nil, &api.ValidationError{Message: "delivery not available: " + delivery.Reason}
+		}
+	}
+
+	quote := &api.OrderQuote{
+		Items:           items,
+		Subtotal:        subtotal,
+		Discount:        discount,
+		LoyaltyDiscount: loyaltyDiscount,
+		Total:           subtotal - discount - loyaltyDiscount,
+		Applied:         applied,
+		NotApplied:      append(rejected, notApplied...),
+		Loyalty:         loyalty,
+		Delivery:        delivery,
+	}
+	if delivery != nil {
+		quote.DeliveryFee = delivery.Fee
+		quote.Total += delivery.Fee
+	}
+	return quote, nil
+}
+
+// loadOrderStore mengambil toko beserta jadwalnya, hanya toko published yang menerima order
+func loadOrderStore(db *gorm.DB, storeID string) (*api.Store, error) {
+	if storeID == "" {
+		return nil, &api.ValidationError{Message: "store_id is required"}
+	}
+	var store api.Store
+	err := preloadSchedule(db).Where("id = ? AND status = ?", storeID, api.StatusPublished).First(&store).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, &api.ValidationError{Message: "store not found"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+
+func validateOrderRequest(req *api.OrderRequest) error {
+	if req.StoreID == "" {
+		return &api.ValidationError{Message: "store_id is required"}
+	}
+	if req.OrderType == "" {
+		req.OrderType = api.OrderTypePickup
+	}
+	switch req.OrderType {
+	case api.OrderTypeDineIn, api.OrderTypePickup, api.OrderTypeDelivery:
+	default:
+		return &api.ValidationError{Message: fmt.Sprintf("invalid order_type %q", req.OrderType)}
+	}
+	if req.OrderType == api.OrderTypeDelivery {
+		if req.DeliveryLatitude == nil || req.DeliveryLongitude == nil {
+			return &api.ValidationError{Message: "delivery_latitude and delivery_longitude are required for delivery orders"}
+		}
+		if err := validateCoordinates(*req.DeliveryLatitude, *req.DeliveryLongitude); err != nil {
+			return err
+		}
+	}
+	if len(req.Items) == 0 {
+		return &api.ValidationError{Message: "order must contain at least one item"}
+	}
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return &api.ValidationError{Message: fmt.Sprintf("invalid quantity for product %d", item.ProductID)}
+		}
+	}
+	return nil
+}
+
+// priceItems mengambil harga produk, topping dan level pedas dari database, bukan dari client
+func (s *orderService) priceItems(db *gorm.DB, req *api.OrderRequest) ([]api.OrderItem, error) {
+	productIDs := make([]int, 0, len(req.Items))
+	for _, item := range req.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	var products []api.Product
+	err := db.Where("id IN ? AND store_id = ? AND status = ?", productIDs, req.StoreID, api.StatusPublished).
+		Preload("ProductMaster").
+		Preload("ProductToppings.Topping", "status = ?", api.StatusPublished).
+		Preload("Components.Product.ProductMaster").
+		Preload("ModifierGroups", orderBySortOrder).
+		Preload("ModifierGroups.Options").
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	choices, err := loadBundleChoices(db, products)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*api.Product, len(products))
+	for i := range products {
+		if err := products[i].AfterFind(); err != nil {
+			return nil, err
+		}
+		byID[products[i].ID] = &products[i]
+	}
+
+	items := make([]api.OrderItem, 0, len(req.Items))
+	for _, reqItem := range req.Items {
+		product, ok := byID[reqItem.ProductID]
+		if !ok {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("product %d is not available at this store", reqItem.ProductID)}
+		}
+
+		item := api.OrderItem{
+			ProductID:    product.ID,
+			ProductName:  product.ProductMaster.ProductName,
+			Quantity:     reqItem.Quantity,
+			UnitPrice:    productUnitPrice(product),
+			SpicyLevelID: reqItem.SpicyLevelID,
+			Toppings:     make([]api.OrderItemTopping, 0, len(reqItem.ToppingIDs)),
+		}
+
+		if product.Type == api.ProductTypeBundle {
+			item.Components, err = bundleItemComponents(product, reqItem.BundleSelections, choices)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if len(product.ModifierGroups) > 0 {
+			if err := applyModifiers(&item, product, reqItem); err != nil {
+				return nil, err
+			}
+		} else if err := s.applyLegacyOptions(db.Statement.Context, &item, product, reqItem); err != nil {
+			return nil, err
+		}
+
+		unitTotal := item.UnitPrice + item.SpicyPrice
+		for _, topping := range item.Toppings {
+			unitTotal += topping.Price
+		}
+		for _, m := range item.Modifiers {
+			unitTotal += m.Price * m.Quantity
+		}
+		item.LineTotal = unitTotal * item.Quantity
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// applyLegacyOptions memakai daftar topping dan level pedas untuk produk tanpa grup modifier
+func (s *orderService) applyLegacyOptions(ctx context.Context, item *api.OrderItem, product *api.Product, reqItem api.OrderRequestItem) error {
+	if len(reqItem.Modifiers) > 0 {
+		return &api.ValidationError{Message: fmt.Sprintf("%s has no modifier groups", item.ProductName)}
+	}
+
+	if reqItem.SpicyLevelID != "" {
+		level, err := s.spicyLevelService.GetSpicyLevel(ctx, reqItem.SpicyLevelID)
+		if err != nil {
+			return &api.ValidationError{Message: fmt.Sprintf("invalid spicy level %q", reqItem.SpicyLevelID)}
+		}
+		item.SpicyPrice = level.Price
+	}
+
+	for _, toppingID := range reqItem.ToppingIDs {
+		topping := findTopping(product.Toppings, toppingID)
+		if topping == nil {
+			return &api.ValidationError{Message: fmt.Sprintf("topping %d is not available for %s", toppingID, item.ProductName)}
+		}
+		item.Toppings = append(item.Toppings, api.OrderItemTopping{
+			ToppingID: topping.ID,
+			Name:      topping.Name,
+			Price:     topping.Price,
+		})
+	}
+	return nil
+}
+
+// applyModifiers memvalidasi pilihan terhadap grup modifier produk. topping_ids dan
+// spicy_level_id lama tetap diterima dan dipetakan ke pilihan yang terhubung.
+// Pilihan topping dan level pedas disimpan di Toppings/SpicyPrice supaya promosi topping tetap jalan.
+func applyModifiers(item *api.OrderItem, product *api.Product, reqItem api.OrderRequestItem) error {
+	selections := append([]api.ModifierSelection{}, reqItem.Modifiers...)
+	for _, toppingID := range reqItem.ToppingIDs {
+		opt := findModifierOption(product.ModifierGroups, func(o api.ModifierOption) bool {
+			return o.ToppingID != nil && *o.ToppingID == toppingID
+		})
+		if opt == nil {
+			return &api.ValidationError{Message: fmt.Sprintf("topping %d is not available for %s", toppingID, item.ProductName)}
+		}
+		selections = append(selections, api.ModifierSelection{OptionID: opt.ID, Quantity: 1})
+	}
+	if reqItem.SpicyLevelID != "" {
+		opt := findModifierOption(product.ModifierGroups, func(o api.ModifierOption) bool {
+			return o.SpicyLevelID != nil && *o.SpicyLevelID == reqItem.SpicyLevelID
+		})
+		if opt == nil {
+			return &api.ValidationError{Message: fmt.Sprintf("invalid spicy level %q", reqItem.SpicyLevelID)}
+		}
+		selections = append(selections, api.ModifierSelection{OptionID: opt.ID, Quantity: 1})
+	}
+
+	selected, err := resolveModifiers(product.ModifierGroups, selections)
+	if err != nil {
+		return err
+	}
+
+	item.SpicyLevelID = ""
+	for _, m := range selected {
+		opt := findModifierOption(product.ModifierGroups, func(o api.ModifierOption) bool { return o.ID == m.OptionID })
+		switch {
+		case opt.ToppingID != nil:
+			for i := 0; i < m.Quantity; i++ {
+				item.Toppings = append(item.Toppings, api.OrderItemTopping{ToppingID: *opt.ToppingID, Name: m.Name, Price: m.Price})
+			}
+		case opt.SpicyLevelID != nil:
+			item.SpicyLevelID = *opt.SpicyLevelID
+			item.SpicyPrice = m.Price * m.Quantity
+		default:
+			item.Modifiers = append(item.Modifiers, m)
+		}
+	}
+	return nil
+}
+
+func findModifierOption(groups []api.ModifierGroup, match func(api.ModifierOption) bool) *api.ModifierOption {
+	for i := range groups {
+		for j := range groups[i].Options {
+			if match(groups[i].Options[j]) {
+				return &groups[i].Options[j]
+			}
+		}
+	}
+	return nil
+}
+
+// loadBundleChoices mengambil semua produk pilihan dari paket yang dipesan dalam satu query
+func loadBundleChoices(db *gorm.DB, products []api.Product) (map[int]*api.Product, error) {
+	ids := make([]int, 0)
+	for _, p := range products {
+		for _, c := range p.Components {
+			ids = append(ids, c.ChoiceProductIDs...)
+		}
+	}
+	choices := make(map[int]*api.Product, len(ids))
+	if len(ids) == 0 {
+		return choices, nil
+	}
+
+	var list []api.Product
+	err := db.Preload("ProductMaster").
+		Where("id IN ? AND status = ?", uniqueInts(ids), api.StatusPublished).
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		choices[list[i].ID] = &list[i]
+	}
+	return choices, nil
+}
+
+// bundleItemComponents menyusun isi satu unit paket dari slot tetap dan pilihan pelanggan
+func bundleItemComponents(bundle *api.Product, selections []api.BundleSelection, choices map[int]*api.Product) ([]api.OrderItemComponent, error) {
+	bundleName := bundle.ProductMaster.ProductName
+	components := make([]api.OrderItemComponent, 0, len(bundle.Components))
+
+	for _, c := range bundle.Components {
+		if c.ProductID != nil {
+			name := ""
+			if c.Product != nil {
+				name = c.Product.ProductMaster.ProductName
+			}
+			components = append(components, api.OrderItemComponent{ProductID: *c.ProductID, Name: name, Quantity: c.Quantity})
+			continue
+		}
+
+		picked := 0
+		for _, sel := range selections {
+			if sel.ComponentID != c.ID {
+				continue
+			}
+			choice, ok := choices[sel.ProductID]
+			if !ok || !containsInt(c.ChoiceProductIDs, sel.ProductID) {
+				return nil, &api.ValidationError{Message: fmt.Sprintf("product %d is not a valid choice for %s in %s", sel.ProductID, c.ChoiceName, bundleName)}
+			}
+			if sel.Quantity <= 0 {
+				return nil, &api.ValidationError{Message: "bundle selection quantity must be positive"}
+			}
+			picked += sel.Quantity
+			components = append(components, api.OrderItemComponent{
+				ProductID: sel.ProductID,
+				Name:      choice.ProductMaster.ProductName,
+				Quantity:  sel.Quantity,
+			})
+		}
+		if picked != c.Quantity {
+			return nil, &api.ValidationError{Message: fmt.Sprintf("choose %d for %s in %s", c.Quantity, c.ChoiceName, bundleName)}
+		}
+	}
+
+	return components, nil
+}
+
+type stockMovement struct {
+	ProductID int
+	Quantity  int
+}
+
+// stockMovements menghitung pengurangan stok per produk. Paket mengurangi stok komponennya.
+// Hasil diurutkan per produk supaya urutan lock baris selalu sama antar transaksi.
+func stockMovements(items []api.OrderItem) []stockMovement {
+	totals := make(map[int]int)
+	for _, item := range items {
+		if len(item.Components) == 0 {
+			totals[item.ProductID] += item.Quantity
+			continue
+		}
+		for _, c := range item.Components {
+			totals[c.ProductID] += c.Quantity * item.Quantity
+		}
+	}
+
+	movements := make([]stockMovement, 0, len(totals))
+	for productID, quantity := range totals {
+		movements = append(movements, stockMovement{ProductID: productID, Quantity: quantity})
+	}
+	sort.Slice(movements, func(i, j int) bool { return movements[i].ProductID < movements[j].ProductID })
+	return movements
+}
+
+func stockItemName(items []api.OrderItem, productID int) string {
+	for _, item := range items {
+		if item.ProductID == productID && len(item.Components) == 0 {
+			return item.ProductName
+		}
+		for _, c := range item.Components {
+			if c.ProductID == productID {
+				return c.Name
+			}
+		}
+	}
+	return fmt.Sprintf("product %d", productID)
+}
+
+// productUnitPrice memakai harga toko, atau harga master jika harga toko belum diisi
+func productUnitPrice(product *api.Product) int {
+	if product.Price > 0 {
+		return int(math.Round(product.Price))
+	}
+	return product.ProductMaster.Price
+}
+
+func findTopping(toppings []api.Topping, id int) *api.Topping {
+	for i := range toppings {
+		if toppings[i].ID == id {
+			return &toppings[i]
+		}
+	}
+	return nil
+}
+
+// loadPromotionCandidates mengambil promosi otomatis yang published ditambah promosi dari voucher.
+// Voucher yang tidak ditemukan langsung dikembalikan sebagai promosi yang ditolak.
+func loadPromotionCandidates(db *gorm.DB, req *api.OrderRequest) ([]promotionCandidate, []api.RejectedPromotion, error) {
+	rejected := make([]api.RejectedPromotion, 0)
+
+	var promotions []api.Promotion
+	if err := db.Where("status = ? AND requires_voucher = ?", api.StatusPublished, false).Find(&promotions).Error; err != nil {
+		return nil, nil, err
+	}
+
+	candidates := make([]promotionCandidate, 0, len(promotions)+1)
+	for _, p := range promotions {
+		candidates = append(candidates, promotionCandidate{Promotion: p})
+	}
+
+	if code := normalizeVoucherCode(req.VoucherCode); code != "" {
+		var voucher api.Voucher
+		err := db.Preload("Promotion").Where("code = ?", code).First(&voucher).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			rejected = append(rejected, api.RejectedPromotion{VoucherCode: code, Reason: "voucher code not found"})
+		case err != nil:
+			return nil, nil, err
+		default:
+			candidates = append(candidates, promotionCandidate{Promotion: voucher.Promotion, Voucher: &voucher})
+		}
+	}
+
+	if err := countPromotionUsage(db, candidates, req.UserID); err != nil {
+		return nil, nil, err
+	}
+	return candidates, rejected, nil
+}
+
+// countPromotionUsage mengisi jumlah pemakaian setiap kandidat dari Promotion_Redemption
+func countPromotionUsage(db *gorm.DB, candidates []promotionCandidate, userID string) error {
+	for i := range candidates {
+		usage, err := countRedemptions(db, "promotion_id = ?", candidates[i].Promotion.ID, userID)
+		if err != nil {
+			return err
+		}
+		candidates[i].Usage = usage
+
+		if v := candidates[i].Voucher; v != nil {
+			usage, err := countRedemptions(db, "voucher_id = ?", v.ID, userID)
+			if err != nil {
+				return err
+			}
+			candidates[i].VoucherUsage = usage
+		}
+	}
+	return nil
+}
+
+// lockAppliedPromotions mengunci promosi yang terpakai beserta vouchernya dengan SELECT ... FOR UPDATE,
+// berurutan menurut id supaya checkout yang bersamaan tidak saling deadlock. Promosi yang tidak
+// terpakai tidak dikunci sehingga checkout lain tetap berjalan. Mengembalikan true jika ada baris
+// yang baru dikunci.
+func lockAppliedPromotions(db *gorm.DB, candidates []promotionCandidate, applied []api.AppliedPromotion, locked map[int]bool) (bool, error) {
+	var promotionIDs, voucherIDs []int
+	for _, a := range applied {
+		if locked[a.PromotionID] {
+			continue
+		}
+		locked[a.PromotionID] = true
+		promotionIDs = append(promotionIDs, a.PromotionID)
+		for _, c := range candidates {
+			if a.VoucherCode != "" && c.Voucher != nil && c.Voucher.Code == a.VoucherCode {
+				voucherIDs = append(voucherIDs, c.Voucher.ID)
+			}
+		}
+	}
+	if len(promotionIDs) == 0 {
+		return false, nil
+	}
+
+	sort.Ints(promotionIDs)
+	var ids []int
+	if err := db.Model(&api.Promotion{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", promotionIDs).Order("id").Pluck("id", &ids).Error; err != nil {
+		return false, err
+	}
+	if len(voucherIDs) > 0 {
+		sort.Ints(voucherIDs)
+		if err := db.Model(&api.Voucher{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", voucherIDs).Order("id").Pluck("id", &ids).Error; err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func countRedemptions(db *gorm.DB, cond string, id int, userID string) (promotionUsage, error) {
+	var usage promotionUsage
+	var total int64
+	if err := db.Model(&api.PromotionRedemption{}).Where(cond, id).Count(&total).Error; err != nil {
+		return usage, err
+	}
+	usage.Total = int(total)
+
+	if userID != "" {
+		var byUser int64
+		if err := db.Model(&api.PromotionRedemption{}).Where(cond, id).Where("user_id = ?", userID).Count(&byUser).Error; err != nil {
+			return usage, err
+		}
+		usage.ByUser = int(byUser)
+	}
+	return usage, nil
+}
+
+func (s *orderService) recordRedemptions(tx *gorm.DB, order *api.Order, applied []api.AppliedPromotion) error {
+	for _, a := range applied {
+		redemption := api.PromotionRedemption{
+			PromotionID: a.PromotionID,
+			OrderID:     order.ID,
+			UserID:      order.UserID,
+			Amount:      a.Amount,
+		}
+		if a.VoucherCode != "" {
+			var voucher api.Voucher
+			if err := tx.Where("code = ?", a.VoucherCode).First(&voucher).Error; err != nil {
+				return err
+			}
+			redemption.VoucherID = &voucher.ID
+		}
+		if err := tx.Create(&redemption).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func voucherApplied(quote *api.OrderQuote, code string) bool {
+	code = normalizeVoucherCode(code)
+	for _, a := range quote.Applied {
+		if a.VoucherCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func voucherRejection(quote *api.OrderQuote, code string) string {
+	code = normalizeVoucherCode(code)
+	for _, r := range quote.NotApplied {
+		if r.VoucherCode == code {
+			return fmt.Sprintf("voucher %s cannot be used: %s", code, r.Reason)
+		}
+	}
+	return fmt.Sprintf("voucher %s cannot be used", code)
+}
+
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func intPtr(n int) *int { return &n }
+
+func paketBerdua() *api.Product {
+	return &api.Product{
+		ID:            10,
+		Type:          api.ProductTypeBundle,
+		ProductMaster: api.ProductMaster{ProductName: "Paket Berdua"},
+		Components: []api.BundleComponent{
+			{ID: 1, ProductID: intPtr(1), Product: &api.Product{ProductMaster: api.ProductMaster{ProductName: "Seblak"}}, Quantity: 2},
+			{ID: 2, ChoiceName: "Minuman", ChoiceProductIDs: []int{3, 4}, Quantity: 2},
+		},
+	}
+}
+
+func TestBundleItemComponents(t *testing.T) {
+	choices := map[int]*api.Product{
+		3: {ID: 3, ProductMaster: api.ProductMaster{ProductName: "Es Teh"}},
+		4: {ID: 4, ProductMaster: api.ProductMaster{ProductName: "Es Jeruk"}},
+		5: {ID: 5, ProductMaster: api.ProductMaster{ProductName: "Kopi"}},
+	}
+
+	tests := []struct {
+		name        string
+		selections  []api.BundleSelection
+		expected    []api.OrderItemComponent
+		expectError bool
+	}{
+		{
+			name:       "Mixed drinks",
+			selections: []api.BundleSelection{{ComponentID: 2, ProductID: 3, Quantity: 1}, {ComponentID: 2, ProductID: 4, Quantity: 1}},
+			expected: []api.OrderItemComponent{
+				{ProductID: 1, Name: "Seblak", Quantity: 2},
+				{ProductID: 3, Name: "Es Teh", Quantity: 1},
+				{ProductID: 4, Name: "Es Jeruk", Quantity: 1},
+			},
+		},
+		{
+			name:        "Too few drinks",
+			selections:  []api.BundleSelection{{ComponentID: 2, ProductID: 3, Quantity: 1}},
+			expectError: true,
+		},
+		{
+			name:        "Drink not in choice group",
+			selections:  []api.BundleSelection{{ComponentID: 2, ProductID: 5, Quantity: 2}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components, err := bundleItemComponents(paketBerdua(), tt.selections, choices)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, components)
+		})
+	}
+}
+
+func TestStockMovements(t *testing.T) {
+	items := []api.OrderItem{
+		{ProductID: 1, Quantity: 1},
+		{
+			ProductID: 10,
+			Quantity:  2,
+			Components: []api.OrderItemComponent{
+				{ProductID: 1, Quantity: 2},
+				{ProductID: 3, Quantity: 1},
+			},
+		},
+	}
+
+	assert.Equal(t, []stockMovement{
+		{ProductID: 1, Quantity: 5},
+		{ProductID: 3, Quantity: 2},
+	}, stockMovements(items))
+}
+
+// Hanya promosi yang terpakai, termasuk promosi dan baris voucher, yang dikunci berurutan menurut id
+func TestLockAppliedPromotions(t *testing.T) {
+	db := dryRunDB(t, postgres.Open("host=localhost"))
+	var statements []string
+	var vars [][]interface{}
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:sql", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+		vars = append(vars, tx.Statement.Vars)
+	}))
+
+	candidates := []promotionCandidate{
+		{Promotion: api.Promotion{ID: 7}},
+		{Promotion: api.Promotion{ID: 3}},
+		{Promotion: api.Promotion{ID: 5}, Voucher: &api.Voucher{ID: 11, Code: "HEMAT"}},
+	}
+	applied := []api.AppliedPromotion{{PromotionID: 7}, {PromotionID: 5, VoucherCode: "HEMAT"}}
+	locked := make(map[int]bool)
+
+	more, err := lockAppliedPromotions(db, candidates, applied, locked)
+	require.NoError(t, err)
+	assert.True(t, more)
+	require.Len(t, statements, 2)
+	assert.Equal(t, `SELECT "id" FROM "Promotion" WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`, statements[0])
+	assert.Equal(t, []interface{}{5, 7}, vars[0])
+	assert.Equal(t, `SELECT "id" FROM "Voucher" WHERE id IN ($1) ORDER BY id FOR UPDATE`, statements[1])
+	assert.Equal(t, []interface{}{11}, vars[1])
+
+	// Promosi yang sudah terkunci tidak dikunci lagi
+	more, err = lockAppliedPromotions(db, candidates, applied, locked)
+	require.NoError(t, err)
+	assert.False(t, more)
+	assert.Len(t, statements, 2)
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/logging"
+	"github.com/seleraseblak/backend/media"
+	"github.com/seleraseblak/backend/storage"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const (
+	// Batas ukuran file foto
+	MaxPhotoSize = 5 << 20
+	// Batas dimensi supaya file kecil yang didekode menjadi gambar raksasa ditolak
+	maxPhotoPixels = 40_000_000
+)
+
+// Tipe foto yang diterima, hasil sniffing isi file (bukan dari nama file/header client)
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+type photoService struct {
+	db      *gorm.DB
+	storage storage.Storage
+}
+
+func NewPhotoService(db *gorm.DB, store storage.Storage) api.PhotoService {
+	return &photoService{db: db, storage: store}
+}
+
+func (s *photoService) UploadProductPhoto(ctx context.Context, productID int, upload api.PhotoUpload) (*api.Product, error) {
+	var product api.Product
+	// Dibaca dari primary: foto lama yang dihapus harus yang terbaru, bukan salinan replica
+	if err := s.db.WithContext(ctx).Clauses(dbresolver.Write).First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+
+	photos, err := s.store(ctx, fmt.Sprintf("products/%d", productID), upload)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.WithContext(ctx).Model(&api.Product{}).Where("id = ?", productID).
+		Select("photo", "photos").
+		Updates(&api.Product{Photo: photos.Original.URL, Photos: photos}).Error
+	if err != nil {
+		s.remove(ctx, "", photos)
+		return nil, err
+	}
+
+	s.remove(ctx, product.Photo, product.Photos)
+	product.Photo = photos.Original.URL
+	product.Photos = photos
+	return &product, nil
+}
+
+func (s *photoService) UploadProductMasterPhoto(ctx context.Context, id string, upload api.PhotoUpload) (*api.ProductMaster, error) {
+	var master api.ProductMaster
+	if err := s.db.WithContext(ctx).Clauses(dbresolver.Write).Where("id = ?", id).First(&master).Error; err != nil {
+		return nil, err
+	}
+
+	photos, err := s.store(ctx, "product-masters/"+id, upload)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.WithContext(ctx).Model(&api.ProductMaster{}).Where("id = ?", id).
+		Select("photo", "photos").
+		Updates(&api.ProductMaster{Photo: photos.Original.URL, Photos: photos}).Error
+	if err != nil {
+		s.remove(ctx, "", photos)
+		return nil, err
+	}
+
+	s.remove(ctx, master.Photo, master.Photos)
+	master.Photo = photos.Original.URL
+	master.Photos = photos
+	return &master, nil
+}
+
+// store memvalidasi foto, membuat varian ukurannya lalu menyimpan semuanya di folder
+// acak di bawah prefix: original.<ext>, thumbnail.jpg, thumbnail.webp, dst.
+func (s *photoService) store(ctx context.Context, prefix string, upload api.PhotoUpload) (*api.ProductPhotos, error) {
+	data, contentType, err := readPhoto(upload)
+	if err != nil {
+		return nil, err
+	}
+	img, err := media.Decode(data)
+	if err != nil {
+		return nil, &api.ValidationError{Message: "photo is not a valid image"}
+	}
+	variants, err := media.Variants(img)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := prefix + "/" + randomName()
+	photos := &api.ProductPhotos{}
+	var stored []string
+	put := func(name, contentType string, data []byte) (string, error) {
+		key := dir + "/" + name
+		if err := s.storage.Put(ctx, key, contentType, data); err != nil {
+			return "", err
+		}
+		stored = append(stored, key)
+		return s.storage.URL(key), nil
+	}
+
+	photos.Original.Width, photos.Original.Height = img.Bounds().Dx(), img.Bounds().Dy()
+	if photos.Original.URL, err = put("original"+photoExtensions[contentType], contentType, data); err != nil {
+		return nil, err
+	}
+	for _, v := range variants {
+		variant := photoVariant(photos, v.Name)
+		variant.Width, variant.Height = v.Width, v.Height
+		if variant.URL, err = put(v.Name+".jpg", "image/jpeg", v.JPEG); err != nil {
+			break
+		}
+		if variant.WebP, err = put(v.Name+".webp", "image/webp", v.WebP); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		for _, key := range stored {
+			s.deleteKey(ctx, key)
+		}
+		return nil, err
+	}
+	return photos, nil
+}
+
+func photoVariant(photos *api.ProductPhotos, name string) *api.PhotoVariant {
+	switch name {
+	case "thumbnail":
+		return &photos.Thumbnail
+	case "medium":
+		return &photos.Medium
+	case "large":
+		return &photos.Large
+	}
+	panic("unknown photo variant " + name)
+}
+
+// remove menghapus foto lama beserta variannya jika tersimpan di storage kita.
+// Gagal hapus tidak membatalkan upload.
+func (s *photoService) remove(ctx context.Context, photo string, photos *api.ProductPhotos) {
+	urls := []string{photo}
+	if photos != nil {
+		for _, v := range []api.PhotoVariant{photos.Original, photos.Thumbnail, photos.Medium, photos.Large} {
+			urls = append(urls, v.URL, v.WebP)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		key, ok := storage.KeyFromURL(s.storage, u)
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		s.deleteKey(ctx, key)
+	}
+}
+
+// deleteKey tetap berjalan walaupun request sudah dibatalkan supaya tidak ada file yatim
+func (s *photoService) deleteKey(ctx context.Context, key string) {
+	if err := s.storage.Delete(context.WithoutCancel(ctx), key); err != nil {
+		logging.FromContext(ctx).Warn("failed to delete photo", "key", key, "error", err)
+	}
+}
+
+// readPhoto membaca foto dengan batas ukuran dan menentukan tipenya dari isi file
+func readPhoto(upload api.PhotoUpload) ([]byte, string, error) {
+	if upload.Size > MaxPhotoSize {
+		return nil, "", photoTooLarge()
+	}
+	data, err := io.ReadAll(io.LimitReader(upload.Content, MaxPhotoSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > MaxPhotoSize {
+		return nil, "", photoTooLarge()
+	}
+	if len(data) == 0 {
+		return nil, "", &api.ValidationError{Message: "photo is empty"}
+	}
+
+	contentType := http.DetectContentType(data)
+	if _, ok := photoExtensions[contentType]; !ok {
+		return nil, "", &api.ValidationError{Message: fmt.Sprintf("unsupported photo type %s, use JPEG, PNG or WebP", contentType)}
+	}
+
+	// Header dicek sebelum didekode penuh untuk membuat varian
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", &api.ValidationError{Message: "photo is not a valid image"}
+	}
+	if cfg.Width*cfg.Height > maxPhotoPixels {
+		return nil, "", &api.ValidationError{Message: "photo dimensions are too large"}
+	}
+	return data, contentType, nil
+}
+
+func photoTooLarge() error {
+	return &api.ValidationError{Message: fmt.Sprintf("photo must be at most %d MB", MaxPhotoSize>>20)}
+}
+
+func randomName() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// resetPhotos mengosongkan varian jika Photo diganti langsung dengan URL lain,
+// supaya photos tidak menunjuk ke foto lama. Photo yang sama (dikirim ulang apa adanya) dibiarkan.
+func resetPhotos(db *gorm.DB, model interface{}, id interface{}, photo string) error {
+	if photo == "" {
+		return nil
+	}
+	return db.Model(model).Where("id = ? AND photo IS DISTINCT FROM ?", id, photo).Update("photos", nil).Error
+}
+
+// validatePhotoURL memastikan Photo yang dikirim langsung berupa URL http(s) atau path
+func validatePhotoURL(photo string) error {
+	if photo == "" || strings.HasPrefix(photo, "/") {
+		return nil
+	}
+	u, err := url.Parse(photo)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &api.ValidationError{Message: "photo must be an http(s) URL, upload files via the photo endpoint"}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/seleraseblak/backend/media/webp"
+	"github.com/seleraseblak/backend/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPNG(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.RGBA{R: 200, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testWebP(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := webp.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadPhoto(t *testing.T) {
+	pngData := testPNG(t)
+	webpData := testWebP(t)
+	fakeWebP := append([]byte("RIFF\x24\x00\x00\x00WEBPVP8 "), make([]byte, 32)...)
+
+	tests := []struct {
+		name        string
+		upload      api.PhotoUpload
+		contentType string
+		err         string
+	}{
+		{name: "PNG", upload: api.PhotoUpload{Filename: "seblak.png", Size: int64(len(pngData)), Content: bytes.NewReader(pngData)}, contentType: "image/png"},
+		{name: "WebP", upload: api.PhotoUpload{Filename: "seblak.webp", Content: bytes.NewReader(webpData)}, contentType: "image/webp"},
+		{name: "WebP signature only", upload: api.PhotoUpload{Filename: "seblak.webp", Content: bytes.NewReader(fakeWebP)}, err: "photo is not a valid image"},
+		{name: "Renamed text file", upload: api.PhotoUpload{Filename: "seblak.jpg", Content: strings.NewReader("not really a photo")}, err: "unsupported photo type text/plain; charset=utf-8, use JPEG, PNG or WebP"},
+		{name: "Broken PNG", upload: api.PhotoUpload{Content: bytes.NewReader(pngData[:20])}, err: "photo is not a valid image"},
+		{name: "Declared size too large", upload: api.PhotoUpload{Size: MaxPhotoSize + 1, Content: bytes.NewReader(pngData)}, err: "photo must be at most 5 MB"},
+		{name: "Actual size too large", upload: api.PhotoUpload{Content: bytes.NewReader(make([]byte, MaxPhotoSize+10))}, err: "photo must be at most 5 MB"},
+		{name: "Empty", upload: api.PhotoUpload{Content: strings.NewReader("")}, err: "photo is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, contentType, err := readPhoto(tt.upload)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.contentType, contentType)
+		})
+	}
+}
+
+func TestValidatePhotoURL(t *testing.T) {
+	assert.NoError(t, validatePhotoURL(""))
+	assert.NoError(t, validatePhotoURL("/uploads/products/1/a.png"))
+	assert.NoError(t, validatePhotoURL("https://cdn.seleraseblak.com/a.png"))
+	assert.Error(t, validatePhotoURL("data:image/png;base64,AAAA"))
+	assert.Error(t, validatePhotoURL("seblak.png"))
+}
+
+func TestStorePhotoVariants(t *testing.T) {
+	dir := t.TempDir()
+	local, err := storage.NewLocal(dir, "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &photoService{storage: local}
+
+	img := image.NewRGBA(image.Rect(0, 0, 1600, 900))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	photos, err := s.store(context.Background(), "products/7", api.PhotoUpload{Content: bytes.NewReader(buf.Bytes())})
+	assert.NoError(t, err)
+
+	assert.Equal(t, [2]int{1600, 900}, [2]int{photos.Original.Width, photos.Original.Height})
+	assert.Equal(t, [2]int{200, 113}, [2]int{photos.Thumbnail.Width, photos.Thumbnail.Height})
+	assert.Equal(t, [2]int{600, 338}, [2]int{photos.Medium.Width, photos.Medium.Height})
+	assert.Equal(t, [2]int{1200, 675}, [2]int{photos.Large.Width, photos.Large.Height})
+	assert.True(t, strings.HasPrefix(photos.Original.URL, "/uploads/products/7/"))
+	assert.True(t, strings.HasSuffix(photos.Original.URL, "/original.png"))
+	assert.True(t, strings.HasSuffix(photos.Medium.URL, "/medium.jpg"))
+	assert.True(t, strings.HasSuffix(photos.Medium.WebP, "/medium.webp"))
+
+	files, _ := filepath.Glob(filepath.Join(dir, "products", "7", "*", "*"))
+	assert.Len(t, files, 7)
+
+	s.remove(context.Background(), photos.Original.URL, photos)
+	for _, f := range files {
+		_, err := os.Stat(f)
+		assert.True(t, os.IsNotExist(err), f)
+	}
+}
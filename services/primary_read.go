@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// primaryReadKey menandai context yang query bacanya harus ke primary
+type primaryReadKey struct{}
+
+// withPrimaryRead dipakai cache saat mengisi entri baru: replica bisa tertinggal dari
+// perubahan yang baru saja membatalkan cache, dan data lama itu akan tersimpan sampai TTL
+func withPrimaryRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadKey{}, true)
+}
+
+type primaryReadPlugin struct{}
+
+// PrimaryReadPlugin dipasang dengan db.Use(services.PrimaryReadPlugin()) setelah read replica
+// didaftarkan, supaya query baca dari context withPrimaryRead tidak diarahkan ke replica
+func PrimaryReadPlugin() gorm.Plugin {
+	return primaryReadPlugin{}
+}
+
+func (primaryReadPlugin) Name() string {
+	return "primary_read"
+}
+
+// Initialize memakai Before("*") karena dbresolver juga begitu; callback yang didaftarkan
+// belakangan berjalan lebih dulu sehingga sempat menandai statement sebelum replica dipilih.
+func (primaryReadPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Query().Before("*").Register("services:primary_read_query", readFromPrimary),
+		cb.Row().Before("*").Register("services:primary_read_row", readFromPrimary),
+		cb.Raw().Before("*").Register("services:primary_read_raw", readFromPrimary),
+	)
+}
+
+func readFromPrimary(db *gorm.DB) {
+	if ctx := db.Statement.Context; ctx != nil && ctx.Value(primaryReadKey{}) != nil {
+		dbresolver.Write.ModifyStatement(db.Statement)
+	}
+}
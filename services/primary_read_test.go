@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+func TestPrimaryReadPlugin(t *testing.T) {
+	primary, replica := &namedPool{name: "primary"}, &namedPool{name: "replica"}
+	db := dryRunDB(t, postgres.New(postgres.Config{Conn: primary}))
+	require.NoError(t, db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{postgres.New(postgres.Config{Conn: replica})},
+	})))
+	require.NoError(t, db.Use(PrimaryReadPlugin()))
+
+	var pools []gorm.ConnPool
+	record := func(tx *gorm.DB) { pools = append(pools, tx.Statement.ConnPool) }
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:pool", record))
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:pool", record))
+
+	ctx := context.Background()
+	var toppings []api.Topping
+	var count int64
+	db.WithContext(ctx).Find(&toppings)
+	db.WithContext(withPrimaryRead(ctx)).Find(&toppings)
+	db.WithContext(withPrimaryRead(ctx)).Model(&api.Topping{}).Count(&count)
+	db.WithContext(withPrimaryRead(ctx)).Model(&api.Topping{}).Select("id").Row()
+
+	require.Len(t, pools, 4)
+	assert.Same(t, replica, pools[0])
+	assert.Same(t, primary, pools[1])
+	assert.Same(t, primary, pools[2])
+	assert.Same(t, primary, pools[3])
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/seleraseblak/backend/api"
 	"gorm.io/gorm"
@@ -15,46 +17,106 @@ func NewProductMasterService(db *gorm.DB) api.ProductMasterService {
 	return &productMasterService{db: db}
 }
 
-func (s *productMasterService) CreateProductMaster(product *api.ProductMaster) error {
+func (s *productMasterService) CreateProductMaster(ctx context.Context, product *api.ProductMaster) error {
 	// Validasi kategori
 	if product.Category == nil {
 		product.Category = []string{} // Pastikan tidak nil
 	}
 
 	product.Status = api.StatusDraft
-	return s.db.Create(product).Error
+	if err := validatePhotoURL(product.Photo); err != nil {
+		return err
+	}
+	// Varian foto hanya dibuat lewat endpoint upload
+	product.Photos = nil
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Categories").Create(product).Error; err != nil {
+			return err
+		}
+		return setProductMasterCategories(tx, product)
+	})
 }
 
-func (s *productMasterService) GetProductMaster(id string) (*api.ProductMaster, error) {
+func (s *productMasterService) GetProductMaster(ctx context.Context, id string) (*api.ProductMaster, error) {
 	var product api.ProductMaster
-	err := s.db.Where("id = ?", id).First(&product).Error
+	err := preloadCategories(s.db.WithContext(ctx)).Where("id = ?", id).First(&product).Error
 	if err != nil {
 		return nil, err
 	}
 	return &product, nil
 }
 
-func (s *productMasterService) UpdateProductMaster(id string, product *api.ProductMaster) error {
-	return s.db.Model(&api.ProductMaster{}).Where("id = ?", id).Updates(product).Error
+func (s *productMasterService) UpdateProductMaster(ctx context.Context, id string, product *api.ProductMaster) error {
+	if err := validatePhotoURL(product.Photo); err != nil {
+		return err
+	}
+	product.Photos = nil
+	// Varian foto dikosongkan di transaksi yang sama supaya tetap ada jika update gagal
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := resetPhotos(tx, &api.ProductMaster{}, id, product.Photo); err != nil {
+			return err
+		}
+		err := tx.Model(&api.ProductMaster{}).Where("id = ?", id).Omit("Categories").Updates(product).Error
+		if err != nil {
+			return err
+		}
+		product.ID = id
+		return setProductMasterCategories(tx, product)
+	})
+}
+
+func (s *productMasterService) DeleteProductMaster(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Model(&api.ProductMaster{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
 }
 
-func (s *productMasterService) DeleteProductMaster(id string) error {
-	return s.db.Model(&api.ProductMaster{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
+func (s *productMasterService) ListProductMasters(ctx context.Context, params map[string]interface{}) ([]api.ProductMaster, error) {
+	search, _ := params["search"].(string)
+	terms := searchTerms(search)
+	if len(terms) == 0 {
+		return s.listProductMasters(s.db.WithContext(ctx), params, nil)
+	}
+
+	// Batas kemiripan trigram hanya berlaku di transaksi ini
+	var products []api.ProductMaster
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)",
+			fmt.Sprint(productSearchSimilarity)).Error
+		if err != nil {
+			return err
+		}
+		products, err = s.listProductMasters(tx, params, terms)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
 }
 
-func (s *productMasterService) ListProductMasters(params map[string]interface{}) ([]api.ProductMaster, error) {
+func (s *productMasterService) listProductMasters(db *gorm.DB, params map[string]interface{}, terms []string) ([]api.ProductMaster, error) {
 	var products []api.ProductMaster
-	query := s.db.Model(&api.ProductMaster{}).Where("status = ?", api.StatusPublished)
+	query := preloadCategories(db).Model(&api.ProductMaster{}).Where("status = ?", api.StatusPublished)
 
-	// Apply search filter
-	if search, ok := params["search"].(string); ok && search != "" {
-		query = query.Where("product_name ILIKE ?", "%"+search+"%")
+	// Full-text search dengan awalan kata, ditambah trigram untuk salah ketik di nama produk
+	if len(terms) > 0 {
+		tsquery := prefixTSQuery(terms)
+		phrase := strings.Join(terms, " ")
+		query = query.Select(`"Product_Master".*,
+			ts_rank_cd(search_vector, to_tsquery('indonesian', ?)) + word_similarity(?, product_name) AS search_rank,
+			ts_headline('indonesian', coalesce(product_name, ''), to_tsquery('indonesian', ?), ?) AS highlight_name,
+			ts_headline('indonesian', coalesce(description, ''), to_tsquery('indonesian', ?), ?) AS highlight_description`,
+			tsquery, phrase,
+			tsquery, highlightOptions+", HighlightAll=true",
+			tsquery, highlightOptions+", MaxWords=25, MinWords=8, MaxFragments=2").
+			Where("search_vector @@ to_tsquery('indonesian', ?) OR ? <% product_name", tsquery, phrase).
+			Order("search_rank DESC, product_name")
 	}
 
-	// Apply category filter jika ada
+	// Filter kategori berdasarkan slug (nama lama seperti "Seblak Kuah" juga diterima)
 	if category, ok := params["category"].(string); ok && category != "" {
-		// Gunakan operator @> untuk mencari dalam array JSON
-		query = query.Where("category @> ?", fmt.Sprintf("[\"%s\"]", category))
+		query = query.Where(`EXISTS (SELECT 1 FROM "Product_Master_Category" pmc JOIN "Category" c ON c.id = pmc.category_id
+			WHERE pmc.product_master_id = "Product_Master".id AND (c.slug = ? OR lower(c.name) = lower(?)))`,
+			slugify(category), category)
 	}
 
 	// Apply pagination
@@ -73,5 +135,9 @@ func (s *productMasterService) ListProductMasters(params map[string]interface{})
 		return nil, err
 	}
 
+	for i := range products {
+		products[i].HighlightName = renderHighlight(products[i].HighlightName)
+		products[i].HighlightDescription = renderHighlight(products[i].HighlightDescription)
+	}
 	return products, nil
 }
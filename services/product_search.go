@@ -0,0 +1,48 @@
+package services
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Kata yang mirip di atas batas ini dianggap cocok walau ada salah ketik
+const productSearchSimilarity = 0.4
+
+// searchTerms memecah kata kunci menjadi kata huruf kecil tanpa tanda baca,
+// sehingga aman dirangkai menjadi tsquery
+func searchTerms(search string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	// Batasi jumlah kata supaya query tetap murah
+	if len(fields) > 8 {
+		fields = fields[:8]
+	}
+	return fields
+}
+
+// prefixTSQuery menggabungkan semua kata dengan AND dan pencocokan awalan,
+// jadi "sebl cek" tetap menemukan "Seblak Ceker Pedas" saat user masih mengetik
+func prefixTSQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = t + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// Penanda highlight dari ts_headline; diganti <mark> setelah teksnya di-escape
+const (
+	highlightStart = "{{mark}}"
+	highlightStop  = "{{/mark}}"
+)
+
+const highlightOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+
+// renderHighlight meng-escape HTML dari data produk lalu memasang tag <mark>
+func renderHighlight(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, highlightStart, "<mark>")
+	return strings.ReplaceAll(s, highlightStop, "</mark>")
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"seblak", "ceker"}, searchTerms("  Seblak   CEKER "))
+	assert.Equal(t, []string{"sbl", "001"}, searchTerms("SBL-001"))
+	// Karakter tsquery seperti & | ! : ' tidak boleh lolos
+	assert.Equal(t, []string{"pedas", "level", "5"}, searchTerms("pedas & !level:5 '"))
+	assert.Empty(t, searchTerms("&& ||"))
+	assert.Len(t, searchTerms("a b c d e f g h i j"), 8)
+}
+
+func TestPrefixTSQuery(t *testing.T) {
+	assert.Equal(t, "seblak:* & ceker:*", prefixTSQuery([]string{"seblak", "ceker"}))
+	assert.Equal(t, "kerupuk:*", prefixTSQuery([]string{"kerupuk"}))
+}
+
+func TestRenderHighlight(t *testing.T) {
+	assert.Equal(t, "<mark>Seblak</mark> <mark>Ceker</mark> Pedas",
+		renderHighlight("{{mark}}Seblak{{/mark}} {{mark}}Ceker{{/mark}} Pedas"))
+	assert.Equal(t, "&lt;script&gt; <mark>seblak</mark> &amp; kerupuk",
+		renderHighlight("<script> {{mark}}seblak{{/mark}} & kerupuk"))
+	assert.Equal(t, "", renderHighlight(""))
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/seleraseblak/backend/api"
 	"gorm.io/gorm"
 )
@@ -13,54 +16,232 @@ func NewProductService(db *gorm.DB) api.ProductService {
 	return &productService{db: db}
 }
 
-func (s *productService) CreateProduct(product *api.Product) error {
+func (s *productService) CreateProduct(ctx context.Context, product *api.Product) error {
 	product.Status = api.StatusDraft
-	if product.Photo != "" {
-		// Tambahkan validasi format/ukuran photo jika diperlukan
+	if err := validatePhotoURL(product.Photo); err != nil {
+		return err
+	}
+	// Varian foto hanya dibuat lewat endpoint upload
+	product.Photos = nil
+	if product.Type == "" {
+		product.Type = api.ProductTypeSingle
 	}
-	return s.db.Create(product).Error
+	if err := s.validateComponents(ctx, product.StoreID, product); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Omit("Components.Product").Create(product).Error
 }
 
-func (s *productService) GetProduct(id int) (*api.Product, error) {
+func (s *productService) GetProduct(ctx context.Context, id int) (*api.Product, error) {
 	var product api.Product
-	err := s.db.Preload("ProductMaster").
-		Preload("ProductToppings.Topping", "status = ?", api.StatusPublished).
+	err := preloadProductDetails(s.db.WithContext(ctx)).
 		Where("id = ?", id).
 		First(&product).Error
 	if err != nil {
 		return nil, err
 	}
+	if err := expandChoices(s.db.WithContext(ctx), []*api.Product{&product}); err != nil {
+		return nil, err
+	}
 	return &product, nil
 }
 
-func (s *productService) UpdateProduct(id int, product *api.Product) error {
-	if product.Photo != "" {
-		// Tambahkan validasi format/ukuran photo jika diperlukan
+func (s *productService) UpdateProduct(ctx context.Context, id int, product *api.Product) error {
+	if err := validatePhotoURL(product.Photo); err != nil {
+		return err
 	}
-	return s.db.Model(&api.Product{}).Where("id = ?", id).Updates(product).Error
+	product.Photos = nil
+	if product.Type != "" || product.Components != nil {
+		var existing api.Product
+		if err := s.db.WithContext(ctx).Preload("Components").First(&existing, id).Error; err != nil {
+			return err
+		}
+		merged := mergeBundle(existing, *product)
+		if err := s.validateComponents(ctx, existing.StoreID, &merged); err != nil {
+			return err
+		}
+		// Produk yang bukan paket tidak boleh menyisakan baris komponen
+		if merged.Type != api.ProductTypeBundle && product.Components == nil {
+			product.Components = []api.BundleComponent{}
+		}
+	}
+
+	// Varian foto dikosongkan di transaksi yang sama supaya tetap ada jika update gagal
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := resetPhotos(tx, &api.Product{}, id, product.Photo); err != nil {
+			return err
+		}
+		if product.Components == nil {
+			return tx.Model(&api.Product{}).Where("id = ?", id).Updates(product).Error
+		}
+
+		// Komponen paket diganti seluruhnya
+		components := product.Components
+		product.Components = nil
+		if err := tx.Model(&api.Product{}).Where("id = ?", id).Updates(product).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("bundle_product_id = ?", id).Delete(&api.BundleComponent{}).Error; err != nil {
+			return err
+		}
+		for i := range components {
+			components[i].ID = 0
+			components[i].BundleProductID = id
+		}
+		if len(components) > 0 {
+			if err := tx.Omit("Product").Create(&components).Error; err != nil {
+				return err
+			}
+		}
+		product.Components = components
+		return nil
+	})
 }
 
-func (s *productService) DeleteProduct(id int) error {
-	return s.db.Model(&api.Product{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
+func (s *productService) DeleteProduct(ctx context.Context, id int) error {
+	return s.db.WithContext(ctx).Model(&api.Product{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
 }
 
-func (s *productService) ListProducts(storeID string, params map[string]interface{}) ([]api.Product, error) {
+func (s *productService) ListProducts(ctx context.Context, storeID string, params map[string]interface{}) ([]api.Product, error) {
 	var products []api.Product
-	query := s.db.Model(&api.Product{}).
+	query := preloadProductDetails(s.db.WithContext(ctx).Model(&api.Product{})).
 		Where("store_id = ? AND status = ?", storeID, api.StatusPublished).
-		Preload("ProductMaster").
-		Preload("ProductToppings.Topping", "status = ?", api.StatusPublished).
 		Limit(20)
 
 	if err := query.Find(&products).Error; err != nil {
 		return nil, err
 	}
 
+	bundles := make([]*api.Product, 0)
 	for i := range products {
 		if err := products[i].AfterFind(); err != nil {
 			return nil, err
 		}
+		if products[i].Type == api.ProductTypeBundle {
+			bundles = append(bundles, &products[i])
+		}
+	}
+	if err := expandChoices(s.db.WithContext(ctx), bundles); err != nil {
+		return nil, err
 	}
 
 	return products, nil
 }
+
+// mergeBundle mengisi tipe dan komponen yang tidak dikirim saat update dari produk yang ada,
+// supaya validasi paket memakai hasil akhirnya
+func mergeBundle(existing, update api.Product) api.Product {
+	if update.Type == "" {
+		update.Type = existing.Type
+	}
+	if update.Components == nil && update.Type == api.ProductTypeBundle {
+		update.Components = existing.Components
+	}
+	return update
+}
+
+// validateComponents memastikan paket hanya berisi produk biasa dari toko yang sama
+func (s *productService) validateComponents(ctx context.Context, storeID string, product *api.Product) error {
+	if product.Type != api.ProductTypeBundle {
+		if len(product.Components) > 0 {
+			return &api.ValidationError{Message: "only bundle products can have components"}
+		}
+		return nil
+	}
+	if len(product.Components) == 0 {
+		return &api.ValidationError{Message: "bundle product requires at least one component"}
+	}
+
+	ids := make([]int, 0)
+	for _, c := range product.Components {
+		if c.Quantity <= 0 {
+			return &api.ValidationError{Message: "component quantity must be positive"}
+		}
+		switch {
+		case c.ProductID != nil && len(c.ChoiceProductIDs) == 0:
+			ids = append(ids, *c.ProductID)
+		case c.ProductID == nil && len(c.ChoiceProductIDs) > 0:
+			if c.ChoiceName == "" {
+				return &api.ValidationError{Message: "choice component requires choice_name"}
+			}
+			ids = append(ids, c.ChoiceProductIDs...)
+		default:
+			return &api.ValidationError{Message: "component must set either product_id or choice_product_ids"}
+		}
+	}
+
+	var count int64
+	err := s.db.WithContext(ctx).Model(&api.Product{}).
+		Where("id IN ? AND store_id = ? AND COALESCE(type, ?) <> ?", uniqueInts(ids), storeID, api.ProductTypeSingle, api.ProductTypeBundle).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if int(count) != len(uniqueInts(ids)) {
+		return &api.ValidationError{Message: fmt.Sprintf("bundle components must be non-bundle products of store %s", storeID)}
+	}
+	return nil
+}
+
+// expandChoices mengisi Choices pada slot pilihan dengan satu query untuk semua paket
+func expandChoices(db *gorm.DB, products []*api.Product) error {
+	ids := make([]int, 0)
+	for _, p := range products {
+		for _, c := range p.Components {
+			ids = append(ids, c.ChoiceProductIDs...)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var choices []api.Product
+	err := db.Preload("ProductMaster").
+		Where("id IN ? AND status = ?", uniqueInts(ids), api.StatusPublished).
+		Find(&choices).Error
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]api.Product, len(choices))
+	for _, c := range choices {
+		byID[c.ID] = c
+	}
+
+	for _, p := range products {
+		for i := range p.Components {
+			c := &p.Components[i]
+			c.Choices = make([]api.Product, 0, len(c.ChoiceProductIDs))
+			for _, id := range c.ChoiceProductIDs {
+				if choice, ok := byID[id]; ok {
+					c.Choices = append(c.Choices, choice)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// preloadProductDetails memuat master, topping, komponen paket dan grup modifier produk
+func preloadProductDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("ProductMaster").
+		Preload("ProductToppings.Topping", "status = ?", api.StatusPublished).
+		Preload("Components.Product.ProductMaster").
+		Preload("ModifierGroups", orderBySortOrder).
+		Preload("ModifierGroups.Options", orderBySortOrder)
+}
+
+func orderBySortOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, id")
+}
+
+func uniqueInts(list []int) []int {
+	seen := make(map[int]bool, len(list))
+	out := make([]int, 0, len(list))
+	for _, n := range list {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+)
+
+func TestMergeBundle(t *testing.T) {
+	productID := 2
+	components := []api.BundleComponent{{ProductID: &productID, Quantity: 1}}
+	bundle := api.Product{Type: api.ProductTypeBundle, Components: components}
+
+	// Komponen yang tidak dikirim diambil dari paket yang ada
+	merged := mergeBundle(bundle, api.Product{Price: 25000})
+	assert.Equal(t, api.ProductTypeBundle, merged.Type)
+	assert.Equal(t, components, merged.Components)
+
+	// Paket yang diubah menjadi produk biasa tidak membawa komponen lamanya
+	merged = mergeBundle(bundle, api.Product{Type: api.ProductTypeSingle})
+	assert.Empty(t, merged.Components)
+
+	// Produk biasa yang diubah menjadi paket hanya memakai komponen yang dikirim
+	merged = mergeBundle(api.Product{Type: api.ProductTypeSingle}, api.Product{Type: api.ProductTypeBundle})
+	assert.Empty(t, merged.Components)
+}
+
+func TestUpdateProductRejectsBundleWithoutComponents(t *testing.T) {
+	service := NewProductService(dryRunDB(t, postgres.Open("host=localhost")))
+
+	err := service.UpdateProduct(context.Background(), 1, &api.Product{Type: api.ProductTypeBundle})
+	var validationErr *api.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
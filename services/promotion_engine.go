@@ -0,0 +1,313 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+)
+
+// promotionUsage adalah jumlah pemakaian yang sudah tercatat di Promotion_Redemption
+type promotionUsage struct {
+	Total  int
+	ByUser int
+}
+
+// promotionCandidate adalah promosi yang akan dievaluasi untuk satu order.
+// Voucher terisi jika promosi ini datang dari kode voucher.
+type promotionCandidate struct {
+	Promotion    api.Promotion
+	Voucher      *api.Voucher
+	Usage        promotionUsage
+	VoucherUsage promotionUsage
+}
+
+// promotionCart adalah keranjang yang sudah dihitung harganya
+type promotionCart struct {
+	StoreID string
+	UserID  string
+	Items   []api.OrderItem
+}
+
+func (c promotionCart) subtotal() int {
+	total := 0
+	for _, item := range c.Items {
+		total += item.LineTotal
+	}
+	return total
+}
+
+// evaluatePromotions menjalankan semua kandidat promosi terhadap keranjang dan
+// mengembalikan promosi yang terpakai, yang tidak terpakai beserta alasannya,
+// dan total diskon. now harus sudah dalam zona waktu toko.
+func evaluatePromotions(cart promotionCart, candidates []promotionCandidate, now time.Time) ([]api.AppliedPromotion, []api.RejectedPromotion, int) {
+	sorted := make([]promotionCandidate, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Promotion.Priority != sorted[j].Promotion.Priority {
+			return sorted[i].Promotion.Priority > sorted[j].Promotion.Priority
+		}
+		return sorted[i].Promotion.ID < sorted[j].Promotion.ID
+	})
+
+	applied := make([]api.AppliedPromotion, 0)
+	rejected := make([]api.RejectedPromotion, 0)
+	subtotal := cart.subtotal()
+	discount := 0
+	exclusiveApplied := ""
+
+	for _, c := range sorted {
+		p := c.Promotion
+		code := ""
+		if c.Voucher != nil {
+			code = c.Voucher.Code
+		}
+		reject := func(reason string) {
+			rejected = append(rejected, api.RejectedPromotion{
+				PromotionID: p.ID,
+				Name:        p.Name,
+				VoucherCode: code,
+				Reason:      reason,
+			})
+		}
+
+		if reason := checkPromotionEligibility(c, cart, subtotal, now); reason != "" {
+			reject(reason)
+			continue
+		}
+		if exclusiveApplied != "" {
+			reject(fmt.Sprintf("cannot be combined with %q", exclusiveApplied))
+			continue
+		}
+		if p.Exclusive && len(applied) > 0 {
+			reject(fmt.Sprintf("cannot be combined with %q", applied[0].Name))
+			continue
+		}
+
+		amount, reason := promotionDiscount(p, cart)
+		if amount <= 0 {
+			reject(reason)
+			continue
+		}
+		if p.MaxDiscount > 0 && amount > p.MaxDiscount {
+			amount = p.MaxDiscount
+		}
+		if remaining := subtotal - discount; amount > remaining {
+			amount = remaining
+		}
+		if amount <= 0 {
+			reject("order is already fully discounted")
+			continue
+		}
+
+		discount += amount
+		applied = append(applied, api.AppliedPromotion{
+			PromotionID: p.ID,
+			Name:        p.Name,
+			VoucherCode: code,
+			Amount:      amount,
+			Reason:      reason,
+		})
+		if p.Exclusive {
+			exclusiveApplied = p.Name
+		}
+	}
+
+	return applied, rejected, discount
+}
+
+// checkPromotionEligibility mengembalikan alasan penolakan, atau string kosong jika promosi boleh dipakai
+func checkPromotionEligibility(c promotionCandidate, cart promotionCart, subtotal int, now time.Time) string {
+	p := c.Promotion
+
+	if p.Status != api.StatusPublished {
+		return "promotion is not active"
+	}
+	if c.Voucher != nil && c.Voucher.Status != api.StatusPublished {
+		return "voucher is not active"
+	}
+	if len(p.StoreIDs) > 0 && !containsString(p.StoreIDs, cart.StoreID) {
+		return "not available at this store"
+	}
+	if reason := checkTimeRange(p.StartsAt, p.EndsAt, now); reason != "" {
+		return reason
+	}
+	if c.Voucher != nil {
+		if reason := checkTimeRange(c.Voucher.StartsAt, c.Voucher.EndsAt, now); reason != "" {
+			return "voucher " + reason
+		}
+	}
+	if len(p.DaysOfWeek) > 0 && !containsInt(p.DaysOfWeek, int(now.Weekday())) {
+		return fmt.Sprintf("only valid on %s", formatWeekdays(p.DaysOfWeek))
+	}
+	if p.DailyStart != "" && p.DailyEnd != "" && !withinDailyWindow(p.DailyStart, p.DailyEnd, now) {
+		return fmt.Sprintf("only valid between %s and %s", p.DailyStart, p.DailyEnd)
+	}
+	if p.MinSubtotal > 0 && subtotal < p.MinSubtotal {
+		return fmt.Sprintf("minimum order of Rp%d not reached", p.MinSubtotal)
+	}
+	if p.UsageLimit > 0 && c.Usage.Total >= p.UsageLimit {
+		return "usage limit reached"
+	}
+	if c.Voucher != nil && c.Voucher.UsageLimit > 0 && c.VoucherUsage.Total >= c.Voucher.UsageLimit {
+		return "voucher usage limit reached"
+	}
+	if p.UsageLimitPerUser > 0 || (c.Voucher != nil && c.Voucher.UsageLimitPerUser > 0) {
+		if cart.UserID == "" {
+			return "requires a signed-in customer"
+		}
+		if p.UsageLimitPerUser > 0 && c.Usage.ByUser >= p.UsageLimitPerUser {
+			return "per-customer usage limit reached"
+		}
+		if c.Voucher != nil && c.Voucher.UsageLimitPerUser > 0 && c.VoucherUsage.ByUser >= c.Voucher.UsageLimitPerUser {
+			return "per-customer voucher usage limit reached"
+		}
+	}
+	return ""
+}
+
+// promotionDiscount menghitung besar diskon sebelum dibatasi MaxDiscount.
+// Jika diskon nol, string kedua berisi alasan penolakan; selain itu berisi penjelasan diskon.
+func promotionDiscount(p api.Promotion, cart promotionCart) (int, string) {
+	switch p.Type {
+	case api.PromotionTypePercentage:
+		base := eligibleSubtotal(p, cart)
+		if base == 0 {
+			return 0, "no eligible items in order"
+		}
+		return base * p.Value / 100, fmt.Sprintf("%d%% off", p.Value)
+
+	case api.PromotionTypeFixedAmount:
+		base := eligibleSubtotal(p, cart)
+		if base == 0 {
+			return 0, "no eligible items in order"
+		}
+		amount := p.Value
+		if amount > base {
+			amount = base
+		}
+		return amount, fmt.Sprintf("Rp%d off", p.Value)
+
+	case api.PromotionTypeFreeTopping:
+		amount := 0
+		for _, item := range cart.Items {
+			if !promotionCoversProduct(p, item.ProductID) {
+				continue
+			}
+			for _, topping := range item.Toppings {
+				if topping.ToppingID == p.ToppingID {
+					amount += topping.Price * item.Quantity
+				}
+			}
+		}
+		if amount == 0 {
+			return 0, "order has no matching topping"
+		}
+		return amount, "free topping"
+
+	case api.PromotionTypeBundle:
+		group := p.BuyQuantity + p.GetQuantity
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return 0, "promotion is misconfigured"
+		}
+		var unitPrices []int
+		for _, item := range cart.Items {
+			if !promotionCoversProduct(p, item.ProductID) {
+				continue
+			}
+			for i := 0; i < item.Quantity; i++ {
+				unitPrices = append(unitPrices, item.UnitPrice)
+			}
+		}
+		free := len(unitPrices) / group * p.GetQuantity
+		if free == 0 {
+			return 0, fmt.Sprintf("add %d eligible items to qualify", group-len(unitPrices)%group)
+		}
+		// Item termurah yang digratiskan
+		sort.Ints(unitPrices)
+		amount := 0
+		for _, price := range unitPrices[:free] {
+			amount += price
+		}
+		return amount, fmt.Sprintf("buy %d get %d", p.BuyQuantity, p.GetQuantity)
+	}
+
+	return 0, fmt.Sprintf("unknown promotion type %q", p.Type)
+}
+
+func eligibleSubtotal(p api.Promotion, cart promotionCart) int {
+	total := 0
+	for _, item := range cart.Items {
+		if promotionCoversProduct(p, item.ProductID) {
+			total += item.LineTotal
+		}
+	}
+	return total
+}
+
+func promotionCoversProduct(p api.Promotion, productID int) bool {
+	return len(p.ProductIDs) == 0 || containsInt(p.ProductIDs, productID)
+}
+
+func checkTimeRange(startsAt, endsAt *time.Time, now time.Time) string {
+	if startsAt != nil && now.Before(*startsAt) {
+		return "has not started yet"
+	}
+	if endsAt != nil && !now.Before(*endsAt) {
+		return "has expired"
+	}
+	return ""
+}
+
+// withinDailyWindow mengecek jam "HH:MM", termasuk jendela yang melewati tengah malam
+func withinDailyWindow(start, end string, now time.Time) bool {
+	startMin, err1 := parseClock(start)
+	endMin, err2 := parseClock(end)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	cur := now.Hour()*60 + now.Minute()
+	if startMin <= endMin {
+		return cur >= startMin && cur < endMin
+	}
+	return cur >= startMin || cur < endMin
+}
+
+// parseClock mengubah "HH:MM" menjadi menit sejak tengah malam
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatWeekdays(days []int) string {
+	out := ""
+	for i, d := range days {
+		if i > 0 {
+			out += ", "
+		}
+		out += time.Weekday(d).String()
+	}
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCart() promotionCart {
+	return promotionCart{
+		StoreID: "store-123",
+		UserID:  "user-1",
+		Items: []api.OrderItem{
+			{
+				ProductID: 1,
+				Quantity:  2,
+				UnitPrice: 20000,
+				Toppings:  []api.OrderItemTopping{{ToppingID: 7, Name: "Kerupuk", Price: 3000}},
+				LineTotal: 46000,
+			},
+			{
+				ProductID: 2,
+				Quantity:  1,
+				UnitPrice: 10000,
+				LineTotal: 10000,
+			},
+		},
+	}
+}
+
+func published(p api.Promotion) promotionCandidate {
+	p.Status = api.StatusPublished
+	return promotionCandidate{Promotion: p}
+}
+
+func TestEvaluatePromotions(t *testing.T) {
+	// Selasa, 12:00 WIB
+	tuesday := time.Date(2024, 3, 5, 12, 0, 0, 0, storeLocation(""))
+
+	tests := []struct {
+		name             string
+		candidates       []promotionCandidate
+		expectedDiscount int
+		expectedApplied  []int
+		expectedRejected map[int]string
+	}{
+		{
+			name: "Percentage over minimum",
+			candidates: []promotionCandidate{
+				published(api.Promotion{ID: 1, Name: "10%", Type: api.PromotionTypePercentage, Value: 10, MinSubtotal: 50000}),
+			},
+			expectedDiscount: 5600,
+			expectedApplied:  []int{1},
+		},
+		{
+			name: "Percentage below minimum",
+			candidates: []promotionCandidate{
+				published(api.Promotion{ID: 1, Name: "10%", Type: api.PromotionTypePercentage, Value: 10, MinSubtotal: 100000}),
+			},
+			expectedDiscount: 0,
+			expectedRejected: map[int]string{1: "minimum order of Rp100000 not reached"},
+		},
+		{
+			name: "Free topping on Tuesday",
+			candidates: []promotionCandidate{
+				published(api.Promotion{ID: 2, Name: "Kerupuk Selasa", Type: api.PromotionTypeFreeTopping, ToppingID: 7, DaysOfWeek: []int{2}}),
+			},
+			expectedDiscount: 6000,
+			expectedApplied:  []int{2},
+		},
+		{
+			name: "Free topping on wrong day",
+			candidates: []promotionCandidate{
+				published(api.Promotion{ID: 2, Name: "Kerupuk Rabu", Type: api.PromotionTypeFreeTopping, ToppingID: 7, DaysOfWeek: []int{3}}),
+			},
+			expectedDiscount: 0,
+			expectedRejected: map[int]string{2: "only valid on Wednesday"},
+		},
+		{
+			name: "Buy 2 get 1 frees the cheapest unit",
+			candidates: []promotionCandidate{
+				published(api.Promotion{ID: 3, Name: "B2G1", Type: api.PromotionTypeBundle, BuyQuantity: 2, GetQuantity: 1}),
+			},
+			expectedDiscount: 10000,
+			expectedApplied:  []int{3},
+		},
+		{
+			name: "Store scoping and usage limits",
+			candidates: []promotionCandidate{
+				published(api.Promotion{ID: 4, Name: "Other store", Type: api.PromotionTypeFixedAmount, Value: 5000, StoreIDs: []string{"store-999"}}),
+				{
+					Promotion: api.Promotion{ID: 5, Name: "Sold out", Type: api.PromotionTypeFixedAmount, Value: 5000, UsageLimit: 10, Status: api.StatusPublished},
+					Usage:     promotionUsage{Total: 10},
+				},
+				{
+					Promotion: api.Promotion{ID: 6, Name: "Once per user", Type: api.PromotionTypeFixedAmount, Value: 5000, UsageLimitPerUser: 1, Status: api.StatusPublished},
+					Usage:     promotionUsage{Total: 3, ByUser: 1},
+				},
+			},
+			expectedDiscount: 0,
+			expectedRejected: map[int]string{
+				4: "not available at this store",
+				5: "usage limit reached",
+				6: "per-customer usage limit reached",
+			},
+		},
+		{
+			name: "Exclusive promotion blocks lower priority",
+			candidates: []promotionCandidate{
+				published(api.Promotion{ID: 7, Name: "Big deal", Type: api.PromotionTypeFixedAmount, Value: 15000, Exclusive: true, Priority: 10}),
+				published(api.Promotion{ID: 8, Name: "Small deal", Type: api.PromotionTypeFixedAmount, Value: 1000}),
+			},
+			expectedDiscount: 15000,
+			expectedApplied:  []int{7},
+			expectedRejected: map[int]string{8: `cannot be combined with "Big deal"`},
+		},
+		{
+			name: "Discount never exceeds subtotal",
+			candidates: []promotionCandidate{
+				published(api.Promotion{ID: 9, Name: "Huge", Type: api.PromotionTypeFixedAmount, Value: 100000}),
+			},
+			expectedDiscount: 56000,
+			expectedApplied:  []int{9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied, rejected, discount := evaluatePromotions(testCart(), tt.candidates, tuesday)
+
+			assert.Equal(t, tt.expectedDiscount, discount)
+
+			appliedIDs := make([]int, 0)
+			for _, a := range applied {
+				appliedIDs = append(appliedIDs, a.PromotionID)
+			}
+			if tt.expectedApplied == nil {
+				tt.expectedApplied = []int{}
+			}
+			assert.Equal(t, tt.expectedApplied, appliedIDs)
+
+			for _, r := range rejected {
+				assert.Equal(t, tt.expectedRejected[r.PromotionID], r.Reason)
+			}
+			assert.Len(t, rejected, len(tt.expectedRejected))
+		})
+	}
+}
+
+func TestWithinDailyWindow(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2024, 3, 5, h, m, 0, 0, time.UTC) }
+
+	assert.True(t, withinDailyWindow("10:00", "14:00", at(12, 0)))
+	assert.False(t, withinDailyWindow("10:00", "14:00", at(14, 0)))
+	assert.True(t, withinDailyWindow("22:00", "02:00", at(1, 30)))
+	assert.False(t, withinDailyWindow("22:00", "02:00", at(12, 0)))
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+)
+
+type promotionService struct {
+	db *gorm.DB
+}
+
+func NewPromotionService(db *gorm.DB) api.PromotionService {
+	return &promotionService{db: db}
+}
+
+func (s *promotionService) CreatePromotion(ctx context.Context, promotion *api.Promotion) error {
+	if err := validatePromotion(promotion); err != nil {
+		return err
+	}
+	promotion.Status = api.StatusDraft
+	return s.db.WithContext(ctx).Create(promotion).Error
+}
+
+func (s *promotionService) GetPromotion(ctx context.Context, id int) (*api.Promotion, error) {
+	var promotion api.Promotion
+	if err := s.db.WithContext(ctx).First(&promotion, id).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (s *promotionService) UpdatePromotion(ctx context.Context, id int, promotion *api.Promotion) error {
+	if promotion.Type != "" {
+		if err := validatePromotion(promotion); err != nil {
+			return err
+		}
+	}
+	return s.db.WithContext(ctx).Model(&api.Promotion{}).Where("id = ?", id).Updates(promotion).Error
+}
+
+func (s *promotionService) DeletePromotion(ctx context.Context, id int) error {
+	return s.db.WithContext(ctx).Model(&api.Promotion{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
+}
+
+func (s *promotionService) ListPromotions(ctx context.Context, params map[string]interface{}) ([]api.Promotion, error) {
+	var promotions []api.Promotion
+	query := s.db.WithContext(ctx).Model(&api.Promotion{})
+
+	if status, ok := params["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if storeID, ok := params["store_id"].(string); ok && storeID != "" {
+		storeIDs, err := json.Marshal([]string{storeID})
+		if err != nil {
+			return nil, err
+		}
+		// Promosi tanpa store_ids berlaku untuk semua toko
+		query = query.Where("store_ids IS NULL OR store_ids = '[]' OR store_ids @> ?", string(storeIDs))
+	}
+
+	if err := query.Order("priority DESC, id").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+func validatePromotion(p *api.Promotion) error {
+	switch p.Type {
+	case api.PromotionTypePercentage:
+		if p.Value <= 0 || p.Value > 100 {
+			return &api.ValidationError{Message: "percentage value must be between 1 and 100"}
+		}
+	case api.PromotionTypeFixedAmount:
+		if p.Value <= 0 {
+			return &api.ValidationError{Message: "fixed amount value must be positive"}
+		}
+	case api.PromotionTypeFreeTopping:
+		if p.ToppingID == 0 {
+			return &api.ValidationError{Message: "free_topping promotion requires topping_id"}
+		}
+	case api.PromotionTypeBundle:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return &api.ValidationError{Message: "bundle promotion requires buy_quantity and get_quantity"}
+		}
+	default:
+		return &api.ValidationError{Message: fmt.Sprintf("invalid promotion type %q", p.Type)}
+	}
+
+	for _, day := range p.DaysOfWeek {
+		if day < 0 || day > 6 {
+			return &api.ValidationError{Message: "days_of_week must be between 0 (Sunday) and 6 (Saturday)"}
+		}
+	}
+	if (p.DailyStart == "") != (p.DailyEnd == "") {
+		return &api.ValidationError{Message: "daily_start and daily_end must be set together"}
+	}
+	if p.DailyStart != "" {
+		if _, err := parseClock(p.DailyStart); err != nil {
+			return &api.ValidationError{Message: err.Error()}
+		}
+		if _, err := parseClock(p.DailyEnd); err != nil {
+			return &api.ValidationError{Message: err.Error()}
+		}
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return &api.ValidationError{Message: "ends_at must be after starts_at"}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ID tetap untuk data contoh, supaya seed bisa dijalankan berulang tanpa duplikat
+const (
+	seedStoreID        = "5e1e7a00-0000-4000-8000-000000000001"
+	seedSeblakID       = "5e1e7a00-0000-4000-8000-000000000101"
+	seedSeblakCekerID  = "5e1e7a00-0000-4000-8000-000000000102"
+	seedEsTehManisID   = "5e1e7a00-0000-4000-8000-000000000103"
+	seedStoreLatitude  = -6.9175
+	seedStoreLongitude = 107.6191
+)
+
+// SeedDemoData mengisi database kosong dengan satu toko published beserta katalog kecil
+// untuk pengembangan lokal. Baris yang sudah ada (berdasarkan ID) tidak diubah.
+func SeedDemoData(db *gorm.DB) error {
+	lat, lng := seedStoreLatitude, seedStoreLongitude
+	store := api.Store{
+		ID:           seedStoreID,
+		StoreName:    "Selera Seblak Bandung",
+		StoreAddress: "Jl. Braga No. 1, Bandung",
+		StorePhone:   "+62221234567",
+		Status:       api.StatusPublished,
+		Timezone:     "Asia/Jakarta",
+		Latitude:     &lat,
+		Longitude:    &lng,
+	}
+
+	hours := make([]api.StoreOpeningHour, 0, 7)
+	for day := 0; day < 7; day++ {
+		hours = append(hours, api.StoreOpeningHour{ID: day + 1, StoreID: seedStoreID, DayOfWeek: day, OpensAt: "10:00", ClosesAt: "22:00"})
+	}
+
+	seblak := 1
+	categories := []api.Category{
+		{ID: 1, Name: "Seblak", Slug: "seblak", DisplayOrder: 1, Status: api.StatusPublished},
+		{ID: 2, Name: "Seblak Kuah", Slug: "seblak-kuah", ParentID: &seblak, DisplayOrder: 1, Status: api.StatusPublished},
+		{ID: 3, Name: "Minuman", Slug: "minuman", DisplayOrder: 2, Status: api.StatusPublished},
+	}
+
+	now := time.Now()
+	toppings := []api.Topping{
+		{ID: 1, Name: "Ceker", Price: 5000, Status: api.StatusPublished, DateCreated: now},
+		{ID: 2, Name: "Bakso", Price: 4000, Status: api.StatusPublished, DateCreated: now},
+		{ID: 3, Name: "Sosis", Price: 3000, Status: api.StatusPublished, DateCreated: now},
+		{ID: 4, Name: "Kerupuk", Price: 2000, Status: api.StatusPublished, DateCreated: now},
+	}
+
+	masters := []api.ProductMaster{
+		{ID: seedSeblakID, ProductName: "Seblak Original", SKU: "SBL-001", Category: []string{"Seblak Kuah"},
+			Description: "Kerupuk basah dengan kuah kencur pedas", Price: 15000, Status: api.StatusPublished},
+		{ID: seedSeblakCekerID, ProductName: "Seblak Ceker", SKU: "SBL-002", Category: []string{"Seblak Kuah"},
+			Description: "Seblak kuah dengan ceker ayam empuk", Price: 20000, Status: api.StatusPublished},
+		{ID: seedEsTehManisID, ProductName: "Es Teh Manis", SKU: "MNM-001", Category: []string{"Minuman"},
+			Description: "Teh melati manis dingin", Price: 5000, Status: api.StatusPublished},
+	}
+	links := []api.ProductMasterCategory{
+		{ProductMasterID: seedSeblakID, CategoryID: 2},
+		{ProductMasterID: seedSeblakCekerID, CategoryID: 2},
+		{ProductMasterID: seedEsTehManisID, CategoryID: 3},
+	}
+
+	products := []api.Product{
+		{ID: 1, ProductMasterID: seedSeblakID, StoreID: seedStoreID, Price: 15000, StockQuantity: 100, IsActive: true, Status: api.StatusPublished, Type: api.ProductTypeSingle},
+		{ID: 2, ProductMasterID: seedSeblakCekerID, StoreID: seedStoreID, Price: 20000, StockQuantity: 100, IsActive: true, Status: api.StatusPublished, Type: api.ProductTypeSingle},
+		{ID: 3, ProductMasterID: seedEsTehManisID, StoreID: seedStoreID, Price: 5000, StockQuantity: 200, IsActive: true, Status: api.StatusPublished, Type: api.ProductTypeSingle},
+	}
+	productToppings := []api.ProductTopping{
+		{ID: 1, ProductID: 1, ToppingID: 1},
+		{ID: 2, ProductID: 1, ToppingID: 2},
+		{ID: 3, ProductID: 1, ToppingID: 3},
+		{ID: 4, ProductID: 1, ToppingID: 4},
+		{ID: 5, ProductID: 2, ToppingID: 2},
+		{ID: 6, ProductID: 2, ToppingID: 4},
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		rows := []interface{}{&store, &hours, &categories, &toppings, &masters, &links, &products, &productToppings}
+		for _, r := range rows {
+			// Asosiasi ditulis sendiri-sendiri di atas
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).Create(r).Error; err != nil {
+				return err
+			}
+		}
+		return syncSequences(tx, "Store_Opening_Hour", "Category", "Topping", "Product", "Product_Topping")
+	})
+}
+
+// syncSequences memajukan sequence id setelah baris diisi dengan ID eksplisit,
+// supaya insert berikutnya tidak bentrok
+func syncSequences(tx *gorm.DB, tables ...string) error {
+	for _, table := range tables {
+		err := tx.Exec(fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('"%[1]s"', 'id'), GREATEST((SELECT max(id) FROM "%[1]s"), 1))`, table)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
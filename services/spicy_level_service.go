@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/seleraseblak/backend/api"
@@ -22,11 +23,11 @@ func NewSpicyLevelService() api.SpicyLevelService {
 	return &spicyLevelService{spicyLevels: spicyLevels}
 }
 
-func (s *spicyLevelService) GetSpicyLevels() ([]api.SpicyLevel, error) {
+func (s *spicyLevelService) GetSpicyLevels(ctx context.Context) ([]api.SpicyLevel, error) {
 	return s.spicyLevels, nil
 }
 
-func (s *spicyLevelService) GetSpicyLevel(id string) (*api.SpicyLevel, error) {
+func (s *spicyLevelService) GetSpicyLevel(ctx context.Context, id string) (*api.SpicyLevel, error) {
 	for _, level := range s.spicyLevels {
 		if level.ID == id {
 			return &level, nil
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+)
+
+// Batas pencarian jam buka berikutnya
+const scheduleLookaheadDays = 14
+
+// Singkatan zona waktu Indonesia yang sering dipakai staf
+var timezoneAliases = map[string]string{
+	"WIB":  "Asia/Jakarta",
+	"WITA": "Asia/Makassar",
+	"WIT":  "Asia/Jayapura",
+}
+
+// normalizeTimezone mengubah WIB/WITA/WIT ke nama IANA dan memastikan zona waktu dikenal
+func normalizeTimezone(tz string) (string, error) {
+	tz = strings.TrimSpace(tz)
+	if tz == "" {
+		return "", nil
+	}
+	if alias, ok := timezoneAliases[strings.ToUpper(tz)]; ok {
+		tz = alias
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return "", &api.ValidationError{Message: fmt.Sprintf("unknown timezone %q", tz)}
+	}
+	return tz, nil
+}
+
+// storeLocation mengembalikan zona waktu toko, default WIB
+func storeLocation(timezone string) *time.Location {
+	if timezone != "" {
+		if loc, err := time.LoadLocation(timezone); err == nil {
+			return loc
+		}
+	}
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+type openInterval struct {
+	start time.Time
+	end   time.Time
+}
+
+// storeIntervals menghitung jam buka toko yang dimulai pada tanggal lokal day.
+// Toko tanpa jadwal mingguan dianggap buka 24 jam, kecuali ada penutupan.
+func storeIntervals(store *api.Store, day time.Time) []openInterval {
+	date := day.Format("2006-01-02")
+	for _, c := range store.Closures {
+		if c.Date != date {
+			continue
+		}
+		if c.OpensAt == "" || c.ClosesAt == "" {
+			return nil
+		}
+		return []openInterval{clockInterval(day, c.OpensAt, c.ClosesAt)}
+	}
+
+	if len(store.OpeningHours) == 0 {
+		return []openInterval{{start: day, end: day.AddDate(0, 0, 1)}}
+	}
+
+	intervals := make([]openInterval, 0)
+	for _, h := range store.OpeningHours {
+		if h.DayOfWeek == int(day.Weekday()) {
+			intervals = append(intervals, clockInterval(day, h.OpensAt, h.ClosesAt))
+		}
+	}
+	return intervals
+}
+
+func clockInterval(day time.Time, opens, closes string) openInterval {
+	openMin, _ := parseClock(opens)
+	closeMin, _ := parseClock(closes)
+	start := day.Add(time.Duration(openMin) * time.Minute)
+	end := day.Add(time.Duration(closeMin) * time.Minute)
+	if closeMin <= openMin {
+		end = end.AddDate(0, 0, 1)
+	}
+	return openInterval{start: start, end: end}
+}
+
+// localMidnight adalah awal hari lokal toko untuk waktu t
+func localMidnight(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// storeOpenAt mengecek apakah toko buka pada waktu t. Shift malam kemarin ikut dicek.
+func storeOpenAt(store *api.Store, t time.Time) bool {
+	loc := storeLocation(store.Timezone)
+	today := localMidnight(t, loc)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		for _, in := range storeIntervals(store, day) {
+			if !t.Before(in.start) && t.Before(in.end) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// storeNextOpenAt mencari waktu buka berikutnya setelah t, nil jika tidak ada dalam 14 hari
+func storeNextOpenAt(store *api.Store, t time.Time) *time.Time {
+	loc := storeLocation(store.Timezone)
+	today := localMidnight(t, loc)
+	for i := 0; i <= scheduleLookaheadDays; i++ {
+		var next *time.Time
+		for _, in := range storeIntervals(store, today.AddDate(0, 0, i)) {
+			if in.start.After(t) && (next == nil || in.start.Before(*next)) {
+				start := in.start
+				next = &start
+			}
+		}
+		if next != nil {
+			return next
+		}
+	}
+	return nil
+}
+
+// applyStoreSchedule mengisi OpenNow dan NextOpenAt pada store
+func applyStoreSchedule(store *api.Store, now time.Time) {
+	store.OpenNow = storeOpenAt(store, now)
+	store.NextOpenAt = nil
+	if !store.OpenNow {
+		store.NextOpenAt = storeNextOpenAt(store, now)
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+)
+
+type storeScheduleService struct {
+	db *gorm.DB
+}
+
+func NewStoreScheduleService(db *gorm.DB) api.StoreScheduleService {
+	return &storeScheduleService{db: db}
+}
+
+// SetOpeningHours mengganti seluruh jadwal mingguan toko
+func (s *storeScheduleService) SetOpeningHours(ctx context.Context, storeID string, hours []api.StoreOpeningHour) ([]api.StoreOpeningHour, error) {
+	for _, h := range hours {
+		if h.DayOfWeek < 0 || h.DayOfWeek > 6 {
+			return nil, &api.ValidationError{Message: "day_of_week must be between 0 (Sunday) and 6 (Saturday)"}
+		}
+		if err := validateShift(h.OpensAt, h.ClosesAt); err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var store api.Store
+		if err := tx.Where("id = ?", storeID).First(&store).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("store_id = ?", storeID).Delete(&api.StoreOpeningHour{}).Error; err != nil {
+			return err
+		}
+		for i := range hours {
+			hours[i].ID = 0
+			hours[i].StoreID = storeID
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		return tx.Create(&hours).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
+
+func (s *storeScheduleService) ListClosures(ctx context.Context, storeID string) ([]api.StoreClosure, error) {
+	var closures []api.StoreClosure
+	err := s.db.WithContext(ctx).Where("store_id = ? AND date >= ?", storeID, time.Now().AddDate(0, 0, -1).Format("2006-01-02")).
+		Order("date").
+		Find(&closures).Error
+	if err != nil {
+		return nil, err
+	}
+	return closures, nil
+}
+
+func (s *storeScheduleService) AddClosure(ctx context.Context, closure *api.StoreClosure) error {
+	if _, err := time.Parse("2006-01-02", closure.Date); err != nil {
+		return &api.ValidationError{Message: "date must be in YYYY-MM-DD format"}
+	}
+	if closure.OpensAt != "" || closure.ClosesAt != "" {
+		if err := validateShift(closure.OpensAt, closure.ClosesAt); err != nil {
+			return err
+		}
+	}
+
+	var existing int64
+	err := s.db.WithContext(ctx).Model(&api.StoreClosure{}).
+		Where("store_id = ? AND date = ?", closure.StoreID, closure.Date).
+		Count(&existing).Error
+	if err != nil {
+		return err
+	}
+	if existing > 0 {
+		return &api.ValidationError{Message: fmt.Sprintf("store already has an exception on %s", closure.Date)}
+	}
+
+	return s.db.WithContext(ctx).Create(closure).Error
+}
+
+func (s *storeScheduleService) DeleteClosure(ctx context.Context, storeID string, id int) error {
+	return s.db.WithContext(ctx).Where("store_id = ? AND id = ?", storeID, id).Delete(&api.StoreClosure{}).Error
+}
+
+func validateShift(opens, closes string) error {
+	// Jam buka dan tutup yang sama berarti buka 24 jam mulai jam tersebut
+	if _, err := parseClock(opens); err != nil {
+		return &api.ValidationError{Message: err.Error()}
+	}
+	if _, err := parseClock(closes); err != nil {
+		return &api.ValidationError{Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func scheduledStore() *api.Store {
+	return &api.Store{
+		Timezone: "Asia/Makassar",
+		OpeningHours: []api.StoreOpeningHour{
+			// Selasa: dua shift, Jumat: shift malam sampai jam 2
+			{DayOfWeek: 2, OpensAt: "10:00", ClosesAt: "14:00"},
+			{DayOfWeek: 2, OpensAt: "17:00", ClosesAt: "21:00"},
+			{DayOfWeek: 5, OpensAt: "18:00", ClosesAt: "02:00"},
+		},
+		Closures: []api.StoreClosure{
+			{Date: "2024-03-12", Reason: "Nyepi"},
+		},
+	}
+}
+
+func TestStoreOpenAt(t *testing.T) {
+	wita := storeLocation("Asia/Makassar")
+	at := func(day, hour, min int) time.Time { return time.Date(2024, 3, day, hour, min, 0, 0, wita) }
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{name: "Tuesday first shift", at: at(5, 11, 0), expected: true},
+		{name: "Tuesday between shifts", at: at(5, 15, 0), expected: false},
+		{name: "Tuesday second shift", at: at(5, 20, 59), expected: true},
+		{name: "Friday late night shift", at: at(8, 23, 0), expected: true},
+		{name: "After midnight on Saturday", at: at(9, 1, 30), expected: true},
+		{name: "Saturday morning", at: at(9, 2, 0), expected: false},
+		{name: "Closed for holiday", at: at(12, 11, 0), expected: false},
+		{name: "Same instant in WIB", at: time.Date(2024, 3, 5, 10, 0, 0, 0, storeLocation("Asia/Jakarta")), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, storeOpenAt(scheduledStore(), tt.at))
+		})
+	}
+}
+
+func TestStoreNextOpenAt(t *testing.T) {
+	wita := storeLocation("Asia/Makassar")
+
+	next := storeNextOpenAt(scheduledStore(), time.Date(2024, 3, 5, 15, 0, 0, 0, wita))
+	assert.Equal(t, time.Date(2024, 3, 5, 17, 0, 0, 0, wita), next.In(wita))
+
+	// Selasa 12 Maret tutup, berikutnya Jumat 15 Maret
+	next = storeNextOpenAt(scheduledStore(), time.Date(2024, 3, 11, 12, 0, 0, 0, wita))
+	assert.Equal(t, time.Date(2024, 3, 15, 18, 0, 0, 0, wita), next.In(wita))
+}
+
+func TestStoreWithoutHoursIsAlwaysOpen(t *testing.T) {
+	assert.True(t, storeOpenAt(&api.Store{}, time.Now()))
+}
+
+func TestNormalizeTimezone(t *testing.T) {
+	tz, err := normalizeTimezone("wita")
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Makassar", tz)
+
+	_, err = normalizeTimezone("Mars/Olympus")
+	assert.Error(t, err)
+}
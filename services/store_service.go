@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/seleraseblak/backend/api"
@@ -18,32 +20,49 @@ func NewStoreService(db *gorm.DB) api.StoreService {
 // Implement api.StoreService interface methods
 
 // Add interface implementations
-func (s *storeService) CreateStore(store *api.Store) error {
+func (s *storeService) CreateStore(ctx context.Context, store *api.Store) error {
+	if err := validateStoreLocation(store); err != nil {
+		return err
+	}
+	tz, err := normalizeTimezone(store.Timezone)
+	if err != nil {
+		return err
+	}
+	store.Timezone = tz
 	store.Status = api.StatusDraft
-	return s.db.Create(store).Error
+	return s.db.WithContext(ctx).Omit("OpeningHours", "Closures").Create(store).Error
 }
 
-func (s *storeService) GetStore(id string) (*api.Store, error) {
+func (s *storeService) GetStore(ctx context.Context, id string) (*api.Store, error) {
 	var store api.Store
-	err := s.db.Where("id = ?", id).First(&store).Error
+	err := preloadSchedule(s.db.WithContext(ctx)).Where("id = ?", id).First(&store).Error
 	if err != nil {
 		return nil, err
 	}
+	applyStoreSchedule(&store, time.Now())
 	return &store, nil
 }
 
-func (s *storeService) UpdateStore(id string, store *api.Store) error {
+func (s *storeService) UpdateStore(ctx context.Context, id string, store *api.Store) error {
+	if err := validateStoreLocation(store); err != nil {
+		return err
+	}
+	tz, err := normalizeTimezone(store.Timezone)
+	if err != nil {
+		return err
+	}
+	store.Timezone = tz
 	store.DateUpdated = time.Now().Format(time.RFC3339)
-	return s.db.Model(&api.Store{}).Where("id = ?", id).Updates(store).Error
+	return s.db.WithContext(ctx).Model(&api.Store{}).Where("id = ?", id).Omit("OpeningHours", "Closures").Updates(store).Error
 }
 
-func (s *storeService) DeleteStore(id string) error {
-	return s.db.Model(&api.Store{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
+func (s *storeService) DeleteStore(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Model(&api.Store{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
 }
 
-func (s *storeService) ListStores(params map[string]interface{}) ([]api.Store, error) {
+func (s *storeService) ListStores(ctx context.Context, params map[string]interface{}) ([]api.Store, error) {
 	var stores []api.Store
-	query := s.db.Model(&api.Store{})
+	query := preloadSchedule(s.db.WithContext(ctx).Model(&api.Store{}))
 
 	// Tambahkan filter status jika diperlukan
 	if status, ok := params["status"].(string); ok {
@@ -54,5 +73,70 @@ func (s *storeService) ListStores(params map[string]interface{}) ([]api.Store, e
 		return nil, err
 	}
 
-	return stores, nil
+	now := time.Now()
+	openOnly, _ := params["open_now"].(bool)
+	result := make([]api.Store, 0, len(stores))
+	for _, store := range stores {
+		applyStoreSchedule(&store, now)
+		if openOnly && !store.OpenNow {
+			continue
+		}
+		result = append(result, store)
+	}
+
+	return result, nil
+}
+
+// ListNearbyStores mencari toko published dalam radius, diurutkan dari yang terdekat.
+// Bounding box menyaring kandidat lewat index latitude/longitude, haversine menghitung jarak sebenarnya.
+func (s *storeService) ListNearbyStores(ctx context.Context, lat, lng, radiusKm float64, params map[string]interface{}) ([]api.Store, error) {
+	if err := validateCoordinates(lat, lng); err != nil {
+		return nil, err
+	}
+	if radiusKm <= 0 || radiusKm > maxNearbyRadiusKm {
+		return nil, &api.ValidationError{Message: fmt.Sprintf("radius must be between 0 and %v km", maxNearbyRadiusKm)}
+	}
+
+	box := boundingBoxAround(lat, lng, radiusKm)
+	var stores []api.Store
+	query := preloadSchedule(s.db.WithContext(ctx).Model(&api.Store{})).
+		Select(`"Store".*, `+haversineSQL+` AS distance_km`, lat, lat, lng).
+		Where("status = ?", api.StatusPublished).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", box.MinLat, box.MaxLat, box.MinLng, box.MaxLng).
+		Where(haversineSQL+" <= ?", lat, lat, lng, radiusKm).
+		Order("distance_km")
+
+	// open_now dihitung di Go, jadi limit baru dipakai setelah toko yang tutup dibuang
+	openOnly, _ := params["open_now"].(bool)
+	limit, _ := params["limit"].(int)
+	if limit > 0 && !openOnly {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&stores).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]api.Store, 0, len(stores))
+	for _, store := range stores {
+		applyStoreSchedule(&store, now)
+		if openOnly && !store.OpenNow {
+			continue
+		}
+		result = append(result, store)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// preloadSchedule memuat jam buka dan penutupan yang belum lewat
+func preloadSchedule(db *gorm.DB) *gorm.DB {
+	// Mundur dua hari supaya shift malam dan perbedaan zona waktu tetap terhitung
+	since := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+	return db.Preload("OpeningHours", func(db *gorm.DB) *gorm.DB {
+		return db.Order("day_of_week, opens_at")
+	}).Preload("Closures", "date >= ?", since)
 }
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"github.com/seleraseblak/backend/api"
 	"gorm.io/gorm"
 )
@@ -13,30 +15,30 @@ func NewToppingService(db *gorm.DB) api.ToppingService {
 	return &toppingService{db: db}
 }
 
-func (s *toppingService) GetToppings() ([]api.Topping, error) {
+func (s *toppingService) GetToppings(ctx context.Context) ([]api.Topping, error) {
 	var toppings []api.Topping
-	if err := s.db.Find(&toppings).Error; err != nil {
+	if err := s.db.WithContext(ctx).Find(&toppings).Error; err != nil {
 		return nil, err
 	}
 	return toppings, nil
 }
 
-func (s *toppingService) GetTopping(id int) (*api.Topping, error) {
+func (s *toppingService) GetTopping(ctx context.Context, id int) (*api.Topping, error) {
 	var topping api.Topping
-	if err := s.db.First(&topping, id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&topping, id).Error; err != nil {
 		return nil, err
 	}
 	return &topping, nil
 }
 
-func (s *toppingService) CreateTopping(topping *api.Topping) error {
-	return s.db.Create(topping).Error
+func (s *toppingService) CreateTopping(ctx context.Context, topping *api.Topping) error {
+	return s.db.WithContext(ctx).Create(topping).Error
 }
 
-func (s *toppingService) UpdateTopping(id int, topping *api.Topping) error {
-	return s.db.Model(&api.Topping{}).Where("id = ?", id).Updates(topping).Error
+func (s *toppingService) UpdateTopping(ctx context.Context, id int, topping *api.Topping) error {
+	return s.db.WithContext(ctx).Model(&api.Topping{}).Where("id = ?", id).Updates(topping).Error
 }
 
-func (s *toppingService) DeleteTopping(id int) error {
-	return s.db.Delete(&api.Topping{}, id).Error
+func (s *toppingService) DeleteTopping(ctx context.Context, id int) error {
+	return s.db.WithContext(ctx).Delete(&api.Topping{}, id).Error
 }
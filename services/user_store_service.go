@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+)
+
+type userStoreService struct {
+	db *gorm.DB
+}
+
+func NewUserStoreService(db *gorm.DB) api.UserStoreService {
+	return &userStoreService{db: db}
+}
+
+// AssignUserToStore memberi user peran di toko. Akun user sendiri dikelola Directus;
+// yang disimpan di sini hanya ID-nya, dan jika sudah punya peran di toko itu perannya diganti.
+func (s *userStoreService) AssignUserToStore(ctx context.Context, userStore *api.UserStore) error {
+	if _, err := uuid.Parse(userStore.UserID); err != nil {
+		return &api.ValidationError{Message: "user_id must be a UUID"}
+	}
+	if userStore.RoleInStore == "" {
+		return &api.ValidationError{Message: "role_in_store is required"}
+	}
+	if userStore.Status == "" {
+		userStore.Status = api.StatusPublished
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var store api.Store
+		if err := tx.Select("id").Where("id = ?", userStore.StoreID).First(&store).Error; err != nil {
+			return err
+		}
+
+		var existing api.UserStore
+		err := tx.Where("user_id = ? AND store_id = ?", userStore.UserID, userStore.StoreID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(userStore).Error
+		}
+		if err != nil {
+			return err
+		}
+		userStore.ID = existing.ID
+		return tx.Model(&existing).Select("role_in_store", "status").Updates(userStore).Error
+	})
+}
+
+func (s *userStoreService) RemoveUserFromStore(ctx context.Context, userID, storeID string) error {
+	return s.db.WithContext(ctx).Where("user_id = ? AND store_id = ?", userID, storeID).Delete(&api.UserStore{}).Error
+}
+
+func (s *userStoreService) GetUserStores(ctx context.Context, userID string) ([]api.UserStore, error) {
+	var userStores []api.UserStore
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&userStores).Error; err != nil {
+		return nil, err
+	}
+	return userStores, nil
+}
+
+func (s *userStoreService) GetStoreUsers(ctx context.Context, storeID string) ([]api.UserStore, error) {
+	var userStores []api.UserStore
+	if err := s.db.WithContext(ctx).Where("store_id = ?", storeID).Order("id").Find(&userStores).Error; err != nil {
+		return nil, err
+	}
+	return userStores, nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+
+	"github.com/seleraseblak/backend/api"
+	"gorm.io/gorm"
+)
+
+type voucherService struct {
+	db *gorm.DB
+}
+
+func NewVoucherService(db *gorm.DB) api.VoucherService {
+	return &voucherService{db: db}
+}
+
+func (s *voucherService) CreateVoucher(ctx context.Context, voucher *api.Voucher) error {
+	voucher.Code = normalizeVoucherCode(voucher.Code)
+	if voucher.Code == "" {
+		return &api.ValidationError{Message: "voucher code is required"}
+	}
+	if voucher.PromotionID == 0 {
+		return &api.ValidationError{Message: "promotion_id is required"}
+	}
+
+	var promotion api.Promotion
+	if err := s.db.WithContext(ctx).First(&promotion, voucher.PromotionID).Error; err != nil {
+		return &api.ValidationError{Message: "promotion not found"}
+	}
+
+	voucher.Status = api.StatusDraft
+	return s.db.WithContext(ctx).Omit("Promotion").Create(voucher).Error
+}
+
+func (s *voucherService) GetVoucherByCode(ctx context.Context, code string) (*api.Voucher, error) {
+	var voucher api.Voucher
+	err := s.db.WithContext(ctx).Preload("Promotion").Where("code = ?", normalizeVoucherCode(code)).First(&voucher).Error
+	if err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (s *voucherService) UpdateVoucher(ctx context.Context, id int, voucher *api.Voucher) error {
+	voucher.Code = normalizeVoucherCode(voucher.Code)
+	return s.db.WithContext(ctx).Model(&api.Voucher{}).Where("id = ?", id).Omit("Promotion").Updates(voucher).Error
+}
+
+func (s *voucherService) DeleteVoucher(ctx context.Context, id int) error {
+	return s.db.WithContext(ctx).Model(&api.Voucher{}).Where("id = ?", id).Update("status", api.StatusArchived).Error
+}
+
+func (s *voucherService) ListVouchers(ctx context.Context, params map[string]interface{}) ([]api.Voucher, error) {
+	var vouchers []api.Voucher
+	query := s.db.WithContext(ctx).Model(&api.Voucher{}).Preload("Promotion")
+
+	if status, ok := params["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if promotionID, ok := params["promotion_id"].(int); ok && promotionID > 0 {
+		query = query.Where("promotion_id = ?", promotionID)
+	}
+
+	if err := query.Find(&vouchers).Error; err != nil {
+		return nil, err
+	}
+	return vouchers, nil
+}
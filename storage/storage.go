@@ -0,0 +1,46 @@
+// Package storage menyimpan file upload (foto produk) di filesystem lokal atau
+// object storage yang kompatibel dengan S3.
+package storage
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+)
+
+// Storage adalah backend penyimpanan file. Key selalu memakai pemisah "/".
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	// URL mengembalikan URL publik untuk key
+	URL(key string) string
+}
+
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// cleanKey menolak key kosong, absolut, atau yang keluar dari root penyimpanan
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if key == "" || cleaned == "" || cleaned != key || strings.HasPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// KeyFromURL mengembalikan key jika url disimpan oleh s
+func KeyFromURL(s Storage, url string) (string, bool) {
+	prefix := s.URL("")
+	if url == "" || !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	key := strings.TrimPrefix(url, prefix)
+	if _, err := cleanKey(key); err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
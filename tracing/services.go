@@ -0,0 +1,730 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/seleraseblak/backend/api"
+)
+
+// Decorator berikut membuat satu span per method service (misalnya ProductService.ListProducts).
+// Query SQL di dalamnya menjadi span anak lewat plugin GORM, jadi waktu setiap query dan
+// preload terlihat terpisah. Dipasang paling luar di cmd/app.go supaya cache hit juga tercatat.
+
+type tracedSpicyLevelService struct {
+	next api.SpicyLevelService
+}
+
+func SpicyLevelService(next api.SpicyLevelService) api.SpicyLevelService {
+	return &tracedSpicyLevelService{next: next}
+}
+
+func (s *tracedSpicyLevelService) GetSpicyLevels(ctx context.Context) ([]api.SpicyLevel, error) {
+	ctx, span := start(ctx, "SpicyLevelService.GetSpicyLevels")
+	result, err := s.next.GetSpicyLevels(ctx)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedSpicyLevelService) GetSpicyLevel(ctx context.Context, id string) (*api.SpicyLevel, error) {
+	ctx, span := start(ctx, "SpicyLevelService.GetSpicyLevel")
+	result, err := s.next.GetSpicyLevel(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+type tracedStoreService struct {
+	next api.StoreService
+}
+
+func StoreService(next api.StoreService) api.StoreService {
+	return &tracedStoreService{next: next}
+}
+
+func (s *tracedStoreService) CreateStore(ctx context.Context, store *api.Store) error {
+	ctx, span := start(ctx, "StoreService.CreateStore")
+	err := s.next.CreateStore(ctx, store)
+	end(span, err)
+	return err
+}
+
+func (s *tracedStoreService) GetStore(ctx context.Context, id string) (*api.Store, error) {
+	ctx, span := start(ctx, "StoreService.GetStore")
+	result, err := s.next.GetStore(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedStoreService) UpdateStore(ctx context.Context, id string, store *api.Store) error {
+	ctx, span := start(ctx, "StoreService.UpdateStore")
+	err := s.next.UpdateStore(ctx, id, store)
+	end(span, err)
+	return err
+}
+
+func (s *tracedStoreService) DeleteStore(ctx context.Context, id string) error {
+	ctx, span := start(ctx, "StoreService.DeleteStore")
+	err := s.next.DeleteStore(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedStoreService) ListStores(ctx context.Context, params map[string]interface{}) ([]api.Store, error) {
+	ctx, span := start(ctx, "StoreService.ListStores")
+	result, err := s.next.ListStores(ctx, params)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedStoreService) ListNearbyStores(ctx context.Context, lat, lng, radiusKm float64, params map[string]interface{}) ([]api.Store, error) {
+	ctx, span := start(ctx, "StoreService.ListNearbyStores")
+	result, err := s.next.ListNearbyStores(ctx, lat, lng, radiusKm, params)
+	end(span, err)
+	return result, err
+}
+
+type tracedStoreScheduleService struct {
+	next api.StoreScheduleService
+}
+
+func StoreScheduleService(next api.StoreScheduleService) api.StoreScheduleService {
+	return &tracedStoreScheduleService{next: next}
+}
+
+func (s *tracedStoreScheduleService) SetOpeningHours(ctx context.Context, storeID string, hours []api.StoreOpeningHour) ([]api.StoreOpeningHour, error) {
+	ctx, span := start(ctx, "StoreScheduleService.SetOpeningHours")
+	result, err := s.next.SetOpeningHours(ctx, storeID, hours)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedStoreScheduleService) ListClosures(ctx context.Context, storeID string) ([]api.StoreClosure, error) {
+	ctx, span := start(ctx, "StoreScheduleService.ListClosures")
+	result, err := s.next.ListClosures(ctx, storeID)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedStoreScheduleService) AddClosure(ctx context.Context, closure *api.StoreClosure) error {
+	ctx, span := start(ctx, "StoreScheduleService.AddClosure")
+	err := s.next.AddClosure(ctx, closure)
+	end(span, err)
+	return err
+}
+
+func (s *tracedStoreScheduleService) DeleteClosure(ctx context.Context, storeID string, id int) error {
+	ctx, span := start(ctx, "StoreScheduleService.DeleteClosure")
+	err := s.next.DeleteClosure(ctx, storeID, id)
+	end(span, err)
+	return err
+}
+
+type tracedDeliveryService struct {
+	next api.DeliveryService
+}
+
+func DeliveryService(next api.DeliveryService) api.DeliveryService {
+	return &tracedDeliveryService{next: next}
+}
+
+func (s *tracedDeliveryService) ListDeliveryZones(ctx context.Context, storeID string) ([]api.DeliveryZone, error) {
+	ctx, span := start(ctx, "DeliveryService.ListDeliveryZones")
+	result, err := s.next.ListDeliveryZones(ctx, storeID)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedDeliveryService) CreateDeliveryZone(ctx context.Context, zone *api.DeliveryZone) error {
+	ctx, span := start(ctx, "DeliveryService.CreateDeliveryZone")
+	err := s.next.CreateDeliveryZone(ctx, zone)
+	end(span, err)
+	return err
+}
+
+func (s *tracedDeliveryService) UpdateDeliveryZone(ctx context.Context, storeID string, id int, zone *api.DeliveryZone) error {
+	ctx, span := start(ctx, "DeliveryService.UpdateDeliveryZone")
+	err := s.next.UpdateDeliveryZone(ctx, storeID, id, zone)
+	end(span, err)
+	return err
+}
+
+func (s *tracedDeliveryService) DeleteDeliveryZone(ctx context.Context, storeID string, id int) error {
+	ctx, span := start(ctx, "DeliveryService.DeleteDeliveryZone")
+	err := s.next.DeleteDeliveryZone(ctx, storeID, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedDeliveryService) CheckDelivery(ctx context.Context, storeID string, lat, lng float64, subtotal int) (*api.DeliveryQuote, error) {
+	ctx, span := start(ctx, "DeliveryService.CheckDelivery")
+	result, err := s.next.CheckDelivery(ctx, storeID, lat, lng, subtotal)
+	end(span, err)
+	return result, err
+}
+
+type tracedPhotoService struct {
+	next api.PhotoService
+}
+
+func PhotoService(next api.PhotoService) api.PhotoService {
+	return &tracedPhotoService{next: next}
+}
+
+func (s *tracedPhotoService) UploadProductPhoto(ctx context.Context, productID int, upload api.PhotoUpload) (*api.Product, error) {
+	ctx, span := start(ctx, "PhotoService.UploadProductPhoto")
+	result, err := s.next.UploadProductPhoto(ctx, productID, upload)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedPhotoService) UploadProductMasterPhoto(ctx context.Context, id string, upload api.PhotoUpload) (*api.ProductMaster, error) {
+	ctx, span := start(ctx, "PhotoService.UploadProductMasterPhoto")
+	result, err := s.next.UploadProductMasterPhoto(ctx, id, upload)
+	end(span, err)
+	return result, err
+}
+
+type tracedProductService struct {
+	next api.ProductService
+}
+
+func ProductService(next api.ProductService) api.ProductService {
+	return &tracedProductService{next: next}
+}
+
+func (s *tracedProductService) CreateProduct(ctx context.Context, product *api.Product) error {
+	ctx, span := start(ctx, "ProductService.CreateProduct")
+	err := s.next.CreateProduct(ctx, product)
+	end(span, err)
+	return err
+}
+
+func (s *tracedProductService) GetProduct(ctx context.Context, id int) (*api.Product, error) {
+	ctx, span := start(ctx, "ProductService.GetProduct")
+	result, err := s.next.GetProduct(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedProductService) UpdateProduct(ctx context.Context, id int, product *api.Product) error {
+	ctx, span := start(ctx, "ProductService.UpdateProduct")
+	err := s.next.UpdateProduct(ctx, id, product)
+	end(span, err)
+	return err
+}
+
+func (s *tracedProductService) DeleteProduct(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "ProductService.DeleteProduct")
+	err := s.next.DeleteProduct(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedProductService) ListProducts(ctx context.Context, storeID string, params map[string]interface{}) ([]api.Product, error) {
+	ctx, span := start(ctx, "ProductService.ListProducts")
+	result, err := s.next.ListProducts(ctx, storeID, params)
+	end(span, err)
+	return result, err
+}
+
+type tracedProductMasterService struct {
+	next api.ProductMasterService
+}
+
+func ProductMasterService(next api.ProductMasterService) api.ProductMasterService {
+	return &tracedProductMasterService{next: next}
+}
+
+func (s *tracedProductMasterService) CreateProductMaster(ctx context.Context, product *api.ProductMaster) error {
+	ctx, span := start(ctx, "ProductMasterService.CreateProductMaster")
+	err := s.next.CreateProductMaster(ctx, product)
+	end(span, err)
+	return err
+}
+
+func (s *tracedProductMasterService) GetProductMaster(ctx context.Context, id string) (*api.ProductMaster, error) {
+	ctx, span := start(ctx, "ProductMasterService.GetProductMaster")
+	result, err := s.next.GetProductMaster(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedProductMasterService) UpdateProductMaster(ctx context.Context, id string, product *api.ProductMaster) error {
+	ctx, span := start(ctx, "ProductMasterService.UpdateProductMaster")
+	err := s.next.UpdateProductMaster(ctx, id, product)
+	end(span, err)
+	return err
+}
+
+func (s *tracedProductMasterService) DeleteProductMaster(ctx context.Context, id string) error {
+	ctx, span := start(ctx, "ProductMasterService.DeleteProductMaster")
+	err := s.next.DeleteProductMaster(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedProductMasterService) ListProductMasters(ctx context.Context, params map[string]interface{}) ([]api.ProductMaster, error) {
+	ctx, span := start(ctx, "ProductMasterService.ListProductMasters")
+	result, err := s.next.ListProductMasters(ctx, params)
+	end(span, err)
+	return result, err
+}
+
+type tracedMenuService struct {
+	next api.MenuService
+}
+
+func MenuService(next api.MenuService) api.MenuService {
+	return &tracedMenuService{next: next}
+}
+
+func (s *tracedMenuService) GetStoreMenu(ctx context.Context, storeID string) (*api.Menu, error) {
+	ctx, span := start(ctx, "MenuService.GetStoreMenu")
+	result, err := s.next.GetStoreMenu(ctx, storeID)
+	end(span, err)
+	return result, err
+}
+
+type tracedCategoryService struct {
+	next api.CategoryService
+}
+
+func CategoryService(next api.CategoryService) api.CategoryService {
+	return &tracedCategoryService{next: next}
+}
+
+func (s *tracedCategoryService) ListCategories(ctx context.Context, params map[string]interface{}) ([]api.Category, error) {
+	ctx, span := start(ctx, "CategoryService.ListCategories")
+	result, err := s.next.ListCategories(ctx, params)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedCategoryService) GetCategory(ctx context.Context, id int) (*api.Category, error) {
+	ctx, span := start(ctx, "CategoryService.GetCategory")
+	result, err := s.next.GetCategory(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedCategoryService) CreateCategory(ctx context.Context, category *api.Category) error {
+	ctx, span := start(ctx, "CategoryService.CreateCategory")
+	err := s.next.CreateCategory(ctx, category)
+	end(span, err)
+	return err
+}
+
+func (s *tracedCategoryService) UpdateCategory(ctx context.Context, id int, category *api.Category) error {
+	ctx, span := start(ctx, "CategoryService.UpdateCategory")
+	err := s.next.UpdateCategory(ctx, id, category)
+	end(span, err)
+	return err
+}
+
+func (s *tracedCategoryService) DeleteCategory(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "CategoryService.DeleteCategory")
+	err := s.next.DeleteCategory(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedCategoryService) ListCategoryProducts(ctx context.Context, slug string, params map[string]interface{}) (*api.Category, []api.ProductMaster, error) {
+	ctx, span := start(ctx, "CategoryService.ListCategoryProducts")
+	first, second, err := s.next.ListCategoryProducts(ctx, slug, params)
+	end(span, err)
+	return first, second, err
+}
+
+type tracedCatalogService struct {
+	next api.CatalogService
+}
+
+func CatalogService(next api.CatalogService) api.CatalogService {
+	return &tracedCatalogService{next: next}
+}
+
+func (s *tracedCatalogService) ExportCatalog(ctx context.Context) (*api.CatalogExport, error) {
+	ctx, span := start(ctx, "CatalogService.ExportCatalog")
+	result, err := s.next.ExportCatalog(ctx)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedCatalogService) ImportCatalog(ctx context.Context, catalog *api.CatalogExport) (*api.CatalogImportResult, error) {
+	ctx, span := start(ctx, "CatalogService.ImportCatalog")
+	result, err := s.next.ImportCatalog(ctx, catalog)
+	end(span, err)
+	return result, err
+}
+
+type tracedUserStoreService struct {
+	next api.UserStoreService
+}
+
+func UserStoreService(next api.UserStoreService) api.UserStoreService {
+	return &tracedUserStoreService{next: next}
+}
+
+func (s *tracedUserStoreService) AssignUserToStore(ctx context.Context, userStore *api.UserStore) error {
+	ctx, span := start(ctx, "UserStoreService.AssignUserToStore")
+	err := s.next.AssignUserToStore(ctx, userStore)
+	end(span, err)
+	return err
+}
+
+func (s *tracedUserStoreService) RemoveUserFromStore(ctx context.Context, userID, storeID string) error {
+	ctx, span := start(ctx, "UserStoreService.RemoveUserFromStore")
+	err := s.next.RemoveUserFromStore(ctx, userID, storeID)
+	end(span, err)
+	return err
+}
+
+func (s *tracedUserStoreService) GetUserStores(ctx context.Context, userID string) ([]api.UserStore, error) {
+	ctx, span := start(ctx, "UserStoreService.GetUserStores")
+	result, err := s.next.GetUserStores(ctx, userID)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedUserStoreService) GetStoreUsers(ctx context.Context, storeID string) ([]api.UserStore, error) {
+	ctx, span := start(ctx, "UserStoreService.GetStoreUsers")
+	result, err := s.next.GetStoreUsers(ctx, storeID)
+	end(span, err)
+	return result, err
+}
+
+type tracedToppingService struct {
+	next api.ToppingService
+}
+
+func ToppingService(next api.ToppingService) api.ToppingService {
+	return &tracedToppingService{next: next}
+}
+
+func (s *tracedToppingService) GetToppings(ctx context.Context) ([]api.Topping, error) {
+	ctx, span := start(ctx, "ToppingService.GetToppings")
+	result, err := s.next.GetToppings(ctx)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedToppingService) GetTopping(ctx context.Context, id int) (*api.Topping, error) {
+	ctx, span := start(ctx, "ToppingService.GetTopping")
+	result, err := s.next.GetTopping(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedToppingService) CreateTopping(ctx context.Context, topping *api.Topping) error {
+	ctx, span := start(ctx, "ToppingService.CreateTopping")
+	err := s.next.CreateTopping(ctx, topping)
+	end(span, err)
+	return err
+}
+
+func (s *tracedToppingService) UpdateTopping(ctx context.Context, id int, topping *api.Topping) error {
+	ctx, span := start(ctx, "ToppingService.UpdateTopping")
+	err := s.next.UpdateTopping(ctx, id, topping)
+	end(span, err)
+	return err
+}
+
+func (s *tracedToppingService) DeleteTopping(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "ToppingService.DeleteTopping")
+	err := s.next.DeleteTopping(ctx, id)
+	end(span, err)
+	return err
+}
+
+type tracedModifierService struct {
+	next api.ModifierService
+}
+
+func ModifierService(next api.ModifierService) api.ModifierService {
+	return &tracedModifierService{next: next}
+}
+
+func (s *tracedModifierService) GetModifierGroups(ctx context.Context, productID int) ([]api.ModifierGroup, error) {
+	ctx, span := start(ctx, "ModifierService.GetModifierGroups")
+	result, err := s.next.GetModifierGroups(ctx, productID)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedModifierService) SetModifierGroups(ctx context.Context, productID int, groups []api.ModifierGroup) ([]api.ModifierGroup, error) {
+	ctx, span := start(ctx, "ModifierService.SetModifierGroups")
+	result, err := s.next.SetModifierGroups(ctx, productID, groups)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedModifierService) ValidateConfiguration(ctx context.Context, productID int, selections []api.ModifierSelection) (*api.ModifierConfiguration, error) {
+	ctx, span := start(ctx, "ModifierService.ValidateConfiguration")
+	result, err := s.next.ValidateConfiguration(ctx, productID, selections)
+	end(span, err)
+	return result, err
+}
+
+type tracedProductToppingService struct {
+	next api.ProductToppingService
+}
+
+func ProductToppingService(next api.ProductToppingService) api.ProductToppingService {
+	return &tracedProductToppingService{next: next}
+}
+
+func (s *tracedProductToppingService) GetProductToppings(ctx context.Context) ([]api.ProductTopping, error) {
+	ctx, span := start(ctx, "ProductToppingService.GetProductToppings")
+	result, err := s.next.GetProductToppings(ctx)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedProductToppingService) GetProductToppingsByProduct(ctx context.Context, productID int) ([]api.ProductTopping, error) {
+	ctx, span := start(ctx, "ProductToppingService.GetProductToppingsByProduct")
+	result, err := s.next.GetProductToppingsByProduct(ctx, productID)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedProductToppingService) GetProductToppingsByTopping(ctx context.Context, toppingID int) ([]api.ProductTopping, error) {
+	ctx, span := start(ctx, "ProductToppingService.GetProductToppingsByTopping")
+	result, err := s.next.GetProductToppingsByTopping(ctx, toppingID)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedProductToppingService) CreateProductTopping(ctx context.Context, productTopping *api.ProductTopping) error {
+	ctx, span := start(ctx, "ProductToppingService.CreateProductTopping")
+	err := s.next.CreateProductTopping(ctx, productTopping)
+	end(span, err)
+	return err
+}
+
+func (s *tracedProductToppingService) DeleteProductTopping(ctx context.Context, productID, toppingID int) error {
+	ctx, span := start(ctx, "ProductToppingService.DeleteProductTopping")
+	err := s.next.DeleteProductTopping(ctx, productID, toppingID)
+	end(span, err)
+	return err
+}
+
+type tracedOrderService struct {
+	next api.OrderService
+}
+
+func OrderService(next api.OrderService) api.OrderService {
+	return &tracedOrderService{next: next}
+}
+
+func (s *tracedOrderService) QuoteOrder(ctx context.Context, req *api.OrderRequest) (*api.OrderQuote, error) {
+	ctx, span := start(ctx, "OrderService.QuoteOrder")
+	result, err := s.next.QuoteOrder(ctx, req)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedOrderService) CreateOrder(ctx context.Context, req *api.OrderRequest) (*api.Order, error) {
+	ctx, span := start(ctx, "OrderService.CreateOrder")
+	result, err := s.next.CreateOrder(ctx, req)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedOrderService) GetOrder(ctx context.Context, id int) (*api.Order, error) {
+	ctx, span := start(ctx, "OrderService.GetOrder")
+	result, err := s.next.GetOrder(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedOrderService) UpdateOrderStatus(ctx context.Context, id int, status string) (*api.Order, error) {
+	ctx, span := start(ctx, "OrderService.UpdateOrderStatus")
+	result, err := s.next.UpdateOrderStatus(ctx, id, status)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedOrderService) ListOrders(ctx context.Context, storeID string, params map[string]interface{}) ([]api.Order, error) {
+	ctx, span := start(ctx, "OrderService.ListOrders")
+	result, err := s.next.ListOrders(ctx, storeID, params)
+	end(span, err)
+	return result, err
+}
+
+type tracedPromotionService struct {
+	next api.PromotionService
+}
+
+func PromotionService(next api.PromotionService) api.PromotionService {
+	return &tracedPromotionService{next: next}
+}
+
+func (s *tracedPromotionService) CreatePromotion(ctx context.Context, promotion *api.Promotion) error {
+	ctx, span := start(ctx, "PromotionService.CreatePromotion")
+	err := s.next.CreatePromotion(ctx, promotion)
+	end(span, err)
+	return err
+}
+
+func (s *tracedPromotionService) GetPromotion(ctx context.Context, id int) (*api.Promotion, error) {
+	ctx, span := start(ctx, "PromotionService.GetPromotion")
+	result, err := s.next.GetPromotion(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedPromotionService) UpdatePromotion(ctx context.Context, id int, promotion *api.Promotion) error {
+	ctx, span := start(ctx, "PromotionService.UpdatePromotion")
+	err := s.next.UpdatePromotion(ctx, id, promotion)
+	end(span, err)
+	return err
+}
+
+func (s *tracedPromotionService) DeletePromotion(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "PromotionService.DeletePromotion")
+	err := s.next.DeletePromotion(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedPromotionService) ListPromotions(ctx context.Context, params map[string]interface{}) ([]api.Promotion, error) {
+	ctx, span := start(ctx, "PromotionService.ListPromotions")
+	result, err := s.next.ListPromotions(ctx, params)
+	end(span, err)
+	return result, err
+}
+
+type tracedVoucherService struct {
+	next api.VoucherService
+}
+
+func VoucherService(next api.VoucherService) api.VoucherService {
+	return &tracedVoucherService{next: next}
+}
+
+func (s *tracedVoucherService) CreateVoucher(ctx context.Context, voucher *api.Voucher) error {
+	ctx, span := start(ctx, "VoucherService.CreateVoucher")
+	err := s.next.CreateVoucher(ctx, voucher)
+	end(span, err)
+	return err
+}
+
+func (s *tracedVoucherService) GetVoucherByCode(ctx context.Context, code string) (*api.Voucher, error) {
+	ctx, span := start(ctx, "VoucherService.GetVoucherByCode")
+	result, err := s.next.GetVoucherByCode(ctx, code)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedVoucherService) UpdateVoucher(ctx context.Context, id int, voucher *api.Voucher) error {
+	ctx, span := start(ctx, "VoucherService.UpdateVoucher")
+	err := s.next.UpdateVoucher(ctx, id, voucher)
+	end(span, err)
+	return err
+}
+
+func (s *tracedVoucherService) DeleteVoucher(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "VoucherService.DeleteVoucher")
+	err := s.next.DeleteVoucher(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedVoucherService) ListVouchers(ctx context.Context, params map[string]interface{}) ([]api.Voucher, error) {
+	ctx, span := start(ctx, "VoucherService.ListVouchers")
+	result, err := s.next.ListVouchers(ctx, params)
+	end(span, err)
+	return result, err
+}
+
+type tracedCustomerService struct {
+	next api.CustomerService
+}
+
+func CustomerService(next api.CustomerService) api.CustomerService {
+	return &tracedCustomerService{next: next}
+}
+
+func (s *tracedCustomerService) CreateCustomer(ctx context.Context, customer *api.Customer) error {
+	ctx, span := start(ctx, "CustomerService.CreateCustomer")
+	err := s.next.CreateCustomer(ctx, customer)
+	end(span, err)
+	return err
+}
+
+func (s *tracedCustomerService) GetCustomer(ctx context.Context, id int) (*api.Customer, error) {
+	ctx, span := start(ctx, "CustomerService.GetCustomer")
+	result, err := s.next.GetCustomer(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedCustomerService) UpdateCustomer(ctx context.Context, id int, customer *api.Customer) error {
+	ctx, span := start(ctx, "CustomerService.UpdateCustomer")
+	err := s.next.UpdateCustomer(ctx, id, customer)
+	end(span, err)
+	return err
+}
+
+func (s *tracedCustomerService) ListCustomers(ctx context.Context, params map[string]interface{}) ([]api.Customer, error) {
+	ctx, span := start(ctx, "CustomerService.ListCustomers")
+	result, err := s.next.ListCustomers(ctx, params)
+	end(span, err)
+	return result, err
+}
+
+type tracedLoyaltyService struct {
+	next api.LoyaltyService
+}
+
+func LoyaltyService(next api.LoyaltyService) api.LoyaltyService {
+	return &tracedLoyaltyService{next: next}
+}
+
+func (s *tracedLoyaltyService) CreateProgram(ctx context.Context, program *api.LoyaltyProgram) error {
+	ctx, span := start(ctx, "LoyaltyService.CreateProgram")
+	err := s.next.CreateProgram(ctx, program)
+	end(span, err)
+	return err
+}
+
+func (s *tracedLoyaltyService) GetProgram(ctx context.Context, id int) (*api.LoyaltyProgram, error) {
+	ctx, span := start(ctx, "LoyaltyService.GetProgram")
+	result, err := s.next.GetProgram(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedLoyaltyService) UpdateProgram(ctx context.Context, id int, program *api.LoyaltyProgram) error {
+	ctx, span := start(ctx, "LoyaltyService.UpdateProgram")
+	err := s.next.UpdateProgram(ctx, id, program)
+	end(span, err)
+	return err
+}
+
+func (s *tracedLoyaltyService) DeleteProgram(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "LoyaltyService.DeleteProgram")
+	err := s.next.DeleteProgram(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedLoyaltyService) ListPrograms(ctx context.Context, params map[string]interface{}) ([]api.LoyaltyProgram, error) {
+	ctx, span := start(ctx, "LoyaltyService.ListPrograms")
+	result, err := s.next.ListPrograms(ctx, params)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedLoyaltyService) GetBalances(ctx context.Context, customerID int, storeID string) ([]api.LoyaltyBalance, error) {
+	ctx, span := start(ctx, "LoyaltyService.GetBalances")
+	result, err := s.next.GetBalances(ctx, customerID, storeID)
+	end(span, err)
+	return result, err
+}
+
+func (s *tracedLoyaltyService) GetHistory(ctx context.Context, customerID int, params map[string]interface{}) ([]api.LoyaltyTransaction, error) {
+	ctx, span := start(ctx, "LoyaltyService.GetHistory")
+	result, err := s.next.GetHistory(ctx, customerID, params)
+	end(span, err)
+	return result, err
+}
@@ -0,0 +1,40 @@
+// Package tracing membuat span OpenTelemetry untuk service dan query database.
+// Provider dan exporter OTLP disiapkan oleh config.InitTracing; tanpa itu semua span
+// di sini tidak dicatat (no-op) tetapi trace context tetap diteruskan.
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/seleraseblak/backend/api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const tracerName = "github.com/seleraseblak/backend"
+
+// Tracer mengambil tracer dari provider global, sehingga provider yang dipasang
+// setelah package ini dimuat tetap dipakai
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+func start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name)
+}
+
+// end menandai span gagal jika ada error. Record not found dan error validasi adalah
+// hasil normal (404/400), jadi hanya dicatat sebagai event.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		var validationErr *api.ValidationError
+		if !errors.Is(err, gorm.ErrRecordNotFound) && !errors.As(err, &validationErr) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
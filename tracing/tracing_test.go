@@ -0,0 +1,123 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seleraseblak/backend/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordSpans memasang provider yang menyimpan span di memori selama test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func attr(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+type fakeStoreService struct {
+	api.StoreService
+	db  *gorm.DB
+	err error
+}
+
+func (f *fakeStoreService) ListStores(ctx context.Context, params map[string]interface{}) ([]api.Store, error) {
+	var stores []api.Store
+	q := f.db.WithContext(ctx).Model(&api.Store{})
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	return stores, q.Find(&stores).Error
+}
+
+func (f *fakeStoreService) GetStore(ctx context.Context, id string) (*api.Store, error) {
+	return nil, f.err
+}
+
+func dryRunDB(t *testing.T) *gorm.DB {
+	// DryRun menjalankan semua callback tanpa mengirim query ke database
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(GormPlugin()))
+	return db
+}
+
+func TestServiceAndSQLSpans(t *testing.T) {
+	recorder := recordSpans(t)
+	svc := StoreService(&fakeStoreService{db: dryRunDB(t)})
+
+	ctx, root := Tracer().Start(context.Background(), "GET /stores")
+	_, err := svc.ListStores(ctx, nil)
+	require.NoError(t, err)
+	root.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	count, find, service := spans[0], spans[1], spans[2]
+	assert.Equal(t, "StoreService.ListStores", service.Name())
+	assert.Equal(t, root.SpanContext().SpanID(), service.Parent().SpanID())
+
+	// Statement yang dipakai ulang (Count lalu Find) tetap menghasilkan span bersaudara
+	for _, span := range []sdktrace.ReadOnlySpan{count, find} {
+		assert.Equal(t, "db.select Store", span.Name())
+		assert.Equal(t, service.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, "postgresql", attr(span, "db.system").AsString())
+		assert.Equal(t, "Store", attr(span, "db.collection.name").AsString())
+	}
+	assert.Contains(t, attr(count, "db.query.text").AsString(), `SELECT count(*) FROM "Store"`)
+}
+
+func TestSQLWithoutParentSpanIsNotTraced(t *testing.T) {
+	recorder := recordSpans(t)
+	var stores []api.Store
+	require.NoError(t, dryRunDB(t).Find(&stores).Error)
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestServiceSpanErrors(t *testing.T) {
+	recorder := recordSpans(t)
+	next := &fakeStoreService{}
+	svc := StoreService(next)
+	ctx := context.Background()
+
+	next.err = gorm.ErrRecordNotFound
+	_, _ = svc.GetStore(ctx, "missing")
+	next.err = &api.ValidationError{Message: "invalid id"}
+	_, _ = svc.GetStore(ctx, "bad")
+	next.err = errors.New("connection reset")
+	_, _ = svc.GetStore(ctx, "id")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code, "not found is a normal result")
+	assert.Equal(t, codes.Unset, spans[1].Status().Code, "validation errors are client errors")
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+	assert.Len(t, spans[2].Events(), 1)
+}